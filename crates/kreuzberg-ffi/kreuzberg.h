@@ -470,6 +470,58 @@ typedef struct CMetadataField {
   int32_t is_null;
 } CMetadataField;
 
+/**
+ * A rendered PDF page.
+ *
+ * # Memory Layout
+ *
+ * Field order: 1 pointer (8 bytes) + 1 usize (8 bytes) + 2 u32 (8 bytes) = 24 bytes total
+ *
+ * # Memory Management
+ *
+ * Must be freed with `kreuzberg_free_rendered_page`.
+ */
+typedef struct CRenderedPage {
+  /**
+   * RGB pixels, 3 bytes per pixel, row by row from the top left
+   */
+  uint8_t *pixels;
+  /**
+   * Length of `pixels` in bytes
+   */
+  uintptr_t pixels_len;
+  /**
+   * Image width in pixels
+   */
+  uint32_t width;
+  /**
+   * Image height in pixels
+   */
+  uint32_t height;
+} CRenderedPage;
+
+/**
+ * A batch of rendered PDF pages.
+ *
+ * # Memory Layout
+ *
+ * Field order: 1 pointer (8 bytes) + 1 usize (8 bytes) = 16 bytes total
+ *
+ * # Memory Management
+ *
+ * Must be freed with `kreuzberg_free_rendered_page_batch`, which also frees the pages.
+ */
+typedef struct CRenderedPageBatch {
+  /**
+   * Rendered pages, in the order of the requested indices
+   */
+  struct CRenderedPage *pages;
+  /**
+   * Number of pages in `pages`
+   */
+  uintptr_t count;
+} CRenderedPageBatch;
+
 /**
  * Statistics for result pool allocation tracking.
  *
@@ -743,6 +795,19 @@ int32_t kreuzberg_config_merge(ExtractionConfig *base, const ExtractionConfig *o
  */
 char *kreuzberg_load_extraction_config_from_file(const char *file_path);
 
+/**
+ * Parse a configuration file without applying defaults (returns JSON string).
+ *
+ * Unlike `kreuzberg_load_extraction_config_from_file`, the result contains only the
+ * keys the file sets, so bindings can tell explicit values apart from defaults.
+ *
+ * # Safety
+ *
+ * - `file_path` must be a valid null-terminated C string
+ * - The returned string must be freed with `kreuzberg_free_string`
+ */
+char *kreuzberg_config_file_document(const char *file_path);
+
 /**
  * Load an ExtractionConfig from a file (returns pointer to config struct).
  *
@@ -2199,6 +2264,102 @@ bool kreuzberg_clear_validators(void);
  */
 char *kreuzberg_list_validators(void);
 
+/**
+ * Render one page of a PDF document to RGB pixels.
+ *
+ * # Safety
+ *
+ * - `data` must be a valid pointer to a byte array of length `data_len`
+ * - `page_index` is 0-based
+ * - `options_json` must be a valid null-terminated C string containing a JSON
+ *   `PageRenderOptions` object (`target_dpi`, `max_image_dimension`, `auto_adjust_dpi`,
+ *   `min_dpi`, `max_dpi`), or NULL for the defaults
+ * - `password` must be a valid null-terminated C string, or NULL
+ * - The returned pointer must be freed with `kreuzberg_free_rendered_page`
+ * - Returns NULL on error (check `kreuzberg_last_error` for details)
+ *
+ * # Example (C)
+ *
+ * ```c
+ * CRenderedPage* page = kreuzberg_render_pdf_page(data, len, 0, "{\"target_dpi\": 150, ...}", NULL);
+ * if (page != NULL) {
+ *     printf("%ux%u\n", page->width, page->height);
+ *     kreuzberg_free_rendered_page(page);
+ * }
+ * ```
+ */
+struct CRenderedPage *kreuzberg_render_pdf_page(const uint8_t *data,
+                                                uintptr_t data_len,
+                                                uintptr_t page_index,
+                                                const char *options_json,
+                                                const char *password);
+
+/**
+ * Render several pages of a PDF document to RGB pixels, loading the document once.
+ *
+ * Rendering is all or nothing: if any page fails, no page is returned.
+ *
+ * # Safety
+ *
+ * - `data` must be a valid pointer to a byte array of length `data_len`
+ * - `page_indices` must be a valid pointer to `page_count` 0-based page indices
+ * - `options_json` and `password` follow `kreuzberg_render_pdf_page`
+ * - The returned pointer must be freed with `kreuzberg_free_rendered_page_batch`
+ * - Returns NULL on error (check `kreuzberg_last_error` for details)
+ *
+ * # Example (C)
+ *
+ * ```c
+ * uintptr_t indices[] = {0, 1, 2};
+ * CRenderedPageBatch* batch = kreuzberg_render_pdf_pages(data, len, indices, 3, NULL, NULL);
+ * if (batch != NULL) {
+ *     for (uintptr_t i = 0; i < batch->count; i++) {
+ *         printf("%ux%u\n", batch->pages[i].width, batch->pages[i].height);
+ *     }
+ *     kreuzberg_free_rendered_page_batch(batch);
+ * }
+ * ```
+ */
+struct CRenderedPageBatch *kreuzberg_render_pdf_pages(const uint8_t *data,
+                                                      uintptr_t data_len,
+                                                      const uintptr_t *page_indices,
+                                                      uintptr_t page_count,
+                                                      const char *options_json,
+                                                      const char *password);
+
+/**
+ * Count the pages of a PDF document.
+ *
+ * # Safety
+ *
+ * - `data` must be a valid pointer to a byte array of length `data_len`
+ * - `password` must be a valid null-terminated C string, or NULL
+ * - Returns the page count (>= 0), or -1 on error (check `kreuzberg_last_error` for details)
+ */
+int32_t kreuzberg_pdf_page_count(const uint8_t *data, uintptr_t data_len, const char *password);
+
+/**
+ * Free a page returned by `kreuzberg_render_pdf_page`.
+ *
+ * # Safety
+ *
+ * - `page` must be a pointer previously returned by `kreuzberg_render_pdf_page`
+ * - `page` can be NULL (no-op)
+ * - `page` must not be used after this call
+ */
+void kreuzberg_free_rendered_page(struct CRenderedPage *page);
+
+/**
+ * Free a batch returned by `kreuzberg_render_pdf_pages`, including its pages.
+ *
+ * # Safety
+ *
+ * - `batch` must be a pointer previously returned by `kreuzberg_render_pdf_pages`
+ * - `batch` can be NULL (no-op)
+ * - `batch` and its pages must not be used after this call
+ */
+void kreuzberg_free_rendered_page_batch(struct CRenderedPageBatch *batch);
+
 /**
  * Get page count from extraction result.
  *
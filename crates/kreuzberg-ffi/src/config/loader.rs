@@ -26,6 +26,25 @@ pub fn load_config_as_json(file_path: &str) -> Result<String, String> {
     }
 }
 
+/// Parse a configuration file without applying defaults (returns JSON string).
+///
+/// # Arguments
+///
+/// * `file_path` - Path to the configuration file
+///
+/// # Returns
+///
+/// JSON string with only the keys set by the file, or error message.
+pub fn load_config_document_as_json(file_path: &str) -> Result<String, String> {
+    match ExtractionConfig::document_from_file(file_path) {
+        Ok(document) => match serde_json::to_string(&document) {
+            Ok(json) => Ok(json),
+            Err(e) => Err(format!("Failed to serialize config document to JSON: {}", e)),
+        },
+        Err(e) => Err(e.to_string()),
+    }
+}
+
 /// Load an ExtractionConfig from a file (returns config struct).
 ///
 /// # Arguments
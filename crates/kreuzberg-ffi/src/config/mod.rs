@@ -18,7 +18,8 @@ mod serialize;
 
 // Re-export key functions for internal use
 pub use loader::{
-    discover_config_as_json, get_embedding_preset, list_embedding_presets, load_config_as_json, load_config_from_file,
+    discover_config_as_json, get_embedding_preset, list_embedding_presets, load_config_as_json,
+    load_config_document_as_json, load_config_from_file,
 };
 pub use merge::merge_configs;
 pub use parse::parse_extraction_config_from_json;
@@ -259,6 +260,49 @@ pub unsafe extern "C" fn kreuzberg_load_extraction_config_from_file(file_path: *
     })
 }
 
+/// Parse a configuration file without applying defaults (returns JSON string).
+///
+/// Unlike `kreuzberg_load_extraction_config_from_file`, the result contains only the
+/// keys the file sets, so bindings can tell explicit values apart from defaults.
+///
+/// # Safety
+///
+/// - `file_path` must be a valid null-terminated C string
+/// - The returned string must be freed with `kreuzberg_free_string`
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_config_file_document(file_path: *const c_char) -> *mut c_char {
+    ffi_panic_guard!("kreuzberg_config_file_document", {
+        clear_last_error();
+
+        if file_path.is_null() {
+            set_last_error("file_path cannot be NULL".to_string());
+            return ptr::null_mut();
+        }
+
+        let path_str = match unsafe { CStr::from_ptr(file_path) }.to_str() {
+            Ok(s) => s,
+            Err(e) => {
+                set_last_error(format!("Invalid UTF-8 in file path: {}", e));
+                return ptr::null_mut();
+            }
+        };
+
+        match load_config_document_as_json(path_str) {
+            Ok(json) => match CString::new(json) {
+                Ok(cstr) => cstr.into_raw(),
+                Err(e) => {
+                    set_last_error(format!("Failed to create C string: {}", e));
+                    ptr::null_mut()
+                }
+            },
+            Err(e) => {
+                set_last_error(e);
+                ptr::null_mut()
+            }
+        }
+    })
+}
+
 /// Load an ExtractionConfig from a file (returns pointer to config struct).
 ///
 /// # Safety
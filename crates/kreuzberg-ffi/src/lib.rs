@@ -14,6 +14,7 @@ mod memory;
 mod mime;
 mod panic_shield;
 mod plugins;
+mod rendering;
 mod result;
 mod result_pool;
 mod result_view;
@@ -26,9 +27,10 @@ pub use batch_streaming::{
     ErrorCallback, ResultCallback, kreuzberg_extract_batch_parallel, kreuzberg_extract_batch_streaming,
 };
 pub use config::{
-    kreuzberg_config_discover, kreuzberg_config_free, kreuzberg_config_from_file, kreuzberg_config_from_json,
-    kreuzberg_config_get_field, kreuzberg_config_is_valid, kreuzberg_config_merge, kreuzberg_config_to_json,
-    kreuzberg_get_embedding_preset, kreuzberg_list_embedding_presets, kreuzberg_load_extraction_config_from_file,
+    kreuzberg_config_discover, kreuzberg_config_file_document, kreuzberg_config_free, kreuzberg_config_from_file,
+    kreuzberg_config_from_json, kreuzberg_config_get_field, kreuzberg_config_is_valid, kreuzberg_config_merge,
+    kreuzberg_config_to_json, kreuzberg_get_embedding_preset, kreuzberg_list_embedding_presets,
+    kreuzberg_load_extraction_config_from_file,
 };
 pub use config_builder::{
     kreuzberg_config_builder_build, kreuzberg_config_builder_free, kreuzberg_config_builder_new,
@@ -66,6 +68,10 @@ pub use panic_shield::{
     get_last_panic_context, set_structured_error,
 };
 pub use plugins::*;
+pub use rendering::{
+    CRenderedPage, CRenderedPageBatch, kreuzberg_free_rendered_page, kreuzberg_free_rendered_page_batch,
+    kreuzberg_pdf_page_count, kreuzberg_render_pdf_page, kreuzberg_render_pdf_pages,
+};
 pub use result::{
     CMetadataField, kreuzberg_result_get_chunk_count, kreuzberg_result_get_detected_language,
     kreuzberg_result_get_metadata_field, kreuzberg_result_get_page_count,
@@ -0,0 +1,366 @@
+//! PDF page rendering FFI functions.
+//!
+//! Exposes `kreuzberg::pdf::rendering` so that bindings can render pages to images,
+//! e.g. for thumbnails or custom OCR. Pages are returned as raw RGB pixels; encoding
+//! to PNG or JPEG is left to the caller.
+//!
+//! # Safety
+//!
+//! PDFium is not thread-safe. Callers must serialize these functions with every other
+//! function that may use PDFium, such as the extraction functions.
+
+use std::ffi::CStr;
+use std::os::raw::c_char;
+use std::ptr;
+
+use kreuzberg::KreuzbergError;
+use kreuzberg::pdf::error::PdfError;
+use kreuzberg::pdf::metadata::extract_metadata_with_password;
+use kreuzberg::pdf::rendering::{PageRenderOptions, PdfRenderer};
+
+use crate::ffi_panic_guard;
+use crate::helpers::{clear_last_error, set_last_error};
+
+/// A rendered PDF page.
+///
+/// # Memory Layout
+///
+/// Field order: 1 pointer (8 bytes) + 1 usize (8 bytes) + 2 u32 (8 bytes) = 24 bytes total
+///
+/// # Memory Management
+///
+/// Must be freed with `kreuzberg_free_rendered_page`.
+#[repr(C)]
+pub struct CRenderedPage {
+    /// RGB pixels, 3 bytes per pixel, row by row from the top left
+    pub pixels: *mut u8,
+    /// Length of `pixels` in bytes
+    pub pixels_len: usize,
+    /// Image width in pixels
+    pub width: u32,
+    /// Image height in pixels
+    pub height: u32,
+}
+
+const _: () = {
+    const SIZE: usize = std::mem::size_of::<CRenderedPage>();
+    assert!(SIZE == 24, "CRenderedPage size must be 24 bytes");
+    const ALIGN: usize = std::mem::align_of::<CRenderedPage>();
+    assert!(ALIGN == 8, "CRenderedPage alignment must be 8 bytes");
+};
+
+/// Render one page of a PDF document to RGB pixels.
+///
+/// # Safety
+///
+/// - `data` must be a valid pointer to a byte array of length `data_len`
+/// - `page_index` is 0-based
+/// - `options_json` must be a valid null-terminated C string containing a JSON
+///   `PageRenderOptions` object (`target_dpi`, `max_image_dimension`, `auto_adjust_dpi`,
+///   `min_dpi`, `max_dpi`), or NULL for the defaults
+/// - `password` must be a valid null-terminated C string, or NULL
+/// - The returned pointer must be freed with `kreuzberg_free_rendered_page`
+/// - Returns NULL on error (check `kreuzberg_last_error` for details)
+///
+/// # Example (C)
+///
+/// ```c
+/// CRenderedPage* page = kreuzberg_render_pdf_page(data, len, 0, "{\"target_dpi\": 150, ...}", NULL);
+/// if (page != NULL) {
+///     printf("%ux%u\n", page->width, page->height);
+///     kreuzberg_free_rendered_page(page);
+/// }
+/// ```
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_render_pdf_page(
+    data: *const u8,
+    data_len: usize,
+    page_index: usize,
+    options_json: *const c_char,
+    password: *const c_char,
+) -> *mut CRenderedPage {
+    ffi_panic_guard!("kreuzberg_render_pdf_page", {
+        clear_last_error();
+
+        if data.is_null() {
+            set_last_error("data cannot be NULL".to_string());
+            return ptr::null_mut();
+        }
+
+        let bytes = unsafe { std::slice::from_raw_parts(data, data_len) };
+
+        let options = match unsafe { parse_render_options(options_json) } {
+            Ok(options) => options,
+            Err(e) => {
+                set_last_error(e);
+                return ptr::null_mut();
+            }
+        };
+
+        let password = match unsafe { parse_password(password) } {
+            Ok(password) => password,
+            Err(e) => {
+                set_last_error(e);
+                return ptr::null_mut();
+            }
+        };
+
+        match render_pages(bytes, &[page_index], &options, password) {
+            Ok(mut pages) => Box::into_raw(Box::new(pages.remove(0))),
+            Err(e) => {
+                set_last_error(KreuzbergError::from(e).to_string());
+                ptr::null_mut()
+            }
+        }
+    })
+}
+
+/// A batch of rendered PDF pages.
+///
+/// # Memory Layout
+///
+/// Field order: 1 pointer (8 bytes) + 1 usize (8 bytes) = 16 bytes total
+///
+/// # Memory Management
+///
+/// Must be freed with `kreuzberg_free_rendered_page_batch`, which also frees the pages.
+#[repr(C)]
+pub struct CRenderedPageBatch {
+    /// Rendered pages, in the order of the requested indices
+    pub pages: *mut CRenderedPage,
+    /// Number of pages in `pages`
+    pub count: usize,
+}
+
+const _: () = {
+    const SIZE: usize = std::mem::size_of::<CRenderedPageBatch>();
+    assert!(SIZE == 16, "CRenderedPageBatch size must be 16 bytes");
+    const ALIGN: usize = std::mem::align_of::<CRenderedPageBatch>();
+    assert!(ALIGN == 8, "CRenderedPageBatch alignment must be 8 bytes");
+};
+
+/// Render several pages of a PDF document to RGB pixels, loading the document once.
+///
+/// Rendering is all or nothing: if any page fails, no page is returned.
+///
+/// # Safety
+///
+/// - `data` must be a valid pointer to a byte array of length `data_len`
+/// - `page_indices` must be a valid pointer to `page_count` 0-based page indices
+/// - `options_json` and `password` follow `kreuzberg_render_pdf_page`
+/// - The returned pointer must be freed with `kreuzberg_free_rendered_page_batch`
+/// - Returns NULL on error (check `kreuzberg_last_error` for details)
+///
+/// # Example (C)
+///
+/// ```c
+/// uintptr_t indices[] = {0, 1, 2};
+/// CRenderedPageBatch* batch = kreuzberg_render_pdf_pages(data, len, indices, 3, NULL, NULL);
+/// if (batch != NULL) {
+///     for (uintptr_t i = 0; i < batch->count; i++) {
+///         printf("%ux%u\n", batch->pages[i].width, batch->pages[i].height);
+///     }
+///     kreuzberg_free_rendered_page_batch(batch);
+/// }
+/// ```
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_render_pdf_pages(
+    data: *const u8,
+    data_len: usize,
+    page_indices: *const usize,
+    page_count: usize,
+    options_json: *const c_char,
+    password: *const c_char,
+) -> *mut CRenderedPageBatch {
+    ffi_panic_guard!("kreuzberg_render_pdf_pages", {
+        clear_last_error();
+
+        if data.is_null() {
+            set_last_error("data cannot be NULL".to_string());
+            return ptr::null_mut();
+        }
+        if page_indices.is_null() || page_count == 0 {
+            set_last_error("page_indices cannot be NULL or empty".to_string());
+            return ptr::null_mut();
+        }
+
+        let bytes = unsafe { std::slice::from_raw_parts(data, data_len) };
+        let page_indices = unsafe { std::slice::from_raw_parts(page_indices, page_count) };
+
+        let options = match unsafe { parse_render_options(options_json) } {
+            Ok(options) => options,
+            Err(e) => {
+                set_last_error(e);
+                return ptr::null_mut();
+            }
+        };
+
+        let password = match unsafe { parse_password(password) } {
+            Ok(password) => password,
+            Err(e) => {
+                set_last_error(e);
+                return ptr::null_mut();
+            }
+        };
+
+        match render_pages(bytes, page_indices, &options, password) {
+            Ok(pages) => {
+                let pages = pages.into_boxed_slice();
+                let count = pages.len();
+                Box::into_raw(Box::new(CRenderedPageBatch {
+                    pages: Box::into_raw(pages) as *mut CRenderedPage,
+                    count,
+                }))
+            }
+            Err(e) => {
+                set_last_error(KreuzbergError::from(e).to_string());
+                ptr::null_mut()
+            }
+        }
+    })
+}
+
+/// Parse the render options JSON, falling back to the defaults when NULL.
+///
+/// # Safety
+///
+/// `options_json` must be a valid null-terminated C string, or NULL.
+unsafe fn parse_render_options(options_json: *const c_char) -> Result<PageRenderOptions, String> {
+    if options_json.is_null() {
+        return Ok(PageRenderOptions::default());
+    }
+    let options_str = unsafe { CStr::from_ptr(options_json) }
+        .to_str()
+        .map_err(|e| format!("Invalid UTF-8 in render options JSON: {}", e))?;
+    serde_json::from_str::<PageRenderOptions>(options_str).map_err(|e| format!("Invalid render options JSON: {}", e))
+}
+
+/// Parse an optional password.
+///
+/// # Safety
+///
+/// `password` must be a valid null-terminated C string, or NULL.
+unsafe fn parse_password<'a>(password: *const c_char) -> Result<Option<&'a str>, String> {
+    if password.is_null() {
+        return Ok(None);
+    }
+    unsafe { CStr::from_ptr(password) }
+        .to_str()
+        .map(Some)
+        .map_err(|e| format!("Invalid UTF-8 in password: {}", e))
+}
+
+fn render_pages(
+    bytes: &[u8],
+    page_indices: &[usize],
+    options: &PageRenderOptions,
+    password: Option<&str>,
+) -> Result<Vec<CRenderedPage>, PdfError> {
+    let renderer = PdfRenderer::new()?;
+    let images = renderer.render_pages_with_password(bytes, page_indices, options, password)?;
+
+    Ok(images
+        .into_iter()
+        .map(|image| {
+            let image = image.into_rgb8();
+            let (width, height) = (image.width(), image.height());
+            let pixels = image.into_raw().into_boxed_slice();
+            let pixels_len = pixels.len();
+
+            CRenderedPage {
+                pixels: Box::into_raw(pixels) as *mut u8,
+                pixels_len,
+                width,
+                height,
+            }
+        })
+        .collect())
+}
+
+/// Count the pages of a PDF document.
+///
+/// # Safety
+///
+/// - `data` must be a valid pointer to a byte array of length `data_len`
+/// - `password` must be a valid null-terminated C string, or NULL
+/// - Returns the page count (>= 0), or -1 on error (check `kreuzberg_last_error` for details)
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_pdf_page_count(data: *const u8, data_len: usize, password: *const c_char) -> i32 {
+    ffi_panic_guard!(
+        "kreuzberg_pdf_page_count",
+        {
+            clear_last_error();
+
+            if data.is_null() {
+                set_last_error("data cannot be NULL".to_string());
+                return -1;
+            }
+
+            let bytes = unsafe { std::slice::from_raw_parts(data, data_len) };
+
+            let password = match unsafe { parse_password(password) } {
+                Ok(password) => password,
+                Err(e) => {
+                    set_last_error(e);
+                    return -1;
+                }
+            };
+
+            match extract_metadata_with_password(bytes, password) {
+                Ok(metadata) => metadata.page_count.unwrap_or(0) as i32,
+                Err(e) => {
+                    set_last_error(KreuzbergError::from(e).to_string());
+                    -1
+                }
+            }
+        },
+        -1
+    )
+}
+
+/// Free a page returned by `kreuzberg_render_pdf_page`.
+///
+/// # Safety
+///
+/// - `page` must be a pointer previously returned by `kreuzberg_render_pdf_page`
+/// - `page` can be NULL (no-op)
+/// - `page` must not be used after this call
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_free_rendered_page(page: *mut CRenderedPage) {
+    if page.is_null() {
+        return;
+    }
+    let page = unsafe { Box::from_raw(page) };
+    unsafe { free_pixels(&page) };
+}
+
+/// Free a batch returned by `kreuzberg_render_pdf_pages`, including its pages.
+///
+/// # Safety
+///
+/// - `batch` must be a pointer previously returned by `kreuzberg_render_pdf_pages`
+/// - `batch` can be NULL (no-op)
+/// - `batch` and its pages must not be used after this call
+#[unsafe(no_mangle)]
+pub unsafe extern "C" fn kreuzberg_free_rendered_page_batch(batch: *mut CRenderedPageBatch) {
+    if batch.is_null() {
+        return;
+    }
+    let batch = unsafe { Box::from_raw(batch) };
+    if batch.pages.is_null() {
+        return;
+    }
+    let pages = unsafe { Box::from_raw(ptr::slice_from_raw_parts_mut(batch.pages, batch.count)) };
+    for page in pages.iter() {
+        unsafe { free_pixels(page) };
+    }
+}
+
+/// # Safety
+///
+/// `page.pixels` must have been allocated by `render_pages`, or be NULL.
+unsafe fn free_pixels(page: &CRenderedPage) {
+    if !page.pixels.is_null() {
+        drop(unsafe { Box::from_raw(ptr::slice_from_raw_parts_mut(page.pixels, page.pixels_len)) });
+    }
+}
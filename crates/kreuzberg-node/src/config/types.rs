@@ -832,6 +832,7 @@ pub struct JsPageConfig {
     pub extract_pages: Option<bool>,
     pub insert_page_markers: Option<bool>,
     pub marker_format: Option<String>,
+    pub ranges: Option<String>,
 }
 
 #[napi(object)]
@@ -866,6 +867,7 @@ impl TryFrom<JsPageConfig> for kreuzberg::core::config::PageConfig {
             marker_format: val
                 .marker_format
                 .unwrap_or_else(|| "\n\n<!-- PAGE {page_num} -->\n\n".to_string()),
+            ranges: val.ranges,
         })
     }
 }
@@ -876,6 +878,7 @@ impl From<kreuzberg::core::config::PageConfig> for JsPageConfig {
             extract_pages: Some(config.extract_pages),
             insert_page_markers: Some(config.insert_page_markers),
             marker_format: Some(config.marker_format),
+            ranges: config.ranges,
         }
     }
 }
@@ -1546,13 +1546,19 @@ pub struct PageConfig {
 #[pymethods]
 impl PageConfig {
     #[new]
-    #[pyo3(signature = (extract_pages=None, insert_page_markers=None, marker_format=None))]
-    fn new(extract_pages: Option<bool>, insert_page_markers: Option<bool>, marker_format: Option<String>) -> Self {
+    #[pyo3(signature = (extract_pages=None, insert_page_markers=None, marker_format=None, ranges=None))]
+    fn new(
+        extract_pages: Option<bool>,
+        insert_page_markers: Option<bool>,
+        marker_format: Option<String>,
+        ranges: Option<String>,
+    ) -> Self {
         Self {
             inner: kreuzberg::core::config::PageConfig {
                 extract_pages: extract_pages.unwrap_or(false),
                 insert_page_markers: insert_page_markers.unwrap_or(false),
                 marker_format: marker_format.unwrap_or_else(|| "\n\n<!-- PAGE {page_num} -->\n\n".to_string()),
+                ranges,
             },
         }
     }
@@ -1587,10 +1593,20 @@ impl PageConfig {
         self.inner.marker_format = value;
     }
 
+    #[getter]
+    fn ranges(&self) -> Option<String> {
+        self.inner.ranges.clone()
+    }
+
+    #[setter]
+    fn set_ranges(&mut self, value: Option<String>) {
+        self.inner.ranges = value;
+    }
+
     fn __repr__(&self) -> String {
         format!(
-            "PageConfig(extract_pages={}, insert_page_markers={}, marker_format='{}')",
-            self.inner.extract_pages, self.inner.insert_page_markers, self.inner.marker_format
+            "PageConfig(extract_pages={}, insert_page_markers={}, marker_format='{}', ranges={:?})",
+            self.inner.extract_pages, self.inner.insert_page_markers, self.inner.marker_format, self.inner.ranges
         )
     }
 }
@@ -175,6 +175,42 @@ impl ExtractionConfig {
         Ok((*config_arc).clone())
     }
 
+    /// Parse a configuration file without applying defaults.
+    ///
+    /// Returns the document as JSON, containing only the keys the file sets itself. Bindings
+    /// use it to tell explicitly set values apart from defaults, e.g. when layering a file on
+    /// top of a profile. The format is detected from the extension as in [`Self::from_file`].
+    ///
+    /// # Errors
+    ///
+    /// Returns `KreuzbergError::Validation` if the file cannot be read, the extension is not
+    /// supported or the content is invalid for the detected format.
+    pub fn document_from_file(path: impl AsRef<Path>) -> Result<serde_json::Value> {
+        let path = path.as_ref();
+
+        let extension = path
+            .extension()
+            .and_then(|ext| ext.to_str())
+            .map(|ext| ext.to_lowercase())
+            .unwrap_or_default();
+
+        let content = std::fs::read_to_string(path)
+            .map_err(|e| KreuzbergError::validation(format!("Failed to read config file {}: {}", path.display(), e)))?;
+
+        match extension.as_str() {
+            "toml" => toml::from_str(&content)
+                .map_err(|e| KreuzbergError::validation(format!("Invalid TOML in {}: {}", path.display(), e))),
+            "yaml" | "yml" => serde_yaml_ng::from_str(&content)
+                .map_err(|e| KreuzbergError::validation(format!("Invalid YAML in {}: {}", path.display(), e))),
+            "json" => serde_json::from_str(&content)
+                .map_err(|e| KreuzbergError::validation(format!("Invalid JSON in {}: {}", path.display(), e))),
+            _ => Err(KreuzbergError::validation(format!(
+                "Unsupported config file format: .{}. Supported formats: .toml, .yaml, .json",
+                extension
+            ))),
+        }
+    }
+
     /// Discover configuration file in parent directories.
     ///
     /// Searches for `kreuzberg.toml` in current directory and parent directories.
@@ -3,6 +3,7 @@
 //! Controls how pages are extracted, tracked, and represented in extraction results.
 //! When `None`, page tracking is disabled.
 
+use crate::{KreuzbergError, Result};
 use serde::{Deserialize, Serialize};
 
 /// Page extraction and tracking configuration.
@@ -27,6 +28,11 @@ pub struct PageConfig {
     /// Default: "\n\n<!-- PAGE {page_num} -->\n\n"
     #[serde(default = "default_page_marker_format")]
     pub marker_format: String,
+
+    /// Pages to extract, e.g. "1-3,10,-2" (see [`PageConfig::selected_page_indices`]).
+    /// `None` extracts every page. Only PDF extraction honours the selection.
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub ranges: Option<String>,
 }
 
 impl Default for PageConfig {
@@ -35,10 +41,78 @@ impl Default for PageConfig {
             extract_pages: false,
             insert_page_markers: false,
             marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+            ranges: None,
+        }
+    }
+}
+
+impl PageConfig {
+    /// Resolve `ranges` against a document of `page_count` pages.
+    ///
+    /// `ranges` is a comma-separated list of 1-indexed pages and inclusive page ranges:
+    /// "7" is page 7, "1-3" pages 1 to 3, "10-" page 10 to the last page and "-2" the
+    /// last two pages. Ranges may overlap and be given in any order; pages past the end
+    /// of the document are ignored.
+    ///
+    /// Returns the selected 0-indexed pages in ascending order, or `None` when `ranges`
+    /// is not set.
+    pub fn selected_page_indices(&self, page_count: usize) -> Result<Option<Vec<usize>>> {
+        let Some(spec) = self.ranges.as_deref() else {
+            return Ok(None);
+        };
+
+        let mut selected = vec![false; page_count];
+        let mut any_range = false;
+        for item in spec.split(',').map(str::trim).filter(|item| !item.is_empty()) {
+            let (first, last) = parse_page_range(item)
+                .ok_or_else(|| KreuzbergError::validation(format!("Invalid page range '{}' in '{}'", item, spec)))?;
+            any_range = true;
+
+            let resolve = |page: i64| if page < 0 { page_count as i64 + 1 + page } else { page };
+            for page in resolve(first).max(1)..=resolve(last).min(page_count as i64) {
+                selected[page as usize - 1] = true;
+            }
+        }
+        if !any_range {
+            return Err(KreuzbergError::validation(format!(
+                "Page ranges '{}' select no pages",
+                spec
+            )));
         }
+
+        Ok(Some(
+            selected
+                .iter()
+                .enumerate()
+                .filter_map(|(index, &keep)| keep.then_some(index))
+                .collect(),
+        ))
     }
 }
 
+/// Parse one item of `PageConfig::ranges` into its first and last page. Negative pages
+/// count from the end of the document: -1 is the last page.
+fn parse_page_range(item: &str) -> Option<(i64, i64)> {
+    if let Some(count) = item.strip_prefix('-') {
+        return Some((-parse_page_number(count)?, -1));
+    }
+    match item.split_once('-') {
+        None => {
+            let page = parse_page_number(item)?;
+            Some((page, page))
+        }
+        Some((first, last)) if last.trim().is_empty() => Some((parse_page_number(first)?, -1)),
+        Some((first, last)) => {
+            let (first, last) = (parse_page_number(first)?, parse_page_number(last)?);
+            (last >= first).then_some((first, last))
+        }
+    }
+}
+
+fn parse_page_number(s: &str) -> Option<i64> {
+    s.trim().parse::<i64>().ok().filter(|&page| page >= 1)
+}
+
 fn default_page_marker_format() -> String {
     "\n\n<!-- PAGE {page_num} -->\n\n".to_string()
 }
@@ -53,5 +127,28 @@ mod tests {
         assert!(!config.extract_pages);
         assert!(!config.insert_page_markers);
         assert_eq!(config.marker_format, "\n\n<!-- PAGE {page_num} -->\n\n");
+        assert!(config.ranges.is_none());
+    }
+
+    #[test]
+    fn test_selected_page_indices() {
+        let select = |ranges: &str, page_count: usize| {
+            PageConfig {
+                ranges: Some(ranges.to_string()),
+                ..Default::default()
+            }
+            .selected_page_indices(page_count)
+        };
+
+        assert_eq!(PageConfig::default().selected_page_indices(5).unwrap(), None);
+        assert_eq!(select("2", 5).unwrap(), Some(vec![1]));
+        assert_eq!(select("4-, 1-2", 5).unwrap(), Some(vec![0, 1, 3, 4]));
+        assert_eq!(select("-2,5", 5).unwrap(), Some(vec![3, 4]));
+        assert_eq!(select("3-10,-9", 4).unwrap(), Some(vec![0, 1, 2, 3]));
+        assert_eq!(select("7", 5).unwrap(), Some(vec![]));
+
+        for invalid in ["", " , ", "0", "3-1", "a", "1-b", "--2"] {
+            assert!(select(invalid, 5).is_err(), "{:?} should be rejected", invalid);
+        }
     }
 }
@@ -165,6 +165,7 @@ mod tests {
                 blocks: vec![FormattedBlock {
                     block_type: BlockType::Heading,
                     level: Some(1),
+                    table_index: None,
                     inline_content: vec![InlineElement {
                         element_type: InlineType::Text,
                         content: "Hello World".to_string(),
@@ -353,6 +354,7 @@ mod tests {
             blocks: vec![FormattedBlock {
                 block_type: BlockType::Paragraph,
                 level: None,
+                table_index: None,
                 inline_content: vec![InlineElement {
                     element_type: InlineType::Text,
                     content: "test".to_string(),
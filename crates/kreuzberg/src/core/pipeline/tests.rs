@@ -883,6 +883,7 @@ async fn test_run_pipeline_with_output_format_djot() {
             blocks: vec![FormattedBlock {
                 block_type: BlockType::Paragraph,
                 level: None,
+                table_index: None,
                 inline_content: vec![InlineElement {
                     element_type: InlineType::Text,
                     content: "test content".to_string(),
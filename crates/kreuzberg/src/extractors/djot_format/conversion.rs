@@ -6,6 +6,7 @@
 //! - Djot markup to HTML
 
 use super::rendering::render_block_to_djot;
+use crate::types::BlockType;
 use jotdown::Parser;
 #[cfg(test)]
 use std::borrow::Cow;
@@ -17,6 +18,7 @@ use std::borrow::Cow;
 /// - Block structure (headings, code blocks, lists, blockquotes, etc.)
 /// - Inline formatting (strong, emphasis, highlight, subscript, superscript, etc.)
 /// - Attributes where present ({.class #id key="value"})
+/// - Top-level tables, as pipe tables at their position
 ///
 /// # Arguments
 ///
@@ -37,6 +39,13 @@ pub fn djot_content_to_djot(content: &crate::types::DjotContent) -> String {
     let mut output = String::new();
 
     for block in &content.blocks {
+        if block.block_type == BlockType::Table {
+            if let Some(table) = block.table_index.and_then(|index| content.tables.get(index)) {
+                output.push_str(table.markdown.trim_end());
+                output.push_str("\n\n");
+            }
+            continue;
+        }
         render_block_to_djot(&mut output, block, 0);
     }
 
@@ -125,6 +134,7 @@ mod tests {
             blocks: vec![FormattedBlock {
                 block_type: BlockType::Heading,
                 level: Some(1),
+                table_index: None,
                 inline_content: vec![InlineElement {
                     element_type: InlineType::Text,
                     content: "Test Heading".to_string(),
@@ -164,6 +174,7 @@ mod tests {
                 blocks: vec![FormattedBlock {
                     block_type: BlockType::Paragraph,
                     level: None,
+                    table_index: None,
                     inline_content: vec![InlineElement {
                         element_type: InlineType::Text,
                         content: "Test content".to_string(),
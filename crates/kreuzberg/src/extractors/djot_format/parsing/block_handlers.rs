@@ -19,6 +19,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Heading,
                     level: Some(*level as usize),
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -34,6 +35,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Paragraph,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -49,6 +51,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Blockquote,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -71,6 +74,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::CodeBlock,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: lang_str,
@@ -89,6 +93,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::RawBlock,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: Some(format.to_string()),
@@ -109,6 +114,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -124,6 +130,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::ListItem,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -141,6 +148,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::ListItem,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: Some(attrs),
                     language: None,
@@ -156,6 +164,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::DefinitionList,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -171,6 +180,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::DefinitionTerm,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -186,6 +196,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::DefinitionDescription,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -201,6 +212,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Div,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -216,6 +228,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Section,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -237,6 +250,7 @@ pub(super) fn handle_block_start(
                 FormattedBlock {
                     block_type: BlockType::Paragraph,
                     level: None,
+                    table_index: None,
                     inline_content: Vec::new(),
                     attributes: parsed_attrs,
                     language: None,
@@ -4,7 +4,8 @@
 
 use super::block_handlers::{handle_block_end, handle_block_start};
 use super::event_handlers::{
-    finalize_block_element, handle_footnote_end, handle_footnote_reference, handle_symbol, handle_thematic_break,
+    finalize_block_element, handle_footnote_end, handle_footnote_reference, handle_symbol, handle_table_end,
+    handle_table_start, handle_thematic_break,
 };
 use super::inline_handlers::{
     finalize_inline_element, handle_image_end, handle_inline_end, handle_inline_start, handle_link_end, handle_math_end,
@@ -26,7 +27,7 @@ use jotdown::{Container, Event};
 /// - Task lists with checked state
 /// - Raw blocks (HTML/LaTeX)
 /// - Footnotes (references and definitions)
-/// - Captions
+/// - Table positions (cells are taken from `tables`)
 /// - Smart punctuation
 /// - All other djot features
 pub fn extract_complete_djot_content(
@@ -45,6 +46,14 @@ pub fn extract_complete_djot_content(
     let mut state = ExtractionState::new();
 
     for event in events {
+        if state.in_table {
+            match event {
+                Event::Start(Container::TableRow { .. }, _) => state.table_has_rows = true,
+                Event::End(Container::Table) => handle_table_end(&mut state, &mut blocks),
+                _ => {}
+            }
+            continue;
+        }
         match event {
             Event::Start(container, attrs) => {
                 handle_start_event(
@@ -183,26 +192,18 @@ fn handle_start_event(
         Some(parse_jotdown_attributes(attrs))
     };
 
-    // Try block handlers first
-    if handle_block_start(state, container, attrs, parsed_attrs.as_ref().cloned(), footnotes) {
+    if let Container::Table = container {
+        handle_table_start(state, parsed_attrs);
         return;
     }
 
-    // Try inline handlers
-    if handle_inline_start(state, container, parsed_attrs, images, links) {
+    // Try block handlers first
+    if handle_block_start(state, container, attrs, parsed_attrs.as_ref().cloned(), footnotes) {
         return;
     }
 
-    // Handle remaining containers (tables, link definitions, etc.)
-    match container {
-        Container::Table | Container::TableRow { .. } | Container::TableCell { .. } | Container::Caption => {
-            // Tables are extracted separately
-        }
-        Container::LinkDefinition { .. } => {
-            // Link definitions are resolved by jotdown, not needed in output
-        }
-        _ => {}
-    }
+    // Otherwise try inline handlers
+    handle_inline_start(state, container, parsed_attrs, images, links);
 }
 
 /// Handle end of a container event.
@@ -241,15 +242,4 @@ fn handle_end_event(
             }
         }
     }
-
-    // Handle remaining containers (tables, link definitions, etc.)
-    match container {
-        Container::Table | Container::TableRow { .. } | Container::TableCell { .. } | Container::Caption => {
-            // Tables are handled separately
-        }
-        Container::LinkDefinition { .. } => {
-            // Link definitions don't produce output
-        }
-        _ => {}
-    }
 }
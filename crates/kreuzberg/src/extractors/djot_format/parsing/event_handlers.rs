@@ -2,7 +2,7 @@
 
 use super::state::{ExtractionState, pop_block};
 use crate::extractors::djot_format::attributes::parse_jotdown_attributes;
-use crate::types::{BlockType, FormattedBlock, InlineElement, InlineType};
+use crate::types::{Attributes, BlockType, FormattedBlock, InlineElement, InlineType};
 use std::collections::HashMap;
 
 /// Handle footnote reference event.
@@ -49,6 +49,7 @@ pub(super) fn handle_thematic_break(
     let hr_block = FormattedBlock {
         block_type: BlockType::ThematicBreak,
         level: None,
+        table_index: None,
         inline_content: Vec::new(),
         attributes: parsed_attrs,
         language: None,
@@ -63,6 +64,44 @@ pub(super) fn handle_thematic_break(
     }
 }
 
+/// Handle start of a table.
+pub(super) fn handle_table_start(state: &mut ExtractionState, parsed_attrs: Option<Attributes>) {
+    state.flush_text();
+    state.in_table = true;
+    state.table_has_rows = false;
+    state.table_attributes = parsed_attrs;
+}
+
+/// Handle end of a table by recording its position in the block tree.
+///
+/// Only a table block pointing into `DjotContent::tables` is added. Tables without rows
+/// are skipped, as `extract_tables_from_events` skips them.
+pub(super) fn handle_table_end(state: &mut ExtractionState, blocks: &mut Vec<FormattedBlock>) {
+    state.in_table = false;
+    let attributes = state.table_attributes.take();
+    if !state.table_has_rows {
+        return;
+    }
+
+    let table_block = FormattedBlock {
+        block_type: BlockType::Table,
+        level: None,
+        table_index: Some(state.table_count),
+        inline_content: Vec::new(),
+        attributes,
+        language: None,
+        code: None,
+        children: Vec::new(),
+    };
+    state.table_count += 1;
+
+    if let Some(parent) = state.block_stack.last_mut() {
+        parent.children.push(table_block);
+    } else {
+        blocks.push(table_block);
+    }
+}
+
 /// Handle end of footnote definition.
 pub(super) fn handle_footnote_end(state: &mut ExtractionState, footnotes: &mut [crate::types::Footnote]) {
     state.flush_text();
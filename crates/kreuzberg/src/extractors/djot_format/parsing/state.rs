@@ -20,6 +20,10 @@ pub(super) struct ExtractionState {
     pub in_raw_block: bool,
     pub raw_format: Option<String>,
     pub current_inline_elements: Vec<InlineElement>,
+    pub in_table: bool, // Table events are skipped; cells come from extract_tables_from_events
+    pub table_has_rows: bool,
+    pub table_attributes: Option<Attributes>,
+    pub table_count: usize,
 }
 
 impl ExtractionState {
@@ -40,6 +44,10 @@ impl ExtractionState {
             in_raw_block: false,
             raw_format: None,
             current_inline_elements: Vec::new(),
+            in_table: false,
+            table_has_rows: false,
+            table_attributes: None,
+            table_count: 0,
         }
     }
 
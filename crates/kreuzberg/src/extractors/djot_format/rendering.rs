@@ -206,6 +206,9 @@ pub fn render_block_to_djot(output: &mut String, block: &FormattedBlock, indent_
             output.push_str(&indent);
             output.push_str("```\n\n");
         }
+        BlockType::Table => {
+            // Cells live in `DjotContent::tables`; see `djot_content_to_djot`.
+        }
         BlockType::MathDisplay => {
             output.push_str(&indent);
             output.push_str("$$\n");
@@ -389,6 +392,7 @@ mod tests {
             blocks: vec![FormattedBlock {
                 block_type: BlockType::Heading,
                 level: Some(1),
+                table_index: None,
                 inline_content: vec![InlineElement {
                     element_type: InlineType::Text,
                     content: "Test Heading".to_string(),
@@ -423,6 +427,7 @@ mod tests {
             blocks: vec![FormattedBlock {
                 block_type: BlockType::Paragraph,
                 level: None,
+                table_index: None,
                 inline_content: vec![InlineElement {
                     element_type: InlineType::Text,
                     content: "Test paragraph".to_string(),
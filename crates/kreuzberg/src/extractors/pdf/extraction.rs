@@ -20,6 +20,53 @@ pub(crate) type PdfExtractionPhaseResult = (
     Option<Vec<PageBoundary>>,
 );
 
+/// Open a PDF document, trying the configured passwords if it is encrypted.
+///
+/// The document is first opened without a password. If PDFium reports that a password is
+/// required, each of `config.pdf_options.passwords` is tried in order.
+///
+/// # Returns
+///
+/// The document and the password that opened it, or `None` if it is not encrypted.
+///
+/// # Errors
+///
+/// `PdfError::PasswordRequired` if the document is encrypted and no passwords are configured,
+/// `PdfError::InvalidPassword` if none of them opens it.
+#[cfg(feature = "pdf")]
+pub(crate) fn load_pdf_document<'a>(
+    pdfium: &'a Pdfium,
+    content: &'a [u8],
+    config: &ExtractionConfig,
+) -> std::result::Result<(PdfDocument<'a>, Option<String>), crate::pdf::error::PdfError> {
+    use crate::pdf::error::{PdfError, format_pdfium_error};
+
+    let err_msg = match pdfium.load_pdf_from_byte_slice(content, None) {
+        Ok(document) => return Ok((document, None)),
+        Err(e) => format_pdfium_error(e),
+    };
+    if !(err_msg.contains("password") || err_msg.contains("Password")) {
+        return Err(PdfError::InvalidPdf(err_msg));
+    }
+
+    let passwords = config
+        .pdf_options
+        .as_ref()
+        .and_then(|pdf| pdf.passwords.as_deref())
+        .unwrap_or_default();
+    for password in passwords {
+        if let Ok(document) = pdfium.load_pdf_from_byte_slice(content, Some(password)) {
+            return Ok((document, Some(password.clone())));
+        }
+    }
+
+    if passwords.is_empty() {
+        Err(PdfError::PasswordRequired)
+    } else {
+        Err(PdfError::InvalidPassword)
+    }
+}
+
 /// Extract text, metadata, and tables from a PDF document using a single shared instance.
 ///
 /// This method consolidates all PDF extraction phases (text, metadata, tables) into a single
@@ -48,10 +95,15 @@ pub(crate) fn extract_all_from_document(
     document: &PdfDocument,
     config: &ExtractionConfig,
 ) -> Result<PdfExtractionPhaseResult> {
+    let page_indices = match config.pages.as_ref() {
+        Some(page_config) => page_config.selected_page_indices(document.pages().len() as usize)?,
+        None => None,
+    };
+
     let (native_text, boundaries, page_contents, pdf_metadata) =
         crate::pdf::text::extract_text_and_metadata_from_pdf_document(document, Some(config))?;
 
-    let tables = extract_tables_from_document(document, &pdf_metadata)?;
+    let tables = extract_tables_from_document(document, &pdf_metadata, page_indices.as_deref())?;
 
     Ok((pdf_metadata, native_text, tables, page_contents, boundaries))
 }
@@ -62,17 +114,28 @@ pub(crate) fn extract_all_from_document(
 /// then uses the existing table reconstruction logic to detect tables.
 ///
 /// Uses the shared PdfDocument reference (wrapped in Arc<RwLock<>> for thread-safety).
+/// When `page_indices` is set, only those pages are scanned.
 #[cfg(all(feature = "pdf", feature = "ocr"))]
 fn extract_tables_from_document(
     document: &PdfDocument,
     _metadata: &crate::pdf::metadata::PdfExtractionMetadata,
+    page_indices: Option<&[usize]>,
 ) -> Result<Vec<Table>> {
     use crate::ocr::table::{reconstruct_table, table_to_markdown};
     use crate::pdf::table::extract_words_from_page;
 
     let mut all_tables = Vec::new();
 
-    for (page_index, page) in document.pages().iter().enumerate() {
+    let page_indices = match page_indices {
+        Some(indices) => indices.to_vec(),
+        None => (0..document.pages().len() as usize).collect(),
+    };
+
+    for page_index in page_indices {
+        let page = document
+            .pages()
+            .get(page_index as i32)
+            .map_err(|_| crate::pdf::error::PdfError::PageNotFound(page_index))?;
         let words = extract_words_from_page(&page, 0.0)?;
 
         if words.is_empty() {
@@ -103,6 +166,7 @@ fn extract_tables_from_document(
 fn extract_tables_from_document(
     _document: &PdfDocument,
     _metadata: &crate::pdf::metadata::PdfExtractionMetadata,
+    _page_indices: Option<&[usize]>,
 ) -> Result<Vec<crate::types::Table>> {
     Ok(vec![])
 }
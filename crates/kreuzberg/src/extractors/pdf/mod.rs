@@ -13,7 +13,9 @@ use crate::Result;
 use crate::core::config::ExtractionConfig;
 use crate::plugins::{DocumentExtractor, Plugin};
 use crate::types::{ExtractionResult, Metadata};
+use ahash::AHashMap;
 use async_trait::async_trait;
+use std::borrow::Cow;
 #[cfg(feature = "tokio-runtime")]
 use std::path::Path;
 
@@ -24,7 +26,7 @@ use crate::pdf::error::PdfError;
 #[cfg(feature = "ocr")]
 pub use ocr::{NativeTextStats, OcrFallbackDecision, evaluate_native_text_for_ocr, evaluate_per_page_ocr};
 
-use extraction::extract_all_from_document;
+use extraction::{extract_all_from_document, load_pdf_document};
 #[cfg(feature = "ocr")]
 use ocr::extract_with_ocr;
 use pages::assign_tables_and_images_to_pages;
@@ -78,7 +80,7 @@ impl DocumentExtractor for PdfExtractor {
         config: &ExtractionConfig,
     ) -> Result<ExtractionResult> {
         #[cfg(feature = "pdf")]
-        let (pdf_metadata, native_text, tables, page_contents, _boundaries) = {
+        let ((pdf_metadata, native_text, tables, page_contents, _boundaries), pdf_password) = {
             #[cfg(target_arch = "wasm32")]
             {
                 let pdfium = crate::pdf::bindings::bind_pdfium(PdfError::MetadataExtractionFailed, "initialize Pdfium")
@@ -96,16 +98,9 @@ impl DocumentExtractor for PdfExtractor {
                         }
                     })?;
 
-                let document = pdfium.load_pdf_from_byte_slice(content, None).map_err(|e| {
-                    let err_msg = crate::pdf::error::format_pdfium_error(e);
-                    if err_msg.contains("password") || err_msg.contains("Password") {
-                        PdfError::PasswordRequired
-                    } else {
-                        PdfError::InvalidPdf(err_msg)
-                    }
-                })?;
+                let (document, pdf_password) = load_pdf_document(&pdfium, content, config)?;
 
-                extract_all_from_document(&document, config)?
+                (extract_all_from_document(&document, config)?, pdf_password)
             }
             #[cfg(all(not(target_arch = "wasm32"), feature = "tokio-runtime"))]
             {
@@ -119,14 +114,7 @@ impl DocumentExtractor for PdfExtractor {
                         let pdfium =
                             crate::pdf::bindings::bind_pdfium(PdfError::MetadataExtractionFailed, "initialize Pdfium")?;
 
-                        let document = pdfium.load_pdf_from_byte_slice(&content_owned, None).map_err(|e| {
-                            let err_msg = crate::pdf::error::format_pdfium_error(e);
-                            if err_msg.contains("password") || err_msg.contains("Password") {
-                                PdfError::PasswordRequired
-                            } else {
-                                PdfError::InvalidPdf(err_msg)
-                            }
-                        })?;
+                        let (document, pdf_password) = load_pdf_document(&pdfium, &content_owned, &config_owned)?;
 
                         let (pdf_metadata, native_text, tables, page_contents, _boundaries) =
                             extract_all_from_document(&document, &config_owned)?;
@@ -143,11 +131,8 @@ impl DocumentExtractor for PdfExtractor {
                         }
 
                         Ok::<_, crate::error::KreuzbergError>((
-                            pdf_metadata,
-                            native_text,
-                            tables,
-                            page_contents,
-                            _boundaries,
+                            (pdf_metadata, native_text, tables, page_contents, _boundaries),
+                            pdf_password,
                         ))
                     })
                     .await
@@ -156,16 +141,9 @@ impl DocumentExtractor for PdfExtractor {
                     let pdfium =
                         crate::pdf::bindings::bind_pdfium(PdfError::MetadataExtractionFailed, "initialize Pdfium")?;
 
-                    let document = pdfium.load_pdf_from_byte_slice(content, None).map_err(|e| {
-                        let err_msg = crate::pdf::error::format_pdfium_error(e);
-                        if err_msg.contains("password") || err_msg.contains("Password") {
-                            PdfError::PasswordRequired
-                        } else {
-                            PdfError::InvalidPdf(err_msg)
-                        }
-                    })?;
+                    let (document, pdf_password) = load_pdf_document(&pdfium, content, config)?;
 
-                    extract_all_from_document(&document, config)?
+                    (extract_all_from_document(&document, config)?, pdf_password)
                 }
             }
             #[cfg(all(not(target_arch = "wasm32"), not(feature = "tokio-runtime")))]
@@ -173,23 +151,24 @@ impl DocumentExtractor for PdfExtractor {
                 let pdfium =
                     crate::pdf::bindings::bind_pdfium(PdfError::MetadataExtractionFailed, "initialize Pdfium")?;
 
-                let document = pdfium.load_pdf_from_byte_slice(content, None).map_err(|e| {
-                    let err_msg = crate::pdf::error::format_pdfium_error(e);
-                    if err_msg.contains("password") || err_msg.contains("Password") {
-                        PdfError::PasswordRequired
-                    } else {
-                        PdfError::InvalidPdf(err_msg)
-                    }
-                })?;
+                let (document, pdf_password) = load_pdf_document(&pdfium, content, config)?;
 
-                extract_all_from_document(&document, config)?
+                (extract_all_from_document(&document, config)?, pdf_password)
             }
         };
 
+        // Pages selected by `PageConfig::ranges` (0-indexed); `None` when every page is extracted.
+        let page_indices: Option<Vec<usize>> = config
+            .pages
+            .as_ref()
+            .filter(|page_cfg| page_cfg.ranges.is_some())
+            .and(_boundaries.as_ref())
+            .map(|boundaries| boundaries.iter().map(|boundary| boundary.page_number - 1).collect());
+
         #[cfg(feature = "ocr")]
         let text = if config.force_ocr {
             if config.ocr.is_some() {
-                extract_with_ocr(content, config).await?
+                extract_with_ocr(content, config, pdf_password.as_deref(), page_indices.as_deref()).await?
             } else {
                 native_text
             }
@@ -197,7 +176,10 @@ impl DocumentExtractor for PdfExtractor {
             let decision = ocr::evaluate_per_page_ocr(
                 &native_text,
                 _boundaries.as_deref(),
-                pdf_metadata.pdf_specific.page_count,
+                page_indices
+                    .as_ref()
+                    .map(Vec::len)
+                    .or(pdf_metadata.pdf_specific.page_count),
             );
 
             if std::env::var("KREUZBERG_DEBUG_OCR").is_ok() {
@@ -215,7 +197,7 @@ impl DocumentExtractor for PdfExtractor {
             }
 
             if decision.fallback {
-                extract_with_ocr(content, config).await?
+                extract_with_ocr(content, config, pdf_password.as_deref(), page_indices.as_deref()).await?
             } else {
                 native_text
             }
@@ -242,7 +224,12 @@ impl DocumentExtractor for PdfExtractor {
 
         let images = if config.images.as_ref().map(|c| c.extract_images).unwrap_or(false) {
             // Image extraction is enabled, extract images if present
-            match crate::pdf::images::extract_images_from_pdf(content) {
+            let pdf_images = crate::pdf::images::PdfImageExtractor::new_with_password(content, pdf_password.as_deref())
+                .and_then(|extractor| match page_indices.as_deref() {
+                    Some(indices) => extractor.extract_images_from_pages(indices),
+                    None => extractor.extract_images(),
+                });
+            match pdf_images {
                 Ok(pdf_images) => Some(
                     pdf_images
                         .into_iter()
@@ -280,6 +267,12 @@ impl DocumentExtractor for PdfExtractor {
 
         let final_pages = assign_tables_and_images_to_pages(page_contents, &tables, images.as_deref().unwrap_or(&[]));
 
+        let mut additional = AHashMap::new();
+        if let Some(ref indices) = page_indices {
+            let selected_pages: Vec<usize> = indices.iter().map(|index| index + 1).collect();
+            additional.insert(Cow::Borrowed("selected_pages"), serde_json::json!(selected_pages));
+        }
+
         Ok(ExtractionResult {
             content: text,
             mime_type: mime_type.to_string().into(),
@@ -302,6 +295,7 @@ impl DocumentExtractor for PdfExtractor {
                 pages: pdf_metadata.page_structure.clone(),
                 #[cfg(feature = "pdf")]
                 format: Some(crate::types::FormatMetadata::Pdf(pdf_metadata.pdf_specific)),
+                additional,
                 ..Default::default()
             },
             pages: final_pages,
@@ -540,6 +534,7 @@ mod tests {
                 extract_pages: true,
                 insert_page_markers: false,
                 marker_format: "<!-- PAGE {page_num} -->".to_string(),
+                ranges: None,
             }),
             ..Default::default()
         };
@@ -598,6 +593,7 @@ mod tests {
                 extract_pages: true,
                 insert_page_markers: true,
                 marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+                ranges: None,
             }),
             ..Default::default()
         };
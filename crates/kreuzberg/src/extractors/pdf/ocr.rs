@@ -168,18 +168,25 @@ pub fn evaluate_per_page_ocr(
 
 /// Extract text from PDF using OCR.
 ///
-/// Renders all pages to images and processes them with OCR backend.
+/// Renders the selected pages to images and processes them with OCR backend.
 ///
 /// # Arguments
 ///
 /// * `content` - Raw PDF bytes
 /// * `config` - Extraction configuration including OCR settings
+/// * `password` - Password that opened the document, if it is encrypted
+/// * `page_indices` - Pages selected by `PageConfig::ranges` (0-indexed), or `None` for all
 ///
 /// # Returns
 ///
-/// Concatenated text from all pages, separated by double newlines
+/// Concatenated text from the rendered pages, separated by double newlines
 #[cfg(feature = "ocr")]
-pub(crate) async fn extract_with_ocr(content: &[u8], config: &ExtractionConfig) -> crate::Result<String> {
+pub(crate) async fn extract_with_ocr(
+    content: &[u8],
+    config: &ExtractionConfig,
+    password: Option<&str>,
+    page_indices: Option<&[usize]>,
+) -> crate::Result<String> {
     use crate::pdf::rendering::{PageRenderOptions, PdfRenderer};
     use crate::plugins::registry::get_ocr_backend_registry;
     use image::ImageEncoder;
@@ -207,12 +214,14 @@ pub(crate) async fn extract_with_ocr(content: &[u8], config: &ExtractionConfig)
             source: None,
         })?;
 
-        renderer
-            .render_all_pages(content, &render_options)
-            .map_err(|e| crate::KreuzbergError::Parsing {
-                message: format!("Failed to render PDF pages: {}", e),
-                source: None,
-            })?
+        match page_indices {
+            Some(indices) => renderer.render_pages_with_password(content, indices, &render_options, password),
+            None => renderer.render_all_pages_with_password(content, &render_options, password),
+        }
+        .map_err(|e| crate::KreuzbergError::Parsing {
+            message: format!("Failed to render PDF pages: {}", e),
+            source: None,
+        })?
     };
 
     let mut page_texts = Vec::with_capacity(images.len());
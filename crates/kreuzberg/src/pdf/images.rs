@@ -98,6 +98,15 @@ impl PdfImageExtractor {
         Ok(page_images)
     }
 
+    /// Extract the images of the pages at `page_indices` (0-indexed), in that order.
+    pub fn extract_images_from_pages(&self, page_indices: &[usize]) -> Result<Vec<PdfImage>> {
+        let mut images = Vec::new();
+        for &page_index in page_indices {
+            images.extend(self.extract_images_from_page(page_index as u32 + 1)?);
+        }
+        Ok(images)
+    }
+
     pub fn get_image_count(&self) -> Result<usize> {
         let images = self.extract_images()?;
         Ok(images.len())
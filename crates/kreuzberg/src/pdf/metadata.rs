@@ -146,20 +146,24 @@ pub fn extract_metadata_from_document(
     document: &PdfDocument<'_>,
     page_boundaries: Option<&[PageBoundary]>,
 ) -> Result<PdfExtractionMetadata> {
-    extract_metadata_from_document_impl(document, page_boundaries)
+    extract_metadata_from_document_impl(document, page_boundaries, false)
 }
 
 /// Internal implementation of metadata extraction that can be reused by unified extraction.
+///
+/// `page_selection` is true when the boundaries come from `PageConfig::ranges`, which may
+/// select no page of the document.
 pub(crate) fn extract_metadata_from_document_impl(
     document: &PdfDocument<'_>,
     page_boundaries: Option<&[PageBoundary]>,
+    page_selection: bool,
 ) -> Result<PdfExtractionMetadata> {
     let pdf_specific = extract_pdf_specific_metadata(document)?;
 
     let common = extract_common_metadata_from_document(document)?;
 
     let page_structure = if let Some(boundaries) = page_boundaries {
-        Some(build_page_structure(document, boundaries)?)
+        Some(build_page_structure(document, boundaries, page_selection)?)
     } else {
         None
     };
@@ -221,30 +225,47 @@ fn extract_pdf_specific_metadata(document: &PdfDocument<'_>) -> Result<PdfMetada
 ///
 /// # Validation
 ///
-/// - Boundaries must not be empty
-/// - Boundary count must match the document's page count
-fn build_page_structure(document: &PdfDocument<'_>, boundaries: &[PageBoundary]) -> Result<PageStructure> {
+/// - Boundaries must not be empty, unless `page_selection` is set: a selection past the
+///   end of the document yields a structure with the page count and no boundaries
+/// - Boundaries must refer to pages of the document; they cover a subset of the pages
+///   when `PageConfig::ranges` selects pages
+fn build_page_structure(
+    document: &PdfDocument<'_>,
+    boundaries: &[PageBoundary],
+    page_selection: bool,
+) -> Result<PageStructure> {
     let total_count = document.pages().len() as usize;
 
+    if boundaries.is_empty() && page_selection {
+        return Ok(PageStructure {
+            total_count,
+            unit_type: PageUnitType::Page,
+            boundaries: Some(Vec::new()),
+            pages: None,
+        });
+    }
+
     if boundaries.is_empty() {
         return Err(PdfError::MetadataExtractionFailed(
             "No page boundaries provided for PageStructure".to_string(),
         ));
     }
 
-    if boundaries.len() != total_count {
+    if let Some(boundary) = boundaries
+        .iter()
+        .find(|boundary| boundary.page_number == 0 || boundary.page_number > total_count)
+    {
         return Err(PdfError::MetadataExtractionFailed(format!(
-            "Boundary count {} doesn't match page count {}",
-            boundaries.len(),
-            total_count
+            "Boundary page {} is outside page count {}",
+            boundary.page_number, total_count
         )));
     }
 
     let mut pages = Vec::new();
-    for (index, boundary) in boundaries.iter().enumerate() {
+    for boundary in boundaries {
         let page_number = boundary.page_number;
 
-        let dimensions = if let Ok(page_rect) = document.pages().page_size(index as i32) {
+        let dimensions = if let Ok(page_rect) = document.pages().page_size((page_number - 1) as i32) {
             Some((page_rect.width().value as f64, page_rect.height().value as f64))
         } else {
             None
@@ -369,6 +390,8 @@ fn parse_keywords(keywords_str: &str) -> Vec<String> {
         .collect()
 }
 
+/// Converts a PDF date ("D:YYYYMMDDHHmmSSOHH'mm'") to RFC 3339. The UT offset of a
+/// full date is kept; dates without one are given as UTC.
 fn parse_pdf_date(date_str: &str) -> String {
     let cleaned = date_str.trim();
 
@@ -381,7 +404,8 @@ fn parse_pdf_date(date_str: &str) -> String {
             let hour = &cleaned[10..12];
             let minute = &cleaned[12..14];
             let second = &cleaned[14..16];
-            format!("{}-{}-{}T{}:{}:{}Z", year, month, day, hour, minute, second)
+            let offset = pdf_date_offset(&cleaned[16..]);
+            format!("{}-{}-{}T{}:{}:{}{}", year, month, day, hour, minute, second, offset)
         } else if cleaned.len() >= 14 {
             let hour = &cleaned[10..12];
             let minute = &cleaned[12..14];
@@ -399,6 +423,21 @@ fn parse_pdf_date(date_str: &str) -> String {
     }
 }
 
+/// Converts the UT offset of a PDF date ("+01'00'", "-05", "Z", "Z00'00'") to RFC 3339.
+fn pdf_date_offset(rest: &str) -> String {
+    let mut chars = rest.trim().chars();
+    let sign = match chars.next() {
+        Some(sign @ ('+' | '-')) => sign,
+        _ => return "Z".to_string(),
+    };
+    let digits: String = chars.filter(char::is_ascii_digit).collect();
+    match digits.len() {
+        2 | 3 => format!("{}{}:00", sign, &digits[0..2]),
+        len if len >= 4 => format!("{}{}:{}", sign, &digits[0..2], &digits[2..4]),
+        _ => "Z".to_string(),
+    }
+}
+
 fn format_pdf_version(version: PdfDocumentVersion) -> Option<String> {
     match version {
         PdfDocumentVersion::Unset => None,
@@ -473,6 +512,14 @@ mod tests {
         assert_eq!(date, "2023-01-15T12:30:45Z");
     }
 
+    #[test]
+    fn test_parse_pdf_date_offset() {
+        assert_eq!(parse_pdf_date("D:20230105120000+01'00'"), "2023-01-05T12:00:00+01:00");
+        assert_eq!(parse_pdf_date("D:20230105120000-05'30"), "2023-01-05T12:00:00-05:30");
+        assert_eq!(parse_pdf_date("D:20230105120000-08"), "2023-01-05T12:00:00-08:00");
+        assert_eq!(parse_pdf_date("D:20230105120000Z00'00'"), "2023-01-05T12:00:00Z");
+    }
+
     #[test]
     fn test_parse_pdf_date_no_time() {
         let date = parse_pdf_date("D:20230115");
@@ -498,13 +545,10 @@ mod tests {
     }
 
     #[test]
-    fn test_build_page_structure_boundary_mismatch_message() {
-        let boundaries_count = 3;
+    fn test_build_page_structure_boundary_out_of_range_message() {
+        let boundary_page = 6;
         let page_count = 5;
-        let error_msg = format!(
-            "Boundary count {} doesn't match page count {}",
-            boundaries_count, page_count
-        );
-        assert_eq!(error_msg, "Boundary count 3 doesn't match page count 5");
+        let error_msg = format!("Boundary page {} is outside page count {}", boundary_page, page_count);
+        assert_eq!(error_msg, "Boundary page 6 is outside page count 5");
     }
 }
@@ -71,36 +71,7 @@ impl PdfRenderer<'_> {
             .get(page_index as i32)
             .map_err(|_| PdfError::PageNotFound(page_index))?;
 
-        let width_points = page.width().value;
-        let height_points = page.height().value;
-
-        let dpi = if options.auto_adjust_dpi {
-            calculate_optimal_dpi(
-                width_points as f64,
-                height_points as f64,
-                options.target_dpi,
-                options.max_image_dimension,
-                options.min_dpi,
-                options.max_dpi,
-            )
-        } else {
-            options.target_dpi
-        };
-
-        let scale = dpi as f64 / PDF_POINTS_PER_INCH;
-
-        let config = PdfRenderConfig::new()
-            .set_target_width(((width_points * scale as f32) as i32).max(1))
-            .set_target_height(((height_points * scale as f32) as i32).max(1))
-            .rotate_if_landscape(PdfPageRenderRotation::None, false);
-
-        let bitmap = page
-            .render_with_config(&config)
-            .map_err(|e| PdfError::RenderingFailed(format!("Failed to render page: {}", e)))?;
-
-        let image = bitmap.as_image().into_rgb8();
-
-        Ok(DynamicImage::ImageRgb8(image))
+        render_page(&page, options)
     }
 
     pub fn render_all_pages(&self, pdf_bytes: &[u8], options: &PageRenderOptions) -> Result<Vec<DynamicImage>> {
@@ -125,17 +96,83 @@ impl PdfRenderer<'_> {
         })?;
 
         let page_count = document.pages().len() as usize;
-        let mut images = Vec::with_capacity(page_count);
+        render_document_pages(&document, options, (0..page_count).collect())
+    }
 
-        for page_index in 0..page_count {
-            let image = self.render_page_to_image_with_password(pdf_bytes, page_index, options, password)?;
-            images.push(image);
-        }
+    /// Render the pages at `page_indices` (0-indexed), loading the document once.
+    pub fn render_pages_with_password(
+        &self,
+        pdf_bytes: &[u8],
+        page_indices: &[usize],
+        options: &PageRenderOptions,
+        password: Option<&str>,
+    ) -> Result<Vec<DynamicImage>> {
+        let document = self.pdfium.load_pdf_from_byte_slice(pdf_bytes, password).map_err(|e| {
+            let err_msg = super::error::format_pdfium_error(e);
+            if (err_msg.contains("password") || err_msg.contains("Password")) && password.is_some() {
+                PdfError::InvalidPassword
+            } else if err_msg.contains("password") || err_msg.contains("Password") {
+                PdfError::PasswordRequired
+            } else {
+                PdfError::InvalidPdf(err_msg)
+            }
+        })?;
 
-        Ok(images)
+        render_document_pages(&document, options, page_indices.to_vec())
     }
 }
 
+fn render_document_pages(
+    document: &PdfDocument<'_>,
+    options: &PageRenderOptions,
+    page_indices: Vec<usize>,
+) -> Result<Vec<DynamicImage>> {
+    let mut images = Vec::with_capacity(page_indices.len());
+
+    for page_index in page_indices {
+        let page = document
+            .pages()
+            .get(page_index as i32)
+            .map_err(|_| PdfError::PageNotFound(page_index))?;
+        images.push(render_page(&page, options)?);
+    }
+
+    Ok(images)
+}
+
+fn render_page(page: &PdfPage<'_>, options: &PageRenderOptions) -> Result<DynamicImage> {
+    let width_points = page.width().value;
+    let height_points = page.height().value;
+
+    let dpi = if options.auto_adjust_dpi {
+        calculate_optimal_dpi(
+            width_points as f64,
+            height_points as f64,
+            options.target_dpi,
+            options.max_image_dimension,
+            options.min_dpi,
+            options.max_dpi,
+        )
+    } else {
+        options.target_dpi
+    };
+
+    let scale = dpi as f64 / PDF_POINTS_PER_INCH;
+
+    let config = PdfRenderConfig::new()
+        .set_target_width(((width_points * scale as f32) as i32).max(1))
+        .set_target_height(((height_points * scale as f32) as i32).max(1))
+        .rotate_if_landscape(PdfPageRenderRotation::None, false);
+
+    let bitmap = page
+        .render_with_config(&config)
+        .map_err(|e| PdfError::RenderingFailed(format!("Failed to render page: {}", e)))?;
+
+    let image = bitmap.as_image().into_rgb8();
+
+    Ok(DynamicImage::ImageRgb8(image))
+}
+
 pub fn render_page_to_image(pdf_bytes: &[u8], page_index: usize, options: &PageRenderOptions) -> Result<DynamicImage> {
     let renderer = PdfRenderer::new()?;
     renderer.render_page_to_image(pdf_bytes, page_index, options)
@@ -141,7 +141,9 @@ pub fn extract_text_and_metadata_from_pdf_document(
     let page_config = extraction_config.and_then(|c| c.pages.as_ref());
     let (text, boundaries, page_contents) = extract_text_from_pdf_document(document, page_config, extraction_config)?;
 
-    let metadata = crate::pdf::metadata::extract_metadata_from_document_impl(document, boundaries.as_deref())?;
+    let page_selection = page_config.is_some_and(|page_cfg| page_cfg.ranges.is_some());
+    let metadata =
+        crate::pdf::metadata::extract_metadata_from_document_impl(document, boundaries.as_deref(), page_selection)?;
 
     Ok((text, boundaries, page_contents, metadata))
 }
@@ -235,7 +237,8 @@ fn extract_text_lazy_fast_path(document: &PdfDocument<'_>) -> Result<PdfTextExtr
 /// Lazy extraction with page boundary and content tracking.
 ///
 /// Processes pages one-by-one, tracking byte boundaries and optionally
-/// collecting per-page content. Pre-allocates buffer capacity using an
+/// collecting per-page content. When `config.ranges` is set, only the selected
+/// pages are loaded and extracted. Pre-allocates buffer capacity using an
 /// adaptive strategy to minimize reallocations while maintaining low peak
 /// memory usage.
 ///
@@ -253,7 +256,14 @@ fn extract_text_lazy_with_tracking(
     extraction_config: Option<&crate::core::config::ExtractionConfig>,
 ) -> Result<PdfTextExtractionResult> {
     let mut content = String::new();
-    let page_count = document.pages().len() as usize;
+    let page_indices = match config
+        .selected_page_indices(document.pages().len() as usize)
+        .map_err(|e| PdfError::ExtractionFailed(e.to_string()))?
+    {
+        Some(indices) => indices,
+        None => (0..document.pages().len() as usize).collect(),
+    };
+    let page_count = page_indices.len();
     let mut boundaries = Vec::with_capacity(page_count);
     let mut page_contents = if config.extract_pages {
         Some(Vec::with_capacity(page_count))
@@ -276,8 +286,12 @@ fn extract_text_lazy_with_tracking(
     let mut total_sample_size = 0usize;
     let mut sample_count = 0;
 
-    for (page_idx, page) in document.pages().iter().enumerate() {
+    for (position, &page_idx) in page_indices.iter().enumerate() {
         let page_number = page_idx + 1;
+        let page = document
+            .pages()
+            .get(page_idx as i32)
+            .map_err(|_| PdfError::PageNotFound(page_idx))?;
 
         let text = page
             .text()
@@ -286,7 +300,7 @@ fn extract_text_lazy_with_tracking(
         let page_text_ref = text.all();
         let page_size = page_text_ref.len();
 
-        if page_idx < 5 {
+        if position < 5 {
             total_sample_size += page_size;
             sample_count += 1;
         }
@@ -295,7 +309,7 @@ fn extract_text_lazy_with_tracking(
         if config.insert_page_markers {
             let marker = config.marker_format.replace("{page_num}", &page_number.to_string());
             content.push_str(&marker);
-        } else if page_idx > 0 {
+        } else if position > 0 {
             // Only add separator between pages when markers are disabled
             content.push_str("\n\n");
         }
@@ -327,7 +341,7 @@ fn extract_text_lazy_with_tracking(
             });
         }
 
-        if page_idx == 4 && page_count > 5 && sample_count > 0 {
+        if position == 4 && page_count > 5 && sample_count > 0 {
             let avg_page_size = total_sample_size / sample_count;
             let estimated_remaining = avg_page_size * (page_count - 5);
             let separator_overhead = (page_count - 5) * 3;
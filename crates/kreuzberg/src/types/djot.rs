@@ -64,6 +64,10 @@ pub struct FormattedBlock {
     #[serde(skip_serializing_if = "Option::is_none")]
     pub level: Option<usize>,
 
+    /// Index into `DjotContent::tables` for table blocks
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub table_index: Option<usize>,
+
     /// Inline content within the block
     pub inline_content: Vec<InlineElement>,
 
@@ -105,6 +109,8 @@ pub enum BlockType {
     ThematicBreak,
     RawBlock,
     MathDisplay,
+    /// Position of a table; `table_index` holds its index into `DjotContent::tables`.
+    Table,
 }
 
 /// Inline element within a block.
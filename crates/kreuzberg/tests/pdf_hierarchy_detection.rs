@@ -38,6 +38,7 @@ async fn test_full_hierarchy_extraction() {
             extract_pages: true,
             insert_page_markers: false,
             marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+            ranges: None,
         }),
         pdf_options: Some(PdfConfig {
             extract_images: false,
@@ -150,6 +151,7 @@ async fn test_hierarchy_disabled() {
             extract_pages: true,
             insert_page_markers: false,
             marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+            ranges: None,
         }),
         pdf_options: Some(PdfConfig {
             extract_images: false,
@@ -205,6 +207,7 @@ async fn test_hierarchy_with_explicit_disabled() {
             extract_pages: true,
             insert_page_markers: false,
             marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+            ranges: None,
         }),
         pdf_options: Some(PdfConfig {
             extract_images: false,
@@ -261,6 +264,7 @@ async fn test_hierarchy_different_k_clusters() {
                 extract_pages: true,
                 insert_page_markers: false,
                 marker_format: "\n\n<!-- PAGE {page_num} -->\n\n".to_string(),
+                ranges: None,
             }),
             pdf_options: Some(PdfConfig {
                 extract_images: false,
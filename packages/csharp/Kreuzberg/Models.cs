@@ -2589,6 +2589,12 @@ public sealed class FormattedBlock
     [JsonPropertyName("level")]
     public int? Level { get; set; }
 
+    /// <summary>
+    /// Index into the Djot content's tables for table blocks.
+    /// </summary>
+    [JsonPropertyName("table_index")]
+    public int? TableIndex { get; set; }
+
     /// <summary>
     /// Inline content within the block.
     /// </summary>
@@ -24,6 +24,7 @@ char *kreuzberg_detect_mime_type_from_path(const char *path);
 char *kreuzberg_get_extensions_for_mime(const char *mime_type);
 char *kreuzberg_validate_mime_type(const char *mime_type);
 char *kreuzberg_load_extraction_config_from_file(const char *path);
+char *kreuzberg_config_file_document(const char *path);
 char *kreuzberg_list_embedding_presets(void);
 char *kreuzberg_get_embedding_preset(const char *name);
 
@@ -88,6 +89,14 @@ func ExtractFileSync(path string, config *ExtractionConfig) (*ExtractionResult,
 		return nil, newValidationErrorWithContext("path is required", nil, ErrorCodeValidation, nil)
 	}
 
+	if config != nil && config.Router != nil {
+		mimeType, err := DetectMimeTypeFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+		config = config.routed(config.Router.match(mimeType, path))
+	}
+
 	// Validate chunking parameters if provided in config
 	if config != nil && config.Chunking != nil {
 		if err := validateChunkingConfig(config.Chunking); err != nil {
@@ -95,6 +104,11 @@ func ExtractFileSync(path string, config *ExtractionConfig) (*ExtractionResult,
 		}
 	}
 
+	config, selection, err := preparePageRanges(config)
+	if err != nil {
+		return nil, err
+	}
+
 	cPath := C.CString(path)
 	defer C.free(unsafe.Pointer(cPath))
 
@@ -122,7 +136,12 @@ func ExtractFileSync(path string, config *ExtractionConfig) (*ExtractionResult,
 	}
 	defer C.kreuzberg_free_result(cRes)
 
-	return convertCResult(cRes)
+	result, err := convertCResult(cRes)
+	if err != nil {
+		return nil, err
+	}
+	selection.apply(result)
+	return result, nil
 }
 
 // ExtractBytesSync extracts content and metadata from a byte array with the given MIME type.
@@ -131,6 +150,10 @@ func ExtractBytesSync(data []byte, mimeType string, config *ExtractionConfig) (*
 		return nil, newValidationErrorWithContext("mimeType is required", nil, ErrorCodeValidation, nil)
 	}
 
+	if config != nil && config.Router != nil {
+		config = config.routed(config.Router.match(mimeType, ""))
+	}
+
 	// Validate chunking parameters if provided in config
 	if config != nil && config.Chunking != nil {
 		if err := validateChunkingConfig(config.Chunking); err != nil {
@@ -138,6 +161,11 @@ func ExtractBytesSync(data []byte, mimeType string, config *ExtractionConfig) (*
 		}
 	}
 
+	config, selection, err := preparePageRanges(config)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := C.CBytes(data)
 	defer C.free(buf)
 
@@ -168,7 +196,12 @@ func ExtractBytesSync(data []byte, mimeType string, config *ExtractionConfig) (*
 	}
 	defer C.kreuzberg_free_result(cRes)
 
-	return convertCResult(cRes)
+	result, err := convertCResult(cRes)
+	if err != nil {
+		return nil, err
+	}
+	selection.apply(result)
+	return result, nil
 }
 
 // BatchExtractFilesSync extracts multiple files sequentially but leverages the optimized batch pipeline.
@@ -177,6 +210,10 @@ func BatchExtractFilesSync(paths []string, config *ExtractionConfig) ([]*Extract
 		return []*ExtractionResult{}, nil
 	}
 
+	if config != nil && config.Router != nil {
+		return batchExtractFilesRouted(paths, config)
+	}
+
 	// Validate chunking parameters if provided in config
 	if config != nil && config.Chunking != nil {
 		if err := validateChunkingConfig(config.Chunking); err != nil {
@@ -184,6 +221,11 @@ func BatchExtractFilesSync(paths []string, config *ExtractionConfig) ([]*Extract
 		}
 	}
 
+	config, selection, err := preparePageRanges(config)
+	if err != nil {
+		return nil, err
+	}
+
 	cStrings := make([]*C.char, len(paths))
 	for i, path := range paths {
 		if path == "" {
@@ -215,15 +257,27 @@ func BatchExtractFilesSync(paths []string, config *ExtractionConfig) ([]*Extract
 	}
 	defer C.kreuzberg_free_batch_result(batch)
 
-	return convertCBatchResult(batch)
+	results, err := convertCBatchResult(batch)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		selection.apply(result)
+	}
+	return results, nil
 }
 
 // BatchExtractBytesSync processes multiple in-memory documents in one pass.
+// When config has a Router, items with an empty MimeType are detected from their content.
 func BatchExtractBytesSync(items []BytesWithMime, config *ExtractionConfig) ([]*ExtractionResult, error) {
 	if len(items) == 0 {
 		return []*ExtractionResult{}, nil
 	}
 
+	if config != nil && config.Router != nil {
+		return batchExtractBytesRouted(items, config)
+	}
+
 	// Validate chunking parameters if provided in config
 	if config != nil && config.Chunking != nil {
 		if err := validateChunkingConfig(config.Chunking); err != nil {
@@ -231,6 +285,11 @@ func BatchExtractBytesSync(items []BytesWithMime, config *ExtractionConfig) ([]*
 		}
 	}
 
+	config, selection, err := preparePageRanges(config)
+	if err != nil {
+		return nil, err
+	}
+
 	cItems := make([]C.CBytesWithMime, len(items))
 	cBuffers := make([]unsafe.Pointer, len(items))
 
@@ -280,7 +339,14 @@ func BatchExtractBytesSync(items []BytesWithMime, config *ExtractionConfig) ([]*
 	}
 	defer C.kreuzberg_free_batch_result(batch)
 
-	return convertCBatchResult(batch)
+	results, err := convertCBatchResult(batch)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		selection.apply(result)
+	}
+	return results, nil
 }
 
 // ExtractFileWithContext extracts content and metadata from a file at the given path,
@@ -390,6 +456,7 @@ func convertCResult(cRes *C.CExtractionResult) (*ExtractionResult, error) {
 			result.Metadata.Subject = stringPtr(subj)
 		}
 	}
+	result.Metadata.inferFormat(result.MimeType)
 
 	if err := decodeJSONCString(cRes.chunks_json, &result.Chunks); err != nil {
 		return nil, newSerializationErrorWithContext("failed to decode chunks", err, ErrorCodeValidation, nil)
@@ -496,6 +563,8 @@ func stringPtr(value string) *string {
 }
 
 // LoadExtractionConfigFromFile parses a TOML/YAML/JSON config file into an ExtractionConfig.
+// If the file has a top-level profile key (e.g., profile = "rag"), its settings are layered
+// on top of that profile (see ConfigProfile).
 func LoadExtractionConfigFromFile(path string) (*ExtractionConfig, error) {
 	if path == "" {
 		return nil, newValidationErrorWithContext("config path cannot be empty", nil, ErrorCodeValidation, nil)
@@ -518,7 +587,24 @@ func LoadExtractionConfigFromFile(path string) (*ExtractionConfig, error) {
 	if err := json.Unmarshal([]byte(raw), cfg); err != nil {
 		return nil, newSerializationErrorWithContext("failed to decode config JSON", err, ErrorCodeValidation, nil)
 	}
-	return cfg, nil
+	return applyConfigFileProfile(path, cfg)
+}
+
+// configFileDocument returns the settings of a TOML/YAML/JSON config file as JSON,
+// without the native defaults.
+func configFileDocument(path string) ([]byte, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	ffiMutex.Lock()
+	ptr := C.kreuzberg_config_file_document(cPath)
+	ffiMutex.Unlock()
+
+	if ptr == nil {
+		return nil, lastError()
+	}
+	defer C.kreuzberg_free_string(ptr)
+	return []byte(C.GoString(ptr)), nil
 }
 
 // ConfigFromFile loads an ExtractionConfig from a file (alias for LoadExtractionConfigFromFile).
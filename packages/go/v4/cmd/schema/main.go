@@ -0,0 +1,39 @@
+// Package main provides a command that writes the JSON Schema for Kreuzberg's
+// ExtractionConfig to disk, for validating kreuzberg.json, kreuzberg.yaml and
+// kreuzberg.toml files in editors and CI.
+//
+// Usage:
+//
+//	go run github.com/kreuzberg-dev/kreuzberg/packages/go/v4/cmd/schema -o kreuzberg.schema.json
+//
+// Without -o the schema is written to stdout. The FFI library must be installed
+// (see cmd/install) because allowed values are read from the native validators.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func main() {
+	output := flag.String("o", "", "Output file path (default: stdout)")
+	flag.Parse()
+
+	if *output != "" {
+		if err := kreuzberg.WriteConfigJSONSchema(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	data, err := kreuzberg.ConfigJSONSchema()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
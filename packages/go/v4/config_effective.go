@@ -0,0 +1,160 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ConfigFieldSource reports where an effective config value came from.
+type ConfigFieldSource string
+
+const (
+	// ConfigFieldExplicit marks a value set by the caller (or by a config file or profile).
+	ConfigFieldExplicit ConfigFieldSource = "explicit"
+	// ConfigFieldDefault marks a value filled in by the native library's defaults.
+	ConfigFieldDefault ConfigFieldSource = "default"
+	// ConfigFieldIgnored marks a value that was set but dropped by native normalization,
+	// usually because the native library does not recognize the field.
+	ConfigFieldIgnored ConfigFieldSource = "ignored"
+)
+
+// ConfigField is one leaf of an annotated effective config.
+// Path uses the same dotted JSON field names as DiffConfigs.
+type ConfigField struct {
+	Path   string            `json:"path"`
+	Value  interface{}       `json:"value"`
+	Source ConfigFieldSource `json:"source"`
+}
+
+// EffectiveConfig round-trips cfg through kreuzberg_config_from_json and
+// kreuzberg_config_to_json and returns the fully populated config the native library
+// will actually use, along with an annotated view of every leaf field in declaration
+// order marking whether it was set explicitly or defaulted. A nil cfg yields the
+// native defaults.
+func EffectiveConfig(cfg *ExtractionConfig) (*ExtractionConfig, []ConfigField, error) {
+	if cfg == nil {
+		cfg = &ExtractionConfig{}
+	}
+
+	raw, err := ConfigToJSON(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	effective := &ExtractionConfig{}
+	if err := json.Unmarshal([]byte(raw), effective); err != nil {
+		return nil, nil, newSerializationErrorWithContext("failed to decode effective config JSON", err, ErrorCodeValidation, nil)
+	}
+
+	fields := []ConfigField{}
+	annotateConfigStruct("", reflect.ValueOf(effective).Elem(), reflect.ValueOf(cfg).Elem(), &fields)
+	return effective, fields, nil
+}
+
+// EffectiveConfigFromFile loads a TOML/YAML/JSON config file and explains it like
+// EffectiveConfig. Values set by the file or by the profile it names are explicit,
+// including values the file sets to the native default.
+func EffectiveConfigFromFile(path string) (*ExtractionConfig, []ConfigField, error) {
+	if _, err := LoadExtractionConfigFromFile(path); err != nil {
+		return nil, nil, err
+	}
+
+	explicit, err := explicitConfigFromFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	name, err := readConfigProfileName(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name != "" {
+		if explicit, err = ConfigProfileWithOverrides(name, explicit); err != nil {
+			return nil, nil, err
+		}
+	}
+	return EffectiveConfig(explicit)
+}
+
+// annotateConfigStruct walks the effective config alongside the caller's input.
+// Either value may be an invalid reflect.Value when the section is absent on that side.
+func annotateConfigStruct(prefix string, effective, input reflect.Value, fields *[]ConfigField) {
+	t := effective.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _ := configFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		effField := fieldOrInvalid(effective, i)
+		inField := fieldOrInvalid(input, i)
+
+		fieldType := t.Field(i).Type
+		if fieldType.Kind() == reflect.Ptr && fieldType.Elem().Kind() == reflect.Struct {
+			effElem, inElem := derefOrInvalid(effField), derefOrInvalid(inField)
+			if !effElem.IsValid() && !inElem.IsValid() {
+				continue
+			}
+			if !effElem.IsValid() {
+				annotateIgnoredStruct(path, inElem, fields)
+				continue
+			}
+			annotateConfigStruct(path, effElem, inElem, fields)
+			continue
+		}
+
+		var effValue, inValue interface{}
+		if effField.IsValid() {
+			effValue = configLeafValue(effField)
+		}
+		if inField.IsValid() {
+			inValue = configLeafValue(inField)
+		}
+		switch {
+		case effValue != nil && inValue != nil:
+			*fields = append(*fields, ConfigField{Path: path, Value: effValue, Source: ConfigFieldExplicit})
+		case effValue != nil:
+			*fields = append(*fields, ConfigField{Path: path, Value: effValue, Source: ConfigFieldDefault})
+		case inValue != nil:
+			*fields = append(*fields, ConfigField{Path: path, Value: inValue, Source: ConfigFieldIgnored})
+		}
+	}
+}
+
+// annotateIgnoredStruct records every set leaf of a section the native library dropped.
+func annotateIgnoredStruct(prefix string, input reflect.Value, fields *[]ConfigField) {
+	t := input.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _ := configFieldName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		path := prefix + "." + name
+		field := input.Field(i)
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct {
+			if !field.IsNil() {
+				annotateIgnoredStruct(path, field.Elem(), fields)
+			}
+			continue
+		}
+		if value := configLeafValue(field); value != nil {
+			*fields = append(*fields, ConfigField{Path: path, Value: value, Source: ConfigFieldIgnored})
+		}
+	}
+}
+
+func fieldOrInvalid(v reflect.Value, i int) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Value{}
+	}
+	return v.Field(i)
+}
+
+func derefOrInvalid(v reflect.Value) reflect.Value {
+	if !v.IsValid() || v.IsNil() {
+		return reflect.Value{}
+	}
+	return v.Elem()
+}
@@ -0,0 +1,105 @@
+package kreuzberg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func findConfigField(fields []ConfigField, path string) (ConfigField, bool) {
+	for _, field := range fields {
+		if field.Path == path {
+			return field, true
+		}
+	}
+	return ConfigField{}, false
+}
+
+func TestAnnotateConfigStruct(t *testing.T) {
+	input := NewExtractionConfig(
+		WithOutputFormat("markdown"),
+		WithChunking(WithMaxChars(500), WithChunkSize(500)),
+		WithPages(WithExtractPages(true)),
+	)
+	effective := NewExtractionConfig(
+		WithUseCache(true),
+		WithOutputFormat("markdown"),
+		WithChunking(WithMaxChars(500), WithMaxOverlap(200)),
+	)
+
+	fields := []ConfigField{}
+	annotateConfigStruct("", reflect.ValueOf(effective).Elem(), reflect.ValueOf(input).Elem(), &fields)
+
+	tests := []struct {
+		path   string
+		source ConfigFieldSource
+	}{
+		{"use_cache", ConfigFieldDefault},
+		{"output_format", ConfigFieldExplicit},
+		{"chunking.max_chars", ConfigFieldExplicit},
+		{"chunking.max_overlap", ConfigFieldDefault},
+		{"chunking.chunk_size", ConfigFieldIgnored},
+		{"pages.extract_pages", ConfigFieldIgnored},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			field, ok := findConfigField(fields, tt.path)
+			if !ok {
+				t.Fatalf("missing annotated field %s in %+v", tt.path, fields)
+			}
+			if field.Source != tt.source {
+				t.Errorf("%s source = %s, want %s", tt.path, field.Source, tt.source)
+			}
+		})
+	}
+
+	if _, ok := findConfigField(fields, "force_ocr"); ok {
+		t.Error("fields unset on both sides should be omitted")
+	}
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	effective, fields, err := EffectiveConfig(NewExtractionConfig(WithOutputFormat("markdown")))
+	if err != nil {
+		t.Fatalf("EffectiveConfig() error = %v", err)
+	}
+	if effective.UseCache == nil {
+		t.Error("effective config should include the native use_cache default")
+	}
+
+	if field, ok := findConfigField(fields, "output_format"); !ok || field.Source != ConfigFieldExplicit {
+		t.Errorf("output_format should be explicit, got %+v", field)
+	}
+	if field, ok := findConfigField(fields, "use_cache"); !ok || field.Source != ConfigFieldDefault {
+		t.Errorf("use_cache should be defaulted, got %+v", field)
+	}
+}
+
+func TestEffectiveConfigFromFileMarksFileValuesExplicit(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kreuzberg.toml": "use_cache = true\nprofile = \"archival_ocr\"\nforce_ocr = false\n",
+		"kreuzberg.yaml": "use_cache: true\nprofile: archival_ocr\nforce_ocr: false\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, fields, err := EffectiveConfigFromFile(path)
+			if err != nil {
+				t.Fatalf("EffectiveConfigFromFile() error = %v", err)
+			}
+			for _, path := range []string{"use_cache", "force_ocr", "ocr.backend"} {
+				if field, ok := findConfigField(fields, path); !ok || field.Source != ConfigFieldExplicit {
+					t.Errorf("%s = %+v, want an explicit value from the file or its profile", path, field)
+				}
+			}
+			if field, _ := findConfigField(fields, "force_ocr"); field.Value != false {
+				t.Errorf("force_ocr = %v, want the file's false", field.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package kreuzberg
+
+/*
+#include "internal/ffi/kreuzberg.h"
+#include <stdlib.h>
+#include <stdint.h>
+
+// HTML option enum FFI functions
+int32_t kreuzberg_parse_heading_style(const char *value);
+const char *kreuzberg_heading_style_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_code_block_style(const char *value);
+const char *kreuzberg_code_block_style_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_highlight_style(const char *value);
+const char *kreuzberg_highlight_style_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_list_indent_type(const char *value);
+const char *kreuzberg_list_indent_type_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_whitespace_mode(const char *value);
+const char *kreuzberg_whitespace_mode_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_newline_style(const char *value);
+const char *kreuzberg_newline_style_to_string(int32_t discriminant);
+int32_t kreuzberg_parse_preprocessing_preset(const char *value);
+const char *kreuzberg_preprocessing_preset_to_string(int32_t discriminant);
+*/
+import "C"
+
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// OCRBackend names a built-in OCR backend.
+type OCRBackend string
+
+const (
+	OCRBackendTesseract OCRBackend = "tesseract"
+	OCRBackendEasyOCR   OCRBackend = "easyocr"
+	OCRBackendPaddleOCR OCRBackend = "paddleocr"
+)
+
+// BinarizationMethod selects the image binarization algorithm used during OCR preprocessing.
+type BinarizationMethod string
+
+const (
+	BinarizationMethodOtsu     BinarizationMethod = "otsu"
+	BinarizationMethodAdaptive BinarizationMethod = "adaptive"
+	BinarizationMethodSauvola  BinarizationMethod = "sauvola"
+)
+
+// TokenReductionLevel controls how aggressively tokens are pruned.
+type TokenReductionLevel string
+
+const (
+	TokenReductionOff        TokenReductionLevel = "off"
+	TokenReductionLight      TokenReductionLevel = "light"
+	TokenReductionModerate   TokenReductionLevel = "moderate"
+	TokenReductionAggressive TokenReductionLevel = "aggressive"
+	TokenReductionMaximum    TokenReductionLevel = "maximum"
+)
+
+// KeywordAlgorithm selects the keyword extraction algorithm.
+type KeywordAlgorithm string
+
+const (
+	KeywordAlgorithmYake KeywordAlgorithm = "yake"
+	KeywordAlgorithmRake KeywordAlgorithm = "rake"
+)
+
+// HeadingStyle controls how headings are rendered in HTML-to-Markdown conversion.
+type HeadingStyle string
+
+const (
+	HeadingStyleATX        HeadingStyle = "atx"
+	HeadingStyleUnderlined HeadingStyle = "underlined"
+	HeadingStyleATXClosed  HeadingStyle = "atx_closed"
+)
+
+// CodeBlockStyle controls how code blocks are rendered in HTML-to-Markdown conversion.
+type CodeBlockStyle string
+
+const (
+	CodeBlockStyleIndented  CodeBlockStyle = "indented"
+	CodeBlockStyleBackticks CodeBlockStyle = "backticks"
+	CodeBlockStyleTildes    CodeBlockStyle = "tildes"
+)
+
+// HighlightStyle controls how highlighted (<mark>) text is rendered.
+type HighlightStyle string
+
+const (
+	HighlightStyleDoubleEqual HighlightStyle = "double_equal"
+	HighlightStyleHTML        HighlightStyle = "html"
+	HighlightStyleBold        HighlightStyle = "bold"
+	HighlightStyleNone        HighlightStyle = "none"
+)
+
+// ListIndentType controls whether nested lists are indented with spaces or tabs.
+type ListIndentType string
+
+const (
+	ListIndentTypeSpaces ListIndentType = "spaces"
+	ListIndentTypeTabs   ListIndentType = "tabs"
+)
+
+// WhitespaceMode controls whitespace handling in HTML-to-Markdown conversion.
+type WhitespaceMode string
+
+const (
+	WhitespaceModeDefault       WhitespaceMode = "default"
+	WhitespaceModePreserve      WhitespaceMode = "preserve"
+	WhitespaceModePreserveInner WhitespaceMode = "preserve_inner"
+	WhitespaceModeCollapse      WhitespaceMode = "collapse"
+)
+
+// NewlineStyle controls how hard line breaks are rendered.
+type NewlineStyle string
+
+const (
+	NewlineStyleDefault   NewlineStyle = "default"
+	NewlineStyleSpaces    NewlineStyle = "spaces"
+	NewlineStyleBackslash NewlineStyle = "backslash"
+)
+
+// PreprocessingPreset selects an HTML preprocessing preset.
+type PreprocessingPreset string
+
+const (
+	PreprocessingPresetNone         PreprocessingPreset = "none"
+	PreprocessingPresetConservative PreprocessingPreset = "conservative"
+	PreprocessingPresetAggressive   PreprocessingPreset = "aggressive"
+)
+
+// String returns the canonical backend name.
+func (v OCRBackend) String() string { return string(v) }
+
+// String returns the canonical method name.
+func (v BinarizationMethod) String() string { return string(v) }
+
+// String returns the canonical level name.
+func (v TokenReductionLevel) String() string { return string(v) }
+
+// String returns the canonical algorithm name.
+func (v KeywordAlgorithm) String() string { return string(v) }
+
+// String returns the canonical style name.
+func (v HeadingStyle) String() string { return string(v) }
+
+// String returns the canonical style name.
+func (v CodeBlockStyle) String() string { return string(v) }
+
+// String returns the canonical style name.
+func (v HighlightStyle) String() string { return string(v) }
+
+// String returns the canonical indent type name.
+func (v ListIndentType) String() string { return string(v) }
+
+// String returns the canonical mode name.
+func (v WhitespaceMode) String() string { return string(v) }
+
+// String returns the canonical style name.
+func (v NewlineStyle) String() string { return string(v) }
+
+// String returns the canonical preset name.
+func (v PreprocessingPreset) String() string { return string(v) }
+
+// String returns the format name.
+func (v OutputFormat) String() string { return string(v) }
+
+// String returns the format name.
+func (v ResultFormat) String() string { return string(v) }
+
+// ParseOCRBackend validates value against the native OCR backend list.
+func ParseOCRBackend(value string) (OCRBackend, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if err := ValidateOCRBackend(normalized); err != nil {
+		return "", err
+	}
+	return OCRBackend(normalized), nil
+}
+
+// ParseBinarizationMethod validates value against the native binarization methods.
+func ParseBinarizationMethod(value string) (BinarizationMethod, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if err := ValidateBinarizationMethod(normalized); err != nil {
+		return "", err
+	}
+	return BinarizationMethod(normalized), nil
+}
+
+// ParseTokenReductionLevel validates value against the native token reduction levels.
+func ParseTokenReductionLevel(value string) (TokenReductionLevel, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if err := ValidateTokenReductionLevel(normalized); err != nil {
+		return "", err
+	}
+	return TokenReductionLevel(normalized), nil
+}
+
+// ParseKeywordAlgorithm parses a keyword algorithm name ("yake" or "rake").
+func ParseKeywordAlgorithm(value string) (KeywordAlgorithm, error) {
+	switch algorithm := KeywordAlgorithm(strings.ToLower(strings.TrimSpace(value))); algorithm {
+	case KeywordAlgorithmYake, KeywordAlgorithmRake:
+		return algorithm, nil
+	default:
+		return "", newValidationErrorWithContext(fmt.Sprintf("invalid keyword algorithm: %s", value), nil, ErrorCodeValidation, nil)
+	}
+}
+
+// ParseOutputFormat validates value via the native output format validator.
+// Aliases such as "md" and "text" are accepted and returned unchanged.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if err := ValidateOutputFormat(normalized); err != nil {
+		return "", err
+	}
+	return OutputFormat(normalized), nil
+}
+
+// ParseResultFormat parses a result format name ("unified" or "element_based").
+func ParseResultFormat(value string) (ResultFormat, error) {
+	switch format := ResultFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case ResultFormatUnified, ResultFormatElementBased:
+		return format, nil
+	default:
+		return "", newValidationErrorWithContext(fmt.Sprintf("invalid result format: %s", value), nil, ErrorCodeValidation, nil)
+	}
+}
+
+// ParseHeadingStyle parses a heading style via kreuzberg_parse_heading_style.
+// Aliases such as "atx-closed" are normalized to their canonical names.
+func ParseHeadingStyle(value string) (HeadingStyle, error) {
+	name, err := parseNativeEnum("heading style", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_heading_style(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_heading_style_to_string(d) })
+	return HeadingStyle(name), err
+}
+
+// ParseCodeBlockStyle parses a code block style via kreuzberg_parse_code_block_style.
+func ParseCodeBlockStyle(value string) (CodeBlockStyle, error) {
+	name, err := parseNativeEnum("code block style", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_code_block_style(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_code_block_style_to_string(d) })
+	return CodeBlockStyle(name), err
+}
+
+// ParseHighlightStyle parses a highlight style via kreuzberg_parse_highlight_style.
+// Aliases such as "==" are normalized to their canonical names.
+func ParseHighlightStyle(value string) (HighlightStyle, error) {
+	name, err := parseNativeEnum("highlight style", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_highlight_style(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_highlight_style_to_string(d) })
+	return HighlightStyle(name), err
+}
+
+// ParseListIndentType parses a list indent type via kreuzberg_parse_list_indent_type.
+func ParseListIndentType(value string) (ListIndentType, error) {
+	name, err := parseNativeEnum("list indent type", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_list_indent_type(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_list_indent_type_to_string(d) })
+	return ListIndentType(name), err
+}
+
+// ParseWhitespaceMode parses a whitespace mode via kreuzberg_parse_whitespace_mode.
+func ParseWhitespaceMode(value string) (WhitespaceMode, error) {
+	name, err := parseNativeEnum("whitespace mode", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_whitespace_mode(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_whitespace_mode_to_string(d) })
+	return WhitespaceMode(name), err
+}
+
+// ParseNewlineStyle parses a newline style via kreuzberg_parse_newline_style.
+func ParseNewlineStyle(value string) (NewlineStyle, error) {
+	name, err := parseNativeEnum("newline style", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_newline_style(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_newline_style_to_string(d) })
+	return NewlineStyle(name), err
+}
+
+// ParsePreprocessingPreset parses an HTML preprocessing preset via kreuzberg_parse_preprocessing_preset.
+func ParsePreprocessingPreset(value string) (PreprocessingPreset, error) {
+	name, err := parseNativeEnum("preprocessing preset", value,
+		func(v *C.char) C.int32_t { return C.kreuzberg_parse_preprocessing_preset(v) },
+		func(d C.int32_t) *C.char { return C.kreuzberg_preprocessing_preset_to_string(d) })
+	return PreprocessingPreset(name), err
+}
+
+// parseNativeEnum resolves value to a discriminant with parse, then back to the
+// canonical name with toString, so aliases accepted by Rust normalize consistently.
+func parseNativeEnum(kind, value string, parse func(*C.char) C.int32_t, toString func(C.int32_t) *C.char) (string, error) {
+	if value == "" {
+		return "", newValidationErrorWithContext(kind+" cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+
+	discriminant := parse(cValue)
+	if discriminant < 0 {
+		return "", newValidationErrorWithContext(fmt.Sprintf("invalid %s: %s", kind, value), nil, ErrorCodeValidation, nil)
+	}
+
+	name := toString(discriminant)
+	if name == nil {
+		return "", newValidationErrorWithContext(fmt.Sprintf("invalid %s: %s", kind, value), nil, ErrorCodeValidation, nil)
+	}
+	return C.GoString(name), nil
+}
+
+// WithTypedOutputFormat is the typed counterpart of WithOutputFormat.
+func WithTypedOutputFormat(format OutputFormat) ExtractionOption {
+	return WithOutputFormat(string(format))
+}
+
+// WithTypedResultFormat is the typed counterpart of WithResultFormat.
+func WithTypedResultFormat(format ResultFormat) ExtractionOption {
+	return WithResultFormat(string(format))
+}
+
+// WithTypedOCRBackend is the typed counterpart of WithOCRBackend.
+func WithTypedOCRBackend(backend OCRBackend) OCROption {
+	return WithOCRBackend(string(backend))
+}
+
+// WithTypedBinarizationMethod is the typed counterpart of WithBinarizationMode.
+func WithTypedBinarizationMethod(method BinarizationMethod) ImagePreprocessingOption {
+	return WithBinarizationMode(string(method))
+}
+
+// WithTypedTokenReductionLevel is the typed counterpart of WithTokenReductionMode.
+func WithTypedTokenReductionLevel(level TokenReductionLevel) TokenReductionOption {
+	return WithTokenReductionMode(string(level))
+}
+
+// WithTypedKeywordAlgorithm is the typed counterpart of WithKeywordAlgorithm.
+func WithTypedKeywordAlgorithm(algorithm KeywordAlgorithm) KeywordOption {
+	return WithKeywordAlgorithm(string(algorithm))
+}
+
+// WithTypedHeadingStyle is the typed counterpart of WithHeadingStyle.
+func WithTypedHeadingStyle(style HeadingStyle) HTMLConversionOption {
+	return WithHeadingStyle(string(style))
+}
+
+// WithTypedCodeBlockStyle is the typed counterpart of WithCodeBlockStyle.
+func WithTypedCodeBlockStyle(style CodeBlockStyle) HTMLConversionOption {
+	return WithCodeBlockStyle(string(style))
+}
+
+// WithTypedHighlightStyle is the typed counterpart of WithHighlightStyle.
+func WithTypedHighlightStyle(style HighlightStyle) HTMLConversionOption {
+	return WithHighlightStyle(string(style))
+}
+
+// WithTypedListIndentType is the typed counterpart of WithListIndentType.
+func WithTypedListIndentType(indentType ListIndentType) HTMLConversionOption {
+	return WithListIndentType(string(indentType))
+}
+
+// WithTypedWhitespaceMode is the typed counterpart of WithWhitespaceMode.
+func WithTypedWhitespaceMode(mode WhitespaceMode) HTMLConversionOption {
+	return WithWhitespaceMode(string(mode))
+}
+
+// WithTypedNewlineStyle is the typed counterpart of WithNewlineStyle.
+func WithTypedNewlineStyle(style NewlineStyle) HTMLConversionOption {
+	return WithNewlineStyle(string(style))
+}
+
+// WithTypedPreprocessingPreset is the typed counterpart of WithHTMLPreprocessingPreset.
+func WithTypedPreprocessingPreset(preset PreprocessingPreset) HTMLPreprocessingOption {
+	return WithHTMLPreprocessingPreset(string(preset))
+}
@@ -0,0 +1,104 @@
+package kreuzberg_test
+
+import (
+	"errors"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func TestParseHTMLEnums(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (string, error)
+		input string
+		want  string
+	}{
+		{"heading style", parseAs(kreuzberg.ParseHeadingStyle), "atx_closed", string(kreuzberg.HeadingStyleATXClosed)},
+		{"code block style", parseAs(kreuzberg.ParseCodeBlockStyle), "backticks", string(kreuzberg.CodeBlockStyleBackticks)},
+		{"highlight style", parseAs(kreuzberg.ParseHighlightStyle), "double_equal", string(kreuzberg.HighlightStyleDoubleEqual)},
+		{"list indent type", parseAs(kreuzberg.ParseListIndentType), "tabs", string(kreuzberg.ListIndentTypeTabs)},
+		{"whitespace mode", parseAs(kreuzberg.ParseWhitespaceMode), "preserve_inner", string(kreuzberg.WhitespaceModePreserveInner)},
+		{"newline style", parseAs(kreuzberg.ParseNewlineStyle), "backslash", string(kreuzberg.NewlineStyleBackslash)},
+		{"preprocessing preset", parseAs(kreuzberg.ParsePreprocessingPreset), "aggressive", string(kreuzberg.PreprocessingPresetAggressive)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.input)
+			if err != nil {
+				t.Fatalf("parse(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parse(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+
+			_, err = tt.parse("not-a-valid-value")
+			var validationErr *kreuzberg.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("expected ValidationError for invalid value, got %v", err)
+			}
+			if _, err := tt.parse(""); err == nil {
+				t.Error("expected error for empty value")
+			}
+		})
+	}
+}
+
+func parseAs[T ~string](parse func(string) (T, error)) func(string) (string, error) {
+	return func(value string) (string, error) {
+		v, err := parse(value)
+		return string(v), err
+	}
+}
+
+func TestParseKeywordAndResultFormat(t *testing.T) {
+	if got, err := kreuzberg.ParseKeywordAlgorithm(" RAKE "); err != nil || got != kreuzberg.KeywordAlgorithmRake {
+		t.Errorf("ParseKeywordAlgorithm() = %q, %v", got, err)
+	}
+	if _, err := kreuzberg.ParseKeywordAlgorithm("textrank"); err == nil {
+		t.Error("expected error for unknown keyword algorithm")
+	}
+	if got, err := kreuzberg.ParseResultFormat("element_based"); err != nil || got != kreuzberg.ResultFormatElementBased {
+		t.Errorf("ParseResultFormat() = %q, %v", got, err)
+	}
+	if _, err := kreuzberg.ParseResultFormat("tree"); err == nil {
+		t.Error("expected error for unknown result format")
+	}
+}
+
+func TestTypedOptionHelpers(t *testing.T) {
+	cfg := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithTypedOutputFormat(kreuzberg.OutputFormatMarkdown),
+		kreuzberg.WithOCR(kreuzberg.WithTypedOCRBackend(kreuzberg.OCRBackendTesseract)),
+		kreuzberg.WithKeywords(kreuzberg.WithTypedKeywordAlgorithm(kreuzberg.KeywordAlgorithmYake)),
+		kreuzberg.WithHTMLOptions(
+			kreuzberg.WithTypedHeadingStyle(kreuzberg.HeadingStyleUnderlined),
+			kreuzberg.WithTypedCodeBlockStyle(kreuzberg.CodeBlockStyleTildes),
+			kreuzberg.WithHTMLPreprocessing(kreuzberg.WithTypedPreprocessingPreset(kreuzberg.PreprocessingPresetConservative)),
+		),
+	)
+
+	if cfg.OutputFormat != "markdown" {
+		t.Errorf("OutputFormat = %q, want markdown", cfg.OutputFormat)
+	}
+	if cfg.OCR == nil || cfg.OCR.Backend != "tesseract" {
+		t.Error("OCR backend should be tesseract")
+	}
+	if cfg.Keywords == nil || cfg.Keywords.Algorithm != "yake" {
+		t.Error("keyword algorithm should be yake")
+	}
+	html := cfg.HTMLOptions
+	if html == nil || html.HeadingStyle == nil || *html.HeadingStyle != "underlined" {
+		t.Error("heading style should be underlined")
+	}
+	if html.CodeBlockStyle == nil || *html.CodeBlockStyle != "tildes" {
+		t.Error("code block style should be tildes")
+	}
+	if html.Preprocessing == nil || html.Preprocessing.Preset == nil || *html.Preprocessing.Preset != "conservative" {
+		t.Error("preprocessing preset should be conservative")
+	}
+	if kreuzberg.HeadingStyleATX.String() != "atx" {
+		t.Errorf("HeadingStyleATX.String() = %q", kreuzberg.HeadingStyleATX.String())
+	}
+}
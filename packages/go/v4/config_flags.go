@@ -0,0 +1,321 @@
+package kreuzberg
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// configFlagParsers holds the enum parsers for string fields. They accept the aliases
+// the native library accepts and return the canonical value, which is what the flag
+// stores. Fields without a parser are checked against staticSchemaConstraints.
+var configFlagParsers = map[string]func(string) (string, error){
+	"ExtractionConfig.output_format":               configFlagParser(ParseOutputFormat),
+	"ExtractionConfig.result_format":               configFlagParser(ParseResultFormat),
+	"OCRConfig.backend":                            configFlagParser(ParseOCRBackend),
+	"OCRConfig.output_format":                      configFlagParser(ParseOutputFormat),
+	"ImagePreprocessingConfig.binarization_method": configFlagParser(ParseBinarizationMethod),
+	"TokenReductionConfig.mode":                    configFlagParser(ParseTokenReductionLevel),
+	"KeywordConfig.algorithm":                      configFlagParser(ParseKeywordAlgorithm),
+	"HTMLConversionOptions.heading_style":          configFlagParser(ParseHeadingStyle),
+	"HTMLConversionOptions.code_block_style":       configFlagParser(ParseCodeBlockStyle),
+	"HTMLConversionOptions.highlight_style":        configFlagParser(ParseHighlightStyle),
+	"HTMLConversionOptions.list_indent_type":       configFlagParser(ParseListIndentType),
+	"HTMLConversionOptions.whitespace_mode":        configFlagParser(ParseWhitespaceMode),
+	"HTMLConversionOptions.newline_style":          configFlagParser(ParseNewlineStyle),
+	"HTMLPreprocessingOptions.preset":              configFlagParser(ParsePreprocessingPreset),
+}
+
+func configFlagParser[T ~string](parse func(string) (T, error)) func(string) (string, error) {
+	return func(raw string) (string, error) {
+		value, err := parse(raw)
+		return string(value), err
+	}
+}
+
+// ConfigFlags binds every ExtractionConfig field to a flag.FlagSet.
+//
+// Flag names are the dotted JSON field names, e.g. -ocr.tesseract_config.psm or
+// -chunking.max_chars. Values are parsed according to the field type and checked
+// against the same constraints as ConfigJSONSchema as soon as the flag is parsed, so
+// invalid input is reported by flag.FlagSet.Parse. []string fields accept a
+// comma-separated list and may be repeated; ngram_range takes "min,max".
+type ConfigFlags struct {
+	flags []*configFlag
+}
+
+// BindConfigFlags registers a flag on fs for every ExtractionConfig field, including
+// nested sections. prefix, if non-empty, is prepended to every flag name followed by a
+// dot (e.g., prefix "kreuzberg" yields -kreuzberg.ocr.language).
+func BindConfigFlags(fs *flag.FlagSet, prefix string) *ConfigFlags {
+	cf := &ConfigFlags{}
+	cf.bindStruct(fs, prefix, nil, reflect.TypeOf(ExtractionConfig{}))
+	return cf
+}
+
+// Apply returns a copy of base with every flag that was set on the command line
+// overlaid on top. Sections and fields whose flags were not set are left untouched.
+// base is not modified and may be nil.
+func (cf *ConfigFlags) Apply(base *ExtractionConfig) *ExtractionConfig {
+	cfg := MergeConfigs(base, nil)
+	root := reflect.ValueOf(cfg).Elem()
+	for _, f := range cf.flags {
+		if !f.value.IsValid() {
+			continue
+		}
+		target := root
+		for _, idx := range f.index[:len(f.index)-1] {
+			field := target.Field(idx)
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			target = field.Elem()
+		}
+		target.Field(f.index[len(f.index)-1]).Set(cloneConfigValue(f.value))
+	}
+	return cfg
+}
+
+// Changed returns the names of the config flags that were set, in registration order.
+func (cf *ConfigFlags) Changed() []string {
+	names := []string{}
+	for _, f := range cf.flags {
+		if f.value.IsValid() {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+func (cf *ConfigFlags) bindStruct(fs *flag.FlagSet, prefix string, index []int, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _ := configFieldName(field)
+		if jsonName == "" {
+			continue
+		}
+		name := jsonName
+		if prefix != "" {
+			name = prefix + "." + jsonName
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			cf.bindStruct(fs, name, fieldIndex, field.Type.Elem())
+			continue
+		}
+
+		kind := configFlagKind(field.Type)
+		if kind == "" {
+			continue
+		}
+		f := &configFlag{
+			name:  name,
+			index: fieldIndex,
+			typ:   field.Type,
+			key:   t.Name() + "." + jsonName,
+			kind:  kind,
+		}
+		fs.Var(f, name, f.usage())
+		cf.flags = append(cf.flags, f)
+	}
+}
+
+// configFlagKind returns a human-readable kind for supported field types, or "".
+func configFlagKind(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	case reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String {
+			return "list"
+		}
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Int {
+			return "int list"
+		}
+	}
+	return ""
+}
+
+// configFlag implements flag.Value for a single config leaf field.
+type configFlag struct {
+	name  string
+	index []int
+	typ   reflect.Type
+	key   string
+	kind  string
+	value reflect.Value
+}
+
+func (f *configFlag) usage() string {
+	usage := fmt.Sprintf("set %s (%s)", f.name, f.kind)
+	constraint, ok := staticSchemaConstraints[f.key]
+	switch {
+	case ok && len(constraint.Enum) > 0:
+		usage += "; one of: " + strings.Join(constraint.Enum, ", ")
+	case ok && constraint.Minimum != nil && constraint.Maximum != nil:
+		usage += fmt.Sprintf("; range %g to %g", *constraint.Minimum, *constraint.Maximum)
+	case ok && constraint.Minimum != nil:
+		usage += fmt.Sprintf("; minimum %g", *constraint.Minimum)
+	}
+	if f.kind == "list" {
+		usage += "; comma-separated, repeatable"
+	}
+	return usage
+}
+
+// String returns the value set on the command line, or "" when unset.
+func (f *configFlag) String() string {
+	if f == nil || !f.value.IsValid() {
+		return ""
+	}
+	v := f.value
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// IsBoolFlag lets boolean fields be set with a bare -name.
+func (f *configFlag) IsBoolFlag() bool {
+	return f.kind == "bool"
+}
+
+// Set parses and validates raw for the field's type.
+func (f *configFlag) Set(raw string) error {
+	elemType := f.typ
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	parsed := reflect.New(elemType).Elem()
+	switch elemType.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		parsed.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		if err := f.checkRange(float64(n)); err != nil {
+			return err
+		}
+		parsed.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		if err := f.checkRange(n); err != nil {
+			return err
+		}
+		parsed.SetFloat(n)
+	case reflect.String:
+		value, err := f.parseString(raw)
+		if err != nil {
+			return err
+		}
+		parsed.SetString(value)
+	case reflect.Slice:
+		items := splitFlagList(raw)
+		if f.value.IsValid() {
+			items = append(f.listValue(), items...)
+		}
+		parsed = reflect.ValueOf(items)
+	case reflect.Array:
+		items := splitFlagList(raw)
+		if len(items) != elemType.Len() {
+			return fmt.Errorf("expected %d comma-separated integers, got %q", elemType.Len(), raw)
+		}
+		for i, item := range items {
+			n, err := strconv.Atoi(item)
+			if err != nil {
+				return fmt.Errorf("invalid integer %q", item)
+			}
+			parsed.Index(i).SetInt(int64(n))
+		}
+	default:
+		return fmt.Errorf("unsupported flag type %s", f.typ)
+	}
+
+	if f.typ.Kind() == reflect.Ptr {
+		ptr := reflect.New(elemType)
+		ptr.Elem().Set(parsed)
+		parsed = ptr
+	}
+	f.value = parsed
+	return nil
+}
+
+// listValue returns the current value of a list flag.
+func (f *configFlag) listValue() []string {
+	items, _ := f.value.Interface().([]string)
+	return items
+}
+
+func (f *configFlag) checkRange(n float64) error {
+	constraint, ok := staticSchemaConstraints[f.key]
+	if !ok {
+		return nil
+	}
+	if constraint.Minimum != nil && n < *constraint.Minimum {
+		return fmt.Errorf("must be at least %g", *constraint.Minimum)
+	}
+	if constraint.Maximum != nil && n > *constraint.Maximum {
+		return fmt.Errorf("must be at most %g", *constraint.Maximum)
+	}
+	return nil
+}
+
+// parseString validates raw and returns the value to store, with enum aliases
+// replaced by their canonical names.
+func (f *configFlag) parseString(raw string) (string, error) {
+	if parse, ok := configFlagParsers[f.key]; ok {
+		return parse(raw)
+	}
+	constraint, ok := staticSchemaConstraints[f.key]
+	if !ok || len(constraint.Enum) == 0 {
+		return raw, nil
+	}
+	for _, allowed := range constraint.Enum {
+		if raw == allowed {
+			return raw, nil
+		}
+	}
+	return "", fmt.Errorf("must be one of: %s", strings.Join(constraint.Enum, ", "))
+}
+
+func splitFlagList(raw string) []string {
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,123 @@
+package kreuzberg_test
+
+import (
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func newConfigFlagSet(t *testing.T) (*flag.FlagSet, *kreuzberg.ConfigFlags) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs, kreuzberg.BindConfigFlags(fs, "")
+}
+
+func TestBindConfigFlagsApply(t *testing.T) {
+	fs, flags := newConfigFlagSet(t)
+	err := fs.Parse([]string{
+		"-ocr.tesseract_config.psm=6",
+		"-chunking.max_chars", "800",
+		"-force_ocr",
+		"-html_options.strip_tags=script,style",
+		"-html_options.strip_tags=nav",
+		"-keywords.ngram_range=1,2",
+		"-keywords.algorithm=rake",
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	base := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithUseCache(false),
+		kreuzberg.WithOCR(kreuzberg.WithOCRLanguage("deu")),
+	)
+	cfg := flags.Apply(base)
+
+	if cfg.UseCache == nil || *cfg.UseCache {
+		t.Error("unset flags should leave base values untouched")
+	}
+	if cfg.OCR.Language == nil || *cfg.OCR.Language != "deu" {
+		t.Error("sections should be overlaid, not replaced")
+	}
+	if cfg.OCR.Tesseract == nil || cfg.OCR.Tesseract.PSM == nil || *cfg.OCR.Tesseract.PSM != 6 {
+		t.Error("nested flag should set ocr.tesseract_config.psm")
+	}
+	if cfg.Chunking == nil || cfg.Chunking.MaxChars == nil || *cfg.Chunking.MaxChars != 800 {
+		t.Error("chunking.max_chars should be 800")
+	}
+	if cfg.ForceOCR == nil || !*cfg.ForceOCR {
+		t.Error("bare boolean flag should set force_ocr")
+	}
+	if want := []string{"script", "style", "nav"}; !reflect.DeepEqual(cfg.HTMLOptions.StripTags, want) {
+		t.Errorf("StripTags = %v, want %v", cfg.HTMLOptions.StripTags, want)
+	}
+	if cfg.Keywords.NgramRange == nil || *cfg.Keywords.NgramRange != [2]int{1, 2} {
+		t.Errorf("NgramRange = %v, want [1 2]", cfg.Keywords.NgramRange)
+	}
+	if cfg.Keywords.Algorithm != "rake" {
+		t.Errorf("Algorithm = %q, want rake", cfg.Keywords.Algorithm)
+	}
+	if base.OCR.Tesseract != nil {
+		t.Error("Apply must not modify base")
+	}
+
+	if changed := flags.Changed(); len(changed) != 6 {
+		t.Errorf("Changed() = %v, want 6 flags", changed)
+	}
+}
+
+func TestBindConfigFlagsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+	}{
+		{"psm above range", "-ocr.tesseract_config.psm=20"},
+		{"non-integer", "-chunking.max_chars=many"},
+		{"invalid enum", "-html_options.heading_style=fancy"},
+		{"invalid boolean", "-use_cache=maybe"},
+		{"short ngram range", "-keywords.ngram_range=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, _ := newConfigFlagSet(t)
+			if err := fs.Parse([]string{tt.arg}); err == nil {
+				t.Errorf("Parse(%s) should fail", tt.arg)
+			}
+		})
+	}
+}
+
+func TestBindConfigFlagsEnumAliases(t *testing.T) {
+	fs, flags := newConfigFlagSet(t)
+	if err := fs.Parse([]string{"-html_options.heading_style=atx-closed", "-keywords.algorithm=RAKE"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	cfg := flags.Apply(nil)
+	if cfg.HTMLOptions == nil || cfg.HTMLOptions.HeadingStyle == nil || *cfg.HTMLOptions.HeadingStyle != string(kreuzberg.HeadingStyleATXClosed) {
+		t.Errorf("HeadingStyle = %v, want %s", cfg.HTMLOptions.HeadingStyle, kreuzberg.HeadingStyleATXClosed)
+	}
+	if cfg.Keywords == nil || cfg.Keywords.Algorithm != "rake" {
+		t.Errorf("Algorithm = %v, want rake", cfg.Keywords)
+	}
+}
+
+func TestBindConfigFlagsPrefixAndUsage(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	kreuzberg.BindConfigFlags(fs, "kb")
+
+	f := fs.Lookup("kb.ocr.tesseract_config.psm")
+	if f == nil {
+		t.Fatal("prefixed flag kb.ocr.tesseract_config.psm not registered")
+	}
+	if !strings.Contains(f.Usage, "0 to 13") {
+		t.Errorf("usage should describe the valid range, got %q", f.Usage)
+	}
+	if fs.Lookup("ocr.language") != nil {
+		t.Error("unprefixed flags should not be registered when a prefix is given")
+	}
+}
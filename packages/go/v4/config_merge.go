@@ -0,0 +1,222 @@
+package kreuzberg
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ConfigChange describes a single field that differs between two configs.
+// Path uses the JSON field names joined with dots (e.g., "ocr.tesseract_config.psm"),
+// matching the paths accepted by ConfigGetField. Old and New hold the dereferenced
+// values, or nil when the field is unset on that side.
+type ConfigChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// MergeConfigs returns a new ExtractionConfig with override layered on top of base.
+//
+// Merge semantics follow kreuzberg_config_merge, refined for Go's optional fields:
+//   - nil pointers, nil slices and empty omitempty strings mean "unset" and never
+//     replace a value from base
+//   - nested config sections are merged field by field rather than replaced wholesale
+//   - slices are replaced, not appended
+//   - fields serialized without omitempty (e.g., FontConfig.Enabled) are always taken
+//     from override when their enclosing section is present
+//
+// Neither input is modified. Either argument may be nil.
+func MergeConfigs(base, override *ExtractionConfig) *ExtractionConfig {
+	merged := &ExtractionConfig{}
+	if base != nil {
+		merged = cloneConfigValue(reflect.ValueOf(base)).Interface().(*ExtractionConfig)
+	}
+	if override != nil {
+		mergeConfigStruct(reflect.ValueOf(merged).Elem(), reflect.ValueOf(override).Elem())
+	}
+	return merged
+}
+
+// DiffConfigs reports every field whose effective value differs between a and b.
+// Changes are listed in struct declaration order, descending into nested sections
+// so that only the leaf fields that changed are reported. Slices are compared as a
+// whole. A nil config is treated as an empty one.
+func DiffConfigs(a, b *ExtractionConfig) []ConfigChange {
+	if a == nil {
+		a = &ExtractionConfig{}
+	}
+	if b == nil {
+		b = &ExtractionConfig{}
+	}
+	changes := []ConfigChange{}
+	diffConfigStruct("", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), &changes)
+	return changes
+}
+
+// configFieldName returns the JSON name of a struct field and whether it is omitempty.
+// Fields tagged "-" or unexported fields return an empty name.
+func configFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+func mergeConfigStruct(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		name, omitEmpty := configFieldName(src.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		srcField := src.Field(i)
+		dstField := dst.Field(i)
+
+		switch srcField.Kind() {
+		case reflect.Ptr:
+			if srcField.IsNil() {
+				continue
+			}
+			if srcField.Elem().Kind() == reflect.Struct && !dstField.IsNil() {
+				mergeConfigStruct(dstField.Elem(), srcField.Elem())
+				continue
+			}
+			dstField.Set(cloneConfigValue(srcField))
+		case reflect.Slice, reflect.Map:
+			if srcField.IsNil() {
+				continue
+			}
+			dstField.Set(cloneConfigValue(srcField))
+		case reflect.Struct:
+			mergeConfigStruct(dstField, srcField)
+		default:
+			if omitEmpty && srcField.IsZero() {
+				continue
+			}
+			dstField.Set(srcField)
+		}
+	}
+}
+
+// configRouterType is the type of ExtractionConfig.Router.
+var configRouterType = reflect.TypeOf((*ConfigRouter)(nil))
+
+// cloneConfigValue deep-copies config values so merged configs never alias their inputs.
+// A ConfigRouter is shared rather than copied.
+func cloneConfigValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		if v.Type() == configRouterType {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(cloneConfigValue(v.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(cloneConfigValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), cloneConfigValue(iter.Value()))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).PkgPath != "" {
+				continue
+			}
+			out.Field(i).Set(cloneConfigValue(v.Field(i)))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func diffConfigStruct(prefix string, a, b reflect.Value, changes *[]ConfigChange) {
+	for i := 0; i < a.NumField(); i++ {
+		name, _ := configFieldName(a.Type().Field(i))
+		if name == "" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		diffConfigValue(path, a.Field(i), b.Field(i), changes)
+	}
+}
+
+func diffConfigValue(path string, a, b reflect.Value, changes *[]ConfigChange) {
+	if a.Kind() == reflect.Ptr && a.Type().Elem().Kind() == reflect.Struct {
+		if a.IsNil() && b.IsNil() {
+			return
+		}
+		zero := reflect.New(a.Type().Elem()).Elem()
+		aElem, bElem := zero, zero
+		if !a.IsNil() {
+			aElem = a.Elem()
+		}
+		if !b.IsNil() {
+			bElem = b.Elem()
+		}
+		diffConfigStruct(path, aElem, bElem, changes)
+		return
+	}
+	if a.Kind() == reflect.Struct {
+		diffConfigStruct(path, a, b, changes)
+		return
+	}
+
+	oldValue := configLeafValue(a)
+	newValue := configLeafValue(b)
+	if reflect.DeepEqual(oldValue, newValue) {
+		return
+	}
+	*changes = append(*changes, ConfigChange{Path: path, Old: oldValue, New: newValue})
+}
+
+// configLeafValue dereferences a leaf config value, returning nil when it is unset.
+func configLeafValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Elem().Interface()
+	case reflect.Slice, reflect.Map:
+		if v.IsNil() || v.Len() == 0 {
+			return nil
+		}
+		return v.Interface()
+	case reflect.String:
+		if v.Len() == 0 {
+			return nil
+		}
+		return v.Interface()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,192 @@
+package kreuzberg_test
+
+import (
+	"reflect"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func TestMergeConfigs(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     *kreuzberg.ExtractionConfig
+		override *kreuzberg.ExtractionConfig
+		check    func(t *testing.T, merged *kreuzberg.ExtractionConfig)
+	}{
+		{
+			name: "nil inputs produce empty config",
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if merged == nil {
+					t.Fatal("merged config should not be nil")
+				}
+				if !reflect.DeepEqual(merged, &kreuzberg.ExtractionConfig{}) {
+					t.Errorf("expected empty config, got %+v", merged)
+				}
+			},
+		},
+		{
+			name: "nil pointer in override keeps base value",
+			base: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithUseCache(true),
+				kreuzberg.WithForceOCR(true),
+			),
+			override: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithUseCache(false),
+			),
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if merged.UseCache == nil || *merged.UseCache {
+					t.Error("UseCache should be overridden to false")
+				}
+				if merged.ForceOCR == nil || !*merged.ForceOCR {
+					t.Error("ForceOCR should remain true")
+				}
+			},
+		},
+		{
+			name: "nested sections merge field by field",
+			base: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithOCR(
+					kreuzberg.WithOCRBackend("tesseract"),
+					kreuzberg.WithTesseract(kreuzberg.WithTesseractPSM(3), kreuzberg.WithTesseractOEM(1)),
+				),
+			),
+			override: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithOCR(
+					kreuzberg.WithOCRLanguage("deu"),
+					kreuzberg.WithTesseract(kreuzberg.WithTesseractPSM(6)),
+				),
+			),
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if merged.OCR == nil || merged.OCR.Backend != "tesseract" {
+					t.Fatal("OCR backend should be kept from base")
+				}
+				if merged.OCR.Language == nil || *merged.OCR.Language != "deu" {
+					t.Error("OCR language should come from override")
+				}
+				if merged.OCR.Tesseract == nil || *merged.OCR.Tesseract.PSM != 6 {
+					t.Error("Tesseract PSM should be overridden to 6")
+				}
+				if merged.OCR.Tesseract.OEM == nil || *merged.OCR.Tesseract.OEM != 1 {
+					t.Error("Tesseract OEM should be kept from base")
+				}
+			},
+		},
+		{
+			name: "slices are replaced not appended",
+			base: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithPdfOptions(kreuzberg.WithPdfPasswords([]string{"a", "b"})),
+			),
+			override: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithPdfOptions(kreuzberg.WithPdfPasswords([]string{"c"})),
+			),
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if !reflect.DeepEqual(merged.PdfOptions.Passwords, []string{"c"}) {
+					t.Errorf("Passwords = %v, want [c]", merged.PdfOptions.Passwords)
+				}
+			},
+		},
+		{
+			name: "empty strings do not clear base values",
+			base: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithOutputFormat("markdown"),
+			),
+			override: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithResultFormat("element_based"),
+			),
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if merged.OutputFormat != "markdown" {
+					t.Errorf("OutputFormat = %q, want markdown", merged.OutputFormat)
+				}
+				if merged.ResultFormat != "element_based" {
+					t.Errorf("ResultFormat = %q, want element_based", merged.ResultFormat)
+				}
+			},
+		},
+		{
+			name: "non-omitempty fields follow override section",
+			base: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithPdfOptions(kreuzberg.WithPdfFontConfig(kreuzberg.WithFontConfigEnabled(true))),
+			),
+			override: kreuzberg.NewExtractionConfig(
+				kreuzberg.WithPdfOptions(kreuzberg.WithPdfFontConfig(kreuzberg.WithFontConfigEnabled(false))),
+			),
+			check: func(t *testing.T, merged *kreuzberg.ExtractionConfig) {
+				if merged.PdfOptions.FontConfig.Enabled {
+					t.Error("FontConfig.Enabled should be overridden to false")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, kreuzberg.MergeConfigs(tt.base, tt.override))
+		})
+	}
+}
+
+func TestMergeConfigsDoesNotAliasInputs(t *testing.T) {
+	base := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithChunking(kreuzberg.WithChunkSize(512)),
+		kreuzberg.WithPostprocessor(kreuzberg.WithEnabledProcessors([]string{"quality"})),
+	)
+	override := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithChunking(kreuzberg.WithChunkOverlap(64)),
+	)
+
+	merged := kreuzberg.MergeConfigs(base, override)
+	*merged.Chunking.ChunkSize = 1024
+	merged.Postprocessor.EnabledProcessors[0] = "changed"
+
+	if *base.Chunking.ChunkSize != 512 {
+		t.Error("mutating merged config should not affect base")
+	}
+	if base.Postprocessor.EnabledProcessors[0] != "quality" {
+		t.Error("mutating merged slices should not affect base")
+	}
+	if base.Chunking.ChunkOverlap != nil {
+		t.Error("base should not receive override fields")
+	}
+}
+
+func TestDiffConfigs(t *testing.T) {
+	a := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithUseCache(true),
+		kreuzberg.WithOCR(
+			kreuzberg.WithOCRBackend("tesseract"),
+			kreuzberg.WithTesseract(kreuzberg.WithTesseractPSM(3)),
+		),
+		kreuzberg.WithOutputFormat("plain"),
+	)
+	b := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithUseCache(true),
+		kreuzberg.WithOCR(
+			kreuzberg.WithOCRBackend("tesseract"),
+			kreuzberg.WithTesseract(kreuzberg.WithTesseractPSM(6)),
+		),
+		kreuzberg.WithChunking(kreuzberg.WithChunkSize(512)),
+	)
+
+	changes := kreuzberg.DiffConfigs(a, b)
+	want := []kreuzberg.ConfigChange{
+		{Path: "ocr.tesseract_config.psm", Old: 3, New: 6},
+		{Path: "chunking.chunk_size", Old: nil, New: 512},
+		{Path: "output_format", Old: "plain", New: nil},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("DiffConfigs() = %+v, want %+v", changes, want)
+	}
+}
+
+func TestDiffConfigsIdentical(t *testing.T) {
+	cfg := kreuzberg.NewExtractionConfig(
+		kreuzberg.WithKeywords(kreuzberg.WithKeywordAlgorithm("yake")),
+	)
+	if changes := kreuzberg.DiffConfigs(cfg, kreuzberg.MergeConfigs(nil, cfg)); len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+	if changes := kreuzberg.DiffConfigs(nil, nil); len(changes) != 0 {
+		t.Errorf("expected no changes for nil configs, got %+v", changes)
+	}
+}
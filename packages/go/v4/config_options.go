@@ -36,6 +36,13 @@ func WithUseCache(enabled bool) ExtractionOption {
 	}
 }
 
+// WithConfigRouter selects a config per document with router.
+func WithConfigRouter(router *ConfigRouter) ExtractionOption {
+	return func(c *ExtractionConfig) {
+		c.Router = router
+	}
+}
+
 // WithEnableQualityProcessing sets whether quality processing is enabled.
 func WithEnableQualityProcessing(enabled bool) ExtractionOption {
 	return func(c *ExtractionConfig) {
@@ -170,6 +177,13 @@ func WithOCRLanguage(lang string) OCROption {
 	}
 }
 
+// WithOCROutputFormat sets the format of OCR results (e.g., "markdown").
+func WithOCROutputFormat(format OutputFormat) OCROption {
+	return func(c *OCRConfig) {
+		c.OutputFormat = string(format)
+	}
+}
+
 // WithTesseract sets the Tesseract configuration with functional options.
 func WithTesseract(opts ...TesseractOption) OCROption {
 	return func(c *OCRConfig) {
@@ -454,6 +468,27 @@ func WithChunkingEnabled(enabled bool) ChunkingOption {
 	}
 }
 
+// WithChunkingTrim sets whether whitespace is trimmed from chunk boundaries.
+func WithChunkingTrim(trim bool) ChunkingOption {
+	return func(c *ChunkingConfig) {
+		c.Trim = &trim
+	}
+}
+
+// WithChunkerType sets the chunker ("Text" or "Markdown").
+func WithChunkerType(chunkerType string) ChunkingOption {
+	return func(c *ChunkingConfig) {
+		c.ChunkerType = chunkerType
+	}
+}
+
+// WithChunkingEmbedding enables embedding generation for chunks.
+func WithChunkingEmbedding(opts ...EmbeddingOption) ChunkingOption {
+	return func(c *ChunkingConfig) {
+		c.Embedding = NewEmbeddingConfig(opts...)
+	}
+}
+
 // ============================================================================
 // ImageExtractionConfig Options
 // ============================================================================
@@ -577,6 +612,13 @@ func WithPdfFontConfig(opts ...FontConfigOption) PdfOption {
 	}
 }
 
+// WithPdfHierarchy sets the hierarchy extraction configuration with functional options.
+func WithPdfHierarchy(opts ...HierarchyOption) PdfOption {
+	return func(c *PdfConfig) {
+		c.Hierarchy = NewHierarchyConfig(opts...)
+	}
+}
+
 // ============================================================================
 // TokenReductionConfig Options
 // ============================================================================
@@ -1239,3 +1281,10 @@ func WithMarkerFormat(format string) PageOption {
 		c.MarkerFormat = &format
 	}
 }
+
+// WithPageRanges selects the pages to return, e.g. "1-3,10,-2".
+func WithPageRanges(ranges string) PageOption {
+	return func(c *PageConfig) {
+		c.Ranges = &ranges
+	}
+}
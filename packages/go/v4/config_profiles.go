@@ -0,0 +1,289 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Built-in config profile names.
+const (
+	// ProfileFastText extracts plain text only, skipping images and quality processing.
+	ProfileFastText = "fast_text"
+	// ProfileRAG produces Markdown split into chunks with embeddings, sized from a native embedding preset.
+	ProfileRAG = "rag"
+	// ProfileArchivalOCR forces OCR with image preprocessing and table detection for scanned archives.
+	ProfileArchivalOCR = "archival_ocr"
+	// ProfileMetadataOnly keeps extraction as cheap as possible for metadata harvesting.
+	ProfileMetadataOnly = "metadata_only"
+)
+
+// DefaultRAGEmbeddingPreset is the embedding preset used by ProfileRAG when available.
+const DefaultRAGEmbeddingPreset = "balanced"
+
+// ProfileFunc builds a fresh ExtractionConfig for a named profile.
+type ProfileFunc func() (*ExtractionConfig, error)
+
+var (
+	profileMu       sync.RWMutex
+	builtinProfiles = map[string]ProfileFunc{
+		ProfileFastText:     fastTextProfile,
+		ProfileRAG:          ragProfile,
+		ProfileArchivalOCR:  archivalOCRProfile,
+		ProfileMetadataOnly: metadataOnlyProfile,
+	}
+	customProfiles = map[string]ProfileFunc{}
+)
+
+// RegisterProfile registers a custom profile under name. Built-in profile names and
+// names that are already registered are rejected.
+func RegisterProfile(name string, fn ProfileFunc) error {
+	if name == "" {
+		return newValidationErrorWithContext("profile name cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+	if fn == nil {
+		return newValidationErrorWithContext("profile function cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	if _, ok := builtinProfiles[name]; ok {
+		return newValidationErrorWithContext(fmt.Sprintf("cannot override built-in profile: %s", name), nil, ErrorCodeValidation, nil)
+	}
+	if _, ok := customProfiles[name]; ok {
+		return newValidationErrorWithContext(fmt.Sprintf("profile already registered: %s", name), nil, ErrorCodeValidation, nil)
+	}
+	customProfiles[name] = fn
+	return nil
+}
+
+// UnregisterProfile removes a custom profile. Built-in profiles cannot be removed.
+func UnregisterProfile(name string) error {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	if _, ok := builtinProfiles[name]; ok {
+		return newValidationErrorWithContext(fmt.Sprintf("cannot remove built-in profile: %s", name), nil, ErrorCodeValidation, nil)
+	}
+	if _, ok := customProfiles[name]; !ok {
+		return newValidationErrorWithContext(fmt.Sprintf("unknown profile: %s", name), nil, ErrorCodeValidation, nil)
+	}
+	delete(customProfiles, name)
+	return nil
+}
+
+// ListProfiles returns the names of all built-in and custom profiles, sorted.
+func ListProfiles() []string {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	names := make([]string, 0, len(builtinProfiles)+len(customProfiles))
+	for name := range builtinProfiles {
+		names = append(names, name)
+	}
+	for name := range customProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigProfile returns a fresh ExtractionConfig for the named profile.
+func ConfigProfile(name string) (*ExtractionConfig, error) {
+	profileMu.RLock()
+	fn, ok := builtinProfiles[name]
+	if !ok {
+		fn, ok = customProfiles[name]
+	}
+	profileMu.RUnlock()
+
+	if !ok {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("unknown profile: %s", name), nil, ErrorCodeValidation, nil)
+	}
+	cfg, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("profile %s returned a nil config", name), nil, ErrorCodeValidation, nil)
+	}
+	return cfg, nil
+}
+
+// NewExtractionConfigFromProfile builds the named profile and then applies opts on top.
+// Like NewExtractionConfig, each With* section option replaces the whole section; use
+// ConfigProfileWithOverrides to change individual fields inside a profile's sections.
+func NewExtractionConfigFromProfile(name string, opts ...ExtractionOption) (*ExtractionConfig, error) {
+	cfg, err := ConfigProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg, nil
+}
+
+// ConfigProfileWithOverrides builds the named profile and deep-merges overrides on top
+// using MergeConfigs, so only the fields set in overrides change.
+func ConfigProfileWithOverrides(name string, overrides *ExtractionConfig) (*ExtractionConfig, error) {
+	cfg, err := ConfigProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return MergeConfigs(cfg, overrides), nil
+}
+
+func fastTextProfile() (*ExtractionConfig, error) {
+	return NewExtractionConfig(
+		WithOutputFormat(string(OutputFormatPlain)),
+		WithEnableQualityProcessing(false),
+		WithImages(WithExtractImages(false)),
+		WithPdfOptions(WithPdfExtractImages(false)),
+	), nil
+}
+
+// ragProfile sizes chunks from the native embedding preset so chunk lengths always fit
+// the preset's model. DefaultRAGEmbeddingPreset is preferred; otherwise the first
+// preset reported by ListEmbeddingPresets is used.
+func ragProfile() (*ExtractionConfig, error) {
+	names, err := ListEmbeddingPresets()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, newValidationErrorWithContext("no embedding presets available for rag profile", nil, ErrorCodeValidation, nil)
+	}
+	name := names[0]
+	for _, candidate := range names {
+		if candidate == DefaultRAGEmbeddingPreset {
+			name = candidate
+			break
+		}
+	}
+
+	preset, err := GetEmbeddingPreset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkingOpts := []ChunkingOption{
+		WithChunkingEnabled(true),
+		WithChunkingEmbedding(WithEmbeddingModel(
+			WithEmbeddingModelType("preset"),
+			WithEmbeddingModelName(preset.Name),
+		)),
+	}
+	if preset.ChunkSize > 0 {
+		chunkingOpts = append(chunkingOpts, WithMaxChars(preset.ChunkSize))
+	}
+	if preset.Overlap > 0 {
+		chunkingOpts = append(chunkingOpts, WithMaxOverlap(preset.Overlap))
+	}
+
+	return NewExtractionConfig(
+		WithOutputFormat(string(OutputFormatMarkdown)),
+		WithChunking(chunkingOpts...),
+	), nil
+}
+
+func archivalOCRProfile() (*ExtractionConfig, error) {
+	return NewExtractionConfig(
+		WithForceOCR(true),
+		WithOCR(
+			WithOCRBackend(string(OCRBackendTesseract)),
+			WithTesseract(
+				WithTesseractEnableTableDetection(true),
+				WithTesseractPreprocessing(
+					WithTargetDPI(300),
+					WithAutoRotate(true),
+					WithDeskew(true),
+					WithDenoise(true),
+				),
+			),
+		),
+		WithPdfOptions(WithPdfExtractMetadata(true)),
+		WithPages(WithExtractPages(true)),
+	), nil
+}
+
+func metadataOnlyProfile() (*ExtractionConfig, error) {
+	return NewExtractionConfig(
+		WithOutputFormat(string(OutputFormatPlain)),
+		WithEnableQualityProcessing(false),
+		WithForceOCR(false),
+		WithImages(WithExtractImages(false)),
+		WithPdfOptions(
+			WithPdfExtractImages(false),
+			WithPdfExtractMetadata(true),
+		),
+		WithPostprocessor(WithPostProcessorEnabled(false)),
+	), nil
+}
+
+// readConfigProfileName returns the top-level "profile" key of a TOML, YAML or JSON
+// config file, or "" when the file does not name a profile.
+func readConfigProfileName(path string) (string, error) {
+	data, err := configFileSettings(path)
+	if err != nil {
+		return "", err
+	}
+	var doc struct {
+		Profile string `json:"profile"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", newSerializationErrorWithContext("failed to decode config document", err, ErrorCodeValidation, nil)
+	}
+	return doc.Profile, nil
+}
+
+// applyConfigFileProfile layers the settings of a config file on top of the profile named
+// by its "profile" key. Returns loaded unchanged when the file names no profile.
+func applyConfigFileProfile(path string, loaded *ExtractionConfig) (*ExtractionConfig, error) {
+	name, err := readConfigProfileName(path)
+	if err != nil || name == "" {
+		return loaded, err
+	}
+
+	profile, err := ConfigProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := explicitConfigFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return MergeConfigs(profile, overrides), nil
+}
+
+// explicitConfigFromFile returns only the settings a config file sets itself, ignoring
+// any profile key. The file is decoded without defaults, so every key present counts,
+// including values equal to the native defaults.
+func explicitConfigFromFile(path string) (*ExtractionConfig, error) {
+	data, err := configFileSettings(path)
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := &ExtractionConfig{}
+	if err := json.Unmarshal(data, explicit); err != nil {
+		return nil, newSerializationErrorWithContext("failed to decode config document", err, ErrorCodeValidation, nil)
+	}
+	return explicit, nil
+}
+
+// configFileSettings returns the settings of a config file as JSON, exactly as the file
+// sets them. TOML and YAML files are decoded by the native loader.
+func configFileSettings(path string) ([]byte, error) {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return configFileDocument(path)
+	}
+	// #nosec G304 -- path is supplied by the caller loading its own config file
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newIOErrorWithContext(fmt.Sprintf("failed to read config file: %s", path), err, ErrorCodeIo, nil)
+	}
+	return data, nil
+}
@@ -0,0 +1,184 @@
+package kreuzberg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltinProfiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(t *testing.T, cfg *ExtractionConfig)
+	}{
+		{
+			name: ProfileFastText,
+			check: func(t *testing.T, cfg *ExtractionConfig) {
+				if cfg.OutputFormat != string(OutputFormatPlain) {
+					t.Errorf("OutputFormat = %q, want plain", cfg.OutputFormat)
+				}
+				if cfg.Images == nil || cfg.Images.ExtractImages == nil || *cfg.Images.ExtractImages {
+					t.Error("fast text profile should disable image extraction")
+				}
+			},
+		},
+		{
+			name: ProfileRAG,
+			check: func(t *testing.T, cfg *ExtractionConfig) {
+				if cfg.Chunking == nil || cfg.Chunking.Embedding == nil || cfg.Chunking.Embedding.Model == nil {
+					t.Fatal("rag profile should configure chunk embeddings")
+				}
+				preset, err := GetEmbeddingPreset(cfg.Chunking.Embedding.Model.Name)
+				if err != nil {
+					t.Fatalf("rag profile uses unknown preset %q: %v", cfg.Chunking.Embedding.Model.Name, err)
+				}
+				if cfg.Chunking.MaxChars == nil || *cfg.Chunking.MaxChars != preset.ChunkSize {
+					t.Errorf("MaxChars should match preset chunk size %d", preset.ChunkSize)
+				}
+			},
+		},
+		{
+			name: ProfileArchivalOCR,
+			check: func(t *testing.T, cfg *ExtractionConfig) {
+				if cfg.ForceOCR == nil || !*cfg.ForceOCR {
+					t.Error("archival profile should force OCR")
+				}
+				if cfg.OCR == nil || cfg.OCR.Tesseract == nil || cfg.OCR.Tesseract.Preprocessing == nil {
+					t.Error("archival profile should configure image preprocessing")
+				}
+			},
+		},
+		{
+			name: ProfileMetadataOnly,
+			check: func(t *testing.T, cfg *ExtractionConfig) {
+				if cfg.PdfOptions == nil || cfg.PdfOptions.ExtractMetadata == nil || !*cfg.PdfOptions.ExtractMetadata {
+					t.Error("metadata profile should extract PDF metadata")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ConfigProfile(tt.name)
+			if err != nil {
+				t.Fatalf("ConfigProfile(%q) error = %v", tt.name, err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+
+	if _, err := ConfigProfile("does-not-exist"); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestRegisterProfile(t *testing.T) {
+	const name = "test_invoices"
+	fn := func() (*ExtractionConfig, error) {
+		return NewExtractionConfig(WithUseCache(false), WithOCR(WithOCRBackend("tesseract"))), nil
+	}
+
+	if err := RegisterProfile(ProfileRAG, fn); err == nil {
+		t.Error("overriding a built-in profile should fail")
+	}
+	if err := RegisterProfile(name, nil); err == nil {
+		t.Error("nil profile function should fail")
+	}
+	if err := RegisterProfile(name, fn); err != nil {
+		t.Fatalf("RegisterProfile() error = %v", err)
+	}
+	t.Cleanup(func() { _ = UnregisterProfile(name) })
+
+	if err := RegisterProfile(name, fn); err == nil {
+		t.Error("duplicate registration should fail")
+	}
+
+	found := false
+	for _, profile := range ListProfiles() {
+		found = found || profile == name
+	}
+	if !found {
+		t.Errorf("ListProfiles() should include %q", name)
+	}
+
+	cfg, err := ConfigProfileWithOverrides(name, &ExtractionConfig{OCR: &OCRConfig{Language: stringPtr("deu")}})
+	if err != nil {
+		t.Fatalf("ConfigProfileWithOverrides() error = %v", err)
+	}
+	if cfg.OCR.Backend != "tesseract" || cfg.OCR.Language == nil || *cfg.OCR.Language != "deu" {
+		t.Errorf("overrides should merge into the profile's OCR section, got %+v", cfg.OCR)
+	}
+
+	cfg, err = NewExtractionConfigFromProfile(name, WithUseCache(true))
+	if err != nil {
+		t.Fatalf("NewExtractionConfigFromProfile() error = %v", err)
+	}
+	if cfg.UseCache == nil || !*cfg.UseCache {
+		t.Error("options should be applied after the profile")
+	}
+
+	if err := UnregisterProfile(ProfileFastText); err == nil {
+		t.Error("removing a built-in profile should fail")
+	}
+}
+
+func TestReadConfigProfileName(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    string
+	}{
+		{"kreuzberg.toml", "profile = \"rag\"\nuse_cache = true\n", "rag"},
+		{"kreuzberg.toml", "use_cache = true\n\n[ocr]\nprofile = \"rag\"\n", ""},
+		{"kreuzberg.yaml", "# settings\nprofile: archival_ocr # scans\nocr:\n  backend: tesseract\n", "archival_ocr"},
+		{"kreuzberg.yml", "ocr:\n  profile: rag\n", ""},
+		{"kreuzberg.toml", "use_cache = true\nprofile = 'rag' # after other keys\n", "rag"},
+		{"kreuzberg.yaml", "\"profile\": 'fast_text'\n", "fast_text"},
+		{"kreuzberg.yaml", "ocr: {profile: rag}\n", ""},
+		{"kreuzberg.json", `{"profile": "metadata_only", "use_cache": false}`, "metadata_only"},
+		{"kreuzberg.json", `{"use_cache": false}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.want, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+			got, err := readConfigProfileName(path)
+			if err != nil {
+				t.Fatalf("readConfigProfileName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readConfigProfileName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileOverridesProfileWithDefaultValues(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"kreuzberg.toml": "profile = \"archival_ocr\"\nforce_ocr = false\n",
+		"kreuzberg.yaml": "profile: archival_ocr\nforce_ocr: false\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := LoadExtractionConfigFromFile(path)
+			if err != nil {
+				t.Fatalf("LoadExtractionConfigFromFile() error = %v", err)
+			}
+			if cfg.ForceOCR == nil || *cfg.ForceOCR {
+				t.Errorf("ForceOCR = %v, want the file's explicit false to override the profile", cfg.ForceOCR)
+			}
+			if cfg.OCR == nil {
+				t.Error("settings the file does not set should come from the profile")
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package kreuzberg
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigRoute maps documents to an ExtractionConfig by MIME type and, optionally, file name.
+type ConfigRoute struct {
+	// MimePattern matches the detected MIME type. Accepts exact types ("application/pdf"),
+	// type wildcards ("image/*") or "*" for any type. Empty matches any type.
+	MimePattern string
+	// FileGlob optionally matches the base file name using path.Match syntax (e.g., "*.scan.pdf").
+	// Empty matches any name. Routes with a glob never match in-memory documents.
+	FileGlob string
+	// Config is layered on top of the router's base config via MergeConfigs.
+	Config *ExtractionConfig
+}
+
+// ConfigRouter selects a per-document ExtractionConfig after MIME detection, so one batch can
+// run OCR only for scanned images, hierarchy detection only for PDFs, and so on.
+//
+// Routes are evaluated in the order they were added; the first match wins. Documents that
+// match no route use the base config unchanged.
+type ConfigRouter struct {
+	base   *ExtractionConfig
+	routes []ConfigRoute
+}
+
+// NewConfigRouter creates a router whose unmatched documents use base (nil means defaults).
+func NewConfigRouter(base *ExtractionConfig) *ConfigRouter {
+	return &ConfigRouter{base: base}
+}
+
+// AddRoute appends a route. Returns a validation error if the route has no patterns,
+// no config, or an invalid file glob.
+func (r *ConfigRouter) AddRoute(route ConfigRoute) error {
+	if route.MimePattern == "" && route.FileGlob == "" {
+		return newValidationErrorWithContext("route requires a MIME pattern or file glob", nil, ErrorCodeValidation, nil)
+	}
+	if route.Config == nil {
+		return newValidationErrorWithContext("route config cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	if route.MimePattern != "" && route.MimePattern != "*" && !strings.Contains(route.MimePattern, "/") {
+		return newValidationErrorWithContext(fmt.Sprintf("invalid MIME pattern: %s", route.MimePattern), nil, ErrorCodeValidation, nil)
+	}
+	if route.FileGlob != "" {
+		if _, err := path.Match(route.FileGlob, ""); err != nil {
+			return newValidationErrorWithContext(fmt.Sprintf("invalid file glob: %s", route.FileGlob), err, ErrorCodeValidation, nil)
+		}
+	}
+	r.routes = append(r.routes, route)
+	return nil
+}
+
+// Route is a convenience wrapper around AddRoute for MIME-only routes.
+func (r *ConfigRouter) Route(mimePattern string, config *ExtractionConfig) error {
+	return r.AddRoute(ConfigRoute{MimePattern: mimePattern, Config: config})
+}
+
+// Resolve returns the config for a document with the given MIME type and file name.
+// fileName may be empty for in-memory documents. The returned config is a fresh copy.
+func (r *ConfigRouter) Resolve(mimeType, fileName string) *ExtractionConfig {
+	return r.configFor(r.match(mimeType, fileName))
+}
+
+// match returns the index of the first matching route, or -1.
+func (r *ConfigRouter) match(mimeType, fileName string) int {
+	mimeType = normalizeMimeType(mimeType)
+	name := ""
+	if fileName != "" {
+		name = filepath.Base(fileName)
+	}
+	for i, route := range r.routes {
+		if route.MimePattern != "" && !mimePatternMatches(route.MimePattern, mimeType) {
+			continue
+		}
+		if route.FileGlob != "" {
+			if name == "" {
+				continue
+			}
+			if ok, _ := path.Match(route.FileGlob, name); !ok {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// normalizeMimeType lowercases a MIME type and strips parameters such as charset.
+func normalizeMimeType(mimeType string) string {
+	if idx := strings.Index(mimeType, ";"); idx >= 0 {
+		mimeType = mimeType[:idx]
+	}
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+func mimePatternMatches(pattern, mimeType string) bool {
+	pattern = normalizeMimeType(pattern)
+	if pattern == "*" || pattern == "*/*" {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		major, _, _ := strings.Cut(mimeType, "/")
+		return major == prefix
+	}
+	return pattern == mimeType
+}
+
+// ExtractFileRouted detects the file's MIME type and extracts it with the config chosen by router.
+func ExtractFileRouted(path string, router *ConfigRouter) (*ExtractionResult, error) {
+	if router == nil {
+		return nil, newValidationErrorWithContext("router cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	return ExtractFileSync(path, &ExtractionConfig{Router: router})
+}
+
+// ExtractBytesRouted extracts in-memory data with the config chosen by router.
+// If mimeType is empty it is detected from the content first.
+func ExtractBytesRouted(data []byte, mimeType string, router *ConfigRouter) (*ExtractionResult, error) {
+	if router == nil {
+		return nil, newValidationErrorWithContext("router cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	if mimeType == "" {
+		detected, err := DetectMimeType(data)
+		if err != nil {
+			return nil, err
+		}
+		mimeType = detected
+	}
+	return ExtractBytesSync(data, mimeType, &ExtractionConfig{Router: router})
+}
+
+// BatchExtractFilesRouted extracts a mixed batch of files, grouping them by routed config so
+// each group still runs through the batch pipeline. Results are returned in input order.
+// A file whose MIME type cannot be detected gets an error result, like any other file
+// that fails in a batch.
+func BatchExtractFilesRouted(paths []string, router *ConfigRouter) ([]*ExtractionResult, error) {
+	if router == nil {
+		return nil, newValidationErrorWithContext("router cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	return BatchExtractFilesSync(paths, &ExtractionConfig{Router: router})
+}
+
+// BatchExtractBytesRouted extracts a mixed batch of in-memory documents, grouping them by
+// routed config. Items with an empty MimeType are detected from their content first.
+// Results are returned in input order. An item whose MIME type cannot be detected gets
+// an error result, like any other item that fails in a batch.
+func BatchExtractBytesRouted(items []BytesWithMime, router *ConfigRouter) ([]*ExtractionResult, error) {
+	if router == nil {
+		return nil, newValidationErrorWithContext("router cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	return BatchExtractBytesSync(items, &ExtractionConfig{Router: router})
+}
+
+// routedGroup is a set of batch items that share a route.
+type routedGroup struct {
+	route   int
+	members []int
+}
+
+// groupByRoute groups batch items by the route matching their MIME type and file name.
+// Items whose MIME type cannot be detected get an error result in results instead.
+func groupByRoute(router *ConfigRouter, count int, detect func(i int) (string, string, error), results []*ExtractionResult) []*routedGroup {
+	var groups []*routedGroup
+	byRoute := map[int]*routedGroup{}
+	for i := 0; i < count; i++ {
+		mimeType, fileName, err := detect(i)
+		if err != nil {
+			results[i] = batchErrorResult(err)
+			continue
+		}
+		idx := router.match(mimeType, fileName)
+		group, ok := byRoute[idx]
+		if !ok {
+			group = &routedGroup{route: idx}
+			byRoute[idx] = group
+			groups = append(groups, group)
+		}
+		group.members = append(group.members, i)
+	}
+	return groups
+}
+
+// batchExtractFilesRouted implements BatchExtractFilesSync for configs with a Router.
+func batchExtractFilesRouted(paths []string, config *ExtractionConfig) ([]*ExtractionResult, error) {
+	for i, p := range paths {
+		if p == "" {
+			return nil, newValidationErrorWithContext(fmt.Sprintf("path at index %d is empty", i), nil, ErrorCodeValidation, nil)
+		}
+	}
+
+	results := make([]*ExtractionResult, len(paths))
+	groups := groupByRoute(config.Router, len(paths), func(i int) (string, string, error) {
+		mimeType, err := DetectMimeTypeFromPath(paths[i])
+		return mimeType, paths[i], err
+	}, results)
+	for _, group := range groups {
+		groupPaths := make([]string, len(group.members))
+		for j, i := range group.members {
+			groupPaths[j] = paths[i]
+		}
+		groupResults, err := BatchExtractFilesSync(groupPaths, config.routed(group.route))
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range group.members {
+			if j < len(groupResults) {
+				results[i] = groupResults[j]
+			}
+		}
+	}
+	return results, nil
+}
+
+// batchExtractBytesRouted implements BatchExtractBytesSync for configs with a Router.
+func batchExtractBytesRouted(items []BytesWithMime, config *ExtractionConfig) ([]*ExtractionResult, error) {
+	for i, item := range items {
+		if len(item.Data) == 0 {
+			return nil, newValidationErrorWithContext(fmt.Sprintf("data at index %d is empty", i), nil, ErrorCodeValidation, nil)
+		}
+	}
+
+	results := make([]*ExtractionResult, len(items))
+	resolved := make([]BytesWithMime, len(items))
+	groups := groupByRoute(config.Router, len(items), func(i int) (string, string, error) {
+		resolved[i] = items[i]
+		if resolved[i].MimeType == "" {
+			detected, err := DetectMimeType(items[i].Data)
+			if err != nil {
+				return "", "", err
+			}
+			resolved[i].MimeType = detected
+		}
+		return resolved[i].MimeType, "", nil
+	}, results)
+	for _, group := range groups {
+		groupItems := make([]BytesWithMime, len(group.members))
+		for j, i := range group.members {
+			groupItems[j] = resolved[i]
+		}
+		groupResults, err := BatchExtractBytesSync(groupItems, config.routed(group.route))
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range group.members {
+			if j < len(groupResults) {
+				results[i] = groupResults[j]
+			}
+		}
+	}
+	return results, nil
+}
+
+// routed returns the config for documents matching the route index returned by match:
+// the routed config layered on top of the other fields of c, without the Router.
+func (c *ExtractionConfig) routed(idx int) *ExtractionConfig {
+	routed := MergeConfigs(c, c.Router.configFor(idx))
+	routed.Router = nil
+	return routed
+}
+
+// batchErrorResult reports a failed batch item the way the native batch functions do.
+func batchErrorResult(err error) *ExtractionResult {
+	errorType := string(ErrorKindUnknown)
+	var kerr KreuzbergError
+	if errors.As(err, &kerr) {
+		errorType = string(kerr.Kind())
+	}
+	return &ExtractionResult{
+		Content:  "Error: " + err.Error(),
+		MimeType: "text/plain",
+		Metadata: Metadata{Error: &ErrorMetadata{ErrorType: errorType, Message: err.Error()}},
+		Tables:   []Table{},
+	}
+}
+
+// configFor returns the merged config for a route index returned by match.
+func (r *ConfigRouter) configFor(idx int) *ExtractionConfig {
+	if idx < 0 {
+		return MergeConfigs(r.base, nil)
+	}
+	return MergeConfigs(r.base, r.routes[idx].Config)
+}
@@ -0,0 +1,230 @@
+package kreuzberg_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func newTestRouter(t *testing.T) *kreuzberg.ConfigRouter {
+	t.Helper()
+	router := kreuzberg.NewConfigRouter(kreuzberg.NewExtractionConfig(
+		kreuzberg.WithUseCache(true),
+	))
+	routes := []kreuzberg.ConfigRoute{
+		{
+			MimePattern: "application/pdf",
+			FileGlob:    "*.scan.pdf",
+			Config:      kreuzberg.NewExtractionConfig(kreuzberg.WithForceOCR(true)),
+		},
+		{
+			MimePattern: "image/*",
+			Config:      kreuzberg.NewExtractionConfig(kreuzberg.WithOCR(kreuzberg.WithOCRBackend("tesseract"))),
+		},
+		{
+			MimePattern: "application/pdf",
+			Config:      kreuzberg.NewExtractionConfig(kreuzberg.WithPdfOptions(kreuzberg.WithPdfExtractMetadata(true))),
+		},
+		{
+			MimePattern: "text/html",
+			Config: kreuzberg.NewExtractionConfig(kreuzberg.WithHTMLOptions(
+				kreuzberg.WithHTMLPreprocessing(kreuzberg.WithHTMLPreprocessingEnabled(true)),
+			)),
+		},
+	}
+	for _, route := range routes {
+		if err := router.AddRoute(route); err != nil {
+			t.Fatalf("AddRoute() error = %v", err)
+		}
+	}
+	return router
+}
+
+func TestConfigRouterResolve(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name     string
+		mimeType string
+		fileName string
+		check    func(t *testing.T, cfg *kreuzberg.ExtractionConfig)
+	}{
+		{
+			name:     "image wildcard enables OCR",
+			mimeType: "image/png",
+			check: func(t *testing.T, cfg *kreuzberg.ExtractionConfig) {
+				if cfg.OCR == nil || cfg.OCR.Backend != "tesseract" {
+					t.Error("image route should configure OCR")
+				}
+			},
+		},
+		{
+			name:     "file glob takes precedence when listed first",
+			mimeType: "application/pdf",
+			fileName: "/data/invoice.scan.pdf",
+			check: func(t *testing.T, cfg *kreuzberg.ExtractionConfig) {
+				if cfg.ForceOCR == nil || !*cfg.ForceOCR {
+					t.Error("scan glob route should force OCR")
+				}
+				if cfg.PdfOptions != nil {
+					t.Error("only the first matching route should apply")
+				}
+			},
+		},
+		{
+			name:     "plain pdf uses pdf route",
+			mimeType: "application/pdf",
+			fileName: "report.pdf",
+			check: func(t *testing.T, cfg *kreuzberg.ExtractionConfig) {
+				if cfg.PdfOptions == nil || cfg.PdfOptions.ExtractMetadata == nil {
+					t.Error("pdf route should set PDF options")
+				}
+				if cfg.ForceOCR != nil {
+					t.Error("scan route should not apply to report.pdf")
+				}
+			},
+		},
+		{
+			name:     "MIME parameters and case are ignored",
+			mimeType: "Text/HTML; charset=utf-8",
+			check: func(t *testing.T, cfg *kreuzberg.ExtractionConfig) {
+				if cfg.HTMLOptions == nil || cfg.HTMLOptions.Preprocessing == nil {
+					t.Error("html route should enable preprocessing")
+				}
+			},
+		},
+		{
+			name:     "unmatched type uses base config",
+			mimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+			check: func(t *testing.T, cfg *kreuzberg.ExtractionConfig) {
+				if cfg.OCR != nil || cfg.PdfOptions != nil || cfg.HTMLOptions != nil {
+					t.Error("unmatched document should not receive route settings")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := router.Resolve(tt.mimeType, tt.fileName)
+			if cfg == nil {
+				t.Fatal("Resolve() returned nil")
+			}
+			if cfg.UseCache == nil || !*cfg.UseCache {
+				t.Error("base config should always be applied")
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestConfigRouterAddRouteValidation(t *testing.T) {
+	router := kreuzberg.NewConfigRouter(nil)
+	cfg := kreuzberg.NewExtractionConfig()
+
+	tests := []struct {
+		name  string
+		route kreuzberg.ConfigRoute
+	}{
+		{name: "no patterns", route: kreuzberg.ConfigRoute{Config: cfg}},
+		{name: "nil config", route: kreuzberg.ConfigRoute{MimePattern: "image/*"}},
+		{name: "malformed MIME pattern", route: kreuzberg.ConfigRoute{MimePattern: "pdf", Config: cfg}},
+		{name: "malformed glob", route: kreuzberg.ConfigRoute{FileGlob: "[", Config: cfg}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := router.AddRoute(tt.route); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	if err := router.Route("*", cfg); err != nil {
+		t.Errorf("catch-all route should be accepted: %v", err)
+	}
+}
+
+func TestRoutedExtractionRequiresRouter(t *testing.T) {
+	if _, err := kreuzberg.ExtractFileRouted("doc.pdf", nil); err == nil {
+		t.Error("ExtractFileRouted should reject a nil router")
+	}
+	if _, err := kreuzberg.ExtractBytesRouted([]byte("x"), "text/plain", nil); err == nil {
+		t.Error("ExtractBytesRouted should reject a nil router")
+	}
+	if _, err := kreuzberg.BatchExtractFilesRouted([]string{"doc.pdf"}, nil); err == nil {
+		t.Error("BatchExtractFilesRouted should reject a nil router")
+	}
+	results, err := kreuzberg.BatchExtractBytesRouted(nil, kreuzberg.NewConfigRouter(nil))
+	if err != nil || len(results) != 0 {
+		t.Errorf("empty batch should return no results, got %v, %v", results, err)
+	}
+}
+
+func TestExtractionConfigRouterRoutesDocuments(t *testing.T) {
+	router := kreuzberg.NewConfigRouter(nil)
+	if err := router.Route("text/plain", kreuzberg.NewExtractionConfig(
+		kreuzberg.WithChunking(kreuzberg.WithMaxChars(64), kreuzberg.WithMaxOverlap(0)),
+	)); err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+	text := []byte(strings.Repeat("Routed documents are chunked. ", 20))
+
+	result, err := kreuzberg.ExtractBytesSync(text, "text/plain", kreuzberg.NewExtractionConfig(
+		kreuzberg.WithUseCache(false),
+		kreuzberg.WithConfigRouter(router),
+	))
+	if err != nil {
+		t.Fatalf("ExtractBytesSync() error = %v", err)
+	}
+	if len(result.Chunks) < 2 {
+		t.Errorf("chunks = %d, want the text/plain route to enable chunking", len(result.Chunks))
+	}
+
+	results, err := kreuzberg.BatchExtractBytesRouted([]kreuzberg.BytesWithMime{
+		{Data: text, MimeType: "text/plain"},
+		{Data: []byte("<html><body><p>Not routed</p></body></html>"), MimeType: "text/html"},
+	}, router)
+	if err != nil {
+		t.Fatalf("BatchExtractBytesRouted() error = %v", err)
+	}
+	if len(results) != 2 || results[0] == nil || results[1] == nil {
+		t.Fatalf("results = %v, want one per item", results)
+	}
+	if len(results[0].Chunks) < 2 || len(results[1].Chunks) != 0 {
+		t.Errorf("chunks = %d and %d, want only the text/plain item chunked", len(results[0].Chunks), len(results[1].Chunks))
+	}
+}
+
+func TestBatchExtractFilesRoutedReportsDetectionFailures(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(good, []byte("routed batch item"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	results, err := kreuzberg.BatchExtractFilesRouted([]string{good, missing}, newTestRouter(t))
+	if err != nil {
+		t.Fatalf("BatchExtractFilesRouted() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %d, want 2", len(results))
+	}
+	if results[0] == nil || results[0].Metadata.Error != nil || !strings.Contains(results[0].Content, "routed batch item") {
+		t.Errorf("results[0] = %+v, want the extracted text", results[0])
+	}
+	if results[1] == nil || results[1].Metadata.Error == nil {
+		t.Errorf("results[1] = %+v, want an error result", results[1])
+	}
+}
+
+func TestMergeConfigsSharesRouter(t *testing.T) {
+	router := newTestRouter(t)
+	merged := kreuzberg.MergeConfigs(kreuzberg.NewExtractionConfig(kreuzberg.WithConfigRouter(router)), nil)
+	if merged.Router != router {
+		t.Error("MergeConfigs should keep the router of base")
+	}
+}
@@ -0,0 +1,258 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ConfigSchemaDraft is the JSON Schema dialect emitted by ConfigJSONSchema.
+const ConfigSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// ConfigSchemaID is the $id used for the generated ExtractionConfig schema.
+const ConfigSchemaID = "https://kreuzberg.dev/schemas/extraction-config.json"
+
+// schemaConstraint holds the extra keywords attached to a single config field.
+type schemaConstraint struct {
+	Enum    []string
+	Minimum *float64
+	Maximum *float64
+}
+
+// staticSchemaConstraints mirrors the native validators (see validation.go) for fields
+// whose ranges or allowed values are fixed. Keys are "<Go type>.<json field>".
+var staticSchemaConstraints = map[string]schemaConstraint{
+	"ExtractionConfig.output_format": {Enum: []string{
+		string(OutputFormatPlain), string(OutputFormatText), string(OutputFormatMarkdown),
+		string(OutputFormatMd), string(OutputFormatDjot), string(OutputFormatHTML),
+	}},
+	"ExtractionConfig.result_format": {Enum: []string{
+		string(ResultFormatUnified), string(ResultFormatElementBased),
+	}},
+	"ExtractionConfig.max_concurrent_extractions": {Minimum: schemaBound(1)},
+	"OCRConfig.output_format": {Enum: []string{
+		string(OutputFormatPlain), string(OutputFormatText), string(OutputFormatMarkdown),
+		string(OutputFormatMd), string(OutputFormatDjot), string(OutputFormatHTML),
+	}},
+	"TesseractConfig.psm":                       {Minimum: schemaBound(0), Maximum: schemaBound(13)},
+	"TesseractConfig.oem":                       {Minimum: schemaBound(0), Maximum: schemaBound(3)},
+	"TesseractConfig.min_confidence":            {Minimum: schemaBound(0), Maximum: schemaBound(100)},
+	"TesseractConfig.table_min_confidence":      {Minimum: schemaBound(0), Maximum: schemaBound(100)},
+	"TesseractConfig.table_row_threshold_ratio": {Minimum: schemaBound(0), Maximum: schemaBound(1)},
+	"ImagePreprocessingConfig.target_dpi":       {Minimum: schemaBound(1), Maximum: schemaBound(2400)},
+	"ImageExtractionConfig.target_dpi":          {Minimum: schemaBound(1), Maximum: schemaBound(2400)},
+	"ImageExtractionConfig.min_dpi":             {Minimum: schemaBound(1), Maximum: schemaBound(2400)},
+	"ImageExtractionConfig.max_dpi":             {Minimum: schemaBound(1), Maximum: schemaBound(2400)},
+	"ImageExtractionConfig.max_image_dimension": {Minimum: schemaBound(1)},
+	"ChunkingConfig.max_chars":                  {Minimum: schemaBound(1), Maximum: schemaBound(104857600)},
+	"ChunkingConfig.max_overlap":                {Minimum: schemaBound(0)},
+	"ChunkingConfig.chunk_size":                 {Minimum: schemaBound(0), Maximum: schemaBound(104857600)},
+	"ChunkingConfig.chunk_overlap":              {Minimum: schemaBound(0)},
+	"ChunkingConfig.chunker_type":               {Enum: []string{"Text", "Markdown"}},
+	"LanguageDetectionConfig.min_confidence":    {Minimum: schemaBound(0), Maximum: schemaBound(1)},
+	"KeywordConfig.algorithm":                   {Enum: []string{string(KeywordAlgorithmYake), string(KeywordAlgorithmRake)}},
+	"KeywordConfig.max_keywords":                {Minimum: schemaBound(1)},
+	"HTMLConversionOptions.heading_style":       {Enum: []string{string(HeadingStyleATX), string(HeadingStyleUnderlined), string(HeadingStyleATXClosed)}},
+	"HTMLConversionOptions.list_indent_type":    {Enum: []string{string(ListIndentTypeSpaces), string(ListIndentTypeTabs)}},
+	"HTMLConversionOptions.highlight_style": {Enum: []string{
+		string(HighlightStyleDoubleEqual), string(HighlightStyleHTML), string(HighlightStyleBold), string(HighlightStyleNone),
+	}},
+	"HTMLConversionOptions.whitespace_mode": {Enum: []string{
+		string(WhitespaceModeDefault), string(WhitespaceModePreserve), string(WhitespaceModePreserveInner), string(WhitespaceModeCollapse),
+	}},
+	"HTMLConversionOptions.newline_style": {Enum: []string{
+		string(NewlineStyleDefault), string(NewlineStyleSpaces), string(NewlineStyleBackslash),
+	}},
+	"HTMLConversionOptions.code_block_style": {Enum: []string{
+		string(CodeBlockStyleIndented), string(CodeBlockStyleBackticks), string(CodeBlockStyleTildes),
+	}},
+	"HTMLPreprocessingOptions.preset": {Enum: []string{
+		string(PreprocessingPresetNone), string(PreprocessingPresetConservative), string(PreprocessingPresetAggressive),
+	}},
+}
+
+// schemaFieldAliases lists the alternative keys the native loader accepts for a field,
+// keyed like staticSchemaConstraints and mapped to the canonical JSON field.
+var schemaFieldAliases = map[string]string{
+	"ChunkingConfig.max_characters": "max_chars",
+	"ChunkingConfig.overlap":        "max_overlap",
+}
+
+// openSchemaStructs are config structs mirroring types of other crates whose fields
+// the binding does not track; their schemas allow keys the Go struct does not declare.
+var openSchemaStructs = map[string]bool{
+	"HTMLConversionOptions":    true,
+	"HTMLPreprocessingOptions": true,
+}
+
+func schemaBound(v float64) *float64 {
+	return &v
+}
+
+// ConfigJSONSchema returns a JSON Schema (draft 2020-12) describing ExtractionConfig and
+// every nested configuration struct. The schema can be used to validate kreuzberg.json,
+// kreuzberg.yaml and kreuzberg.toml files before they reach the binding.
+//
+// Allowed OCR backends, binarization methods and token reduction levels are queried
+// from the native library so the schema always matches the linked Kreuzberg version.
+func ConfigJSONSchema() ([]byte, error) {
+	constraints, err := nativeSchemaConstraints()
+	if err != nil {
+		return nil, err
+	}
+
+	gen := &schemaGenerator{
+		constraints: constraints,
+		defs:        map[string]interface{}{},
+	}
+	root := gen.structSchema(reflect.TypeOf(ExtractionConfig{}))
+	// Config files may name a profile to layer their settings on; see ListProfiles.
+	root["properties"].(map[string]interface{})["profile"] = map[string]interface{}{"type": "string"}
+	// JSON config files have no comment syntax, so the native loader ignores "$comment".
+	root["properties"].(map[string]interface{})["$comment"] = map[string]interface{}{"type": "string"}
+	root["$schema"] = ConfigSchemaDraft
+	root["$id"] = ConfigSchemaID
+	root["title"] = "Kreuzberg ExtractionConfig"
+	root["$defs"] = gen.defs
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode config schema", err, ErrorCodeValidation, nil)
+	}
+	return data, nil
+}
+
+// WriteConfigJSONSchema writes the ExtractionConfig JSON Schema to path.
+func WriteConfigJSONSchema(path string) error {
+	if path == "" {
+		return newValidationErrorWithContext("schema path cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+
+	data, err := ConfigJSONSchema()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return newIOErrorWithContext("failed to write config schema", err, ErrorCodeIo, nil)
+	}
+	return nil
+}
+
+func nativeSchemaConstraints() (map[string]schemaConstraint, error) {
+	constraints := make(map[string]schemaConstraint, len(staticSchemaConstraints)+3)
+	for key, value := range staticSchemaConstraints {
+		constraints[key] = value
+	}
+
+	backends, err := GetValidOCRBackends()
+	if err != nil {
+		return nil, err
+	}
+	constraints["OCRConfig.backend"] = schemaConstraint{Enum: backends}
+
+	methods, err := GetValidBinarizationMethods()
+	if err != nil {
+		return nil, err
+	}
+	constraints["ImagePreprocessingConfig.binarization_method"] = schemaConstraint{Enum: methods}
+
+	levels, err := GetValidTokenReductionLevels()
+	if err != nil {
+		return nil, err
+	}
+	constraints["TokenReductionConfig.mode"] = schemaConstraint{Enum: levels}
+
+	return constraints, nil
+}
+
+type schemaGenerator struct {
+	constraints map[string]schemaConstraint
+	defs        map[string]interface{}
+}
+
+// structSchema builds an object schema for a config struct using its JSON tags.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _ := configFieldName(field)
+		if name == "" {
+			continue
+		}
+		prop := g.typeSchema(field.Type)
+		if c, ok := g.constraints[t.Name()+"."+name]; ok {
+			applySchemaConstraint(prop, c)
+		}
+		properties[name] = prop
+	}
+	for key, canonical := range schemaFieldAliases {
+		structName, alias, _ := strings.Cut(key, ".")
+		if prop, ok := properties[canonical]; ok && structName == t.Name() {
+			properties[alias] = prop
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if !openSchemaStructs[t.Name()] {
+		schema["additionalProperties"] = false
+	}
+	return schema
+}
+
+// typeSchema maps a Go type to its JSON Schema, registering nested structs in $defs.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so self-referencing structs terminate.
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Array:
+		items := make([]interface{}, t.Len())
+		for i := range items {
+			items[i] = g.typeSchema(t.Elem())
+		}
+		return map[string]interface{}{
+			"type":        "array",
+			"prefixItems": items,
+			"minItems":    t.Len(),
+			"maxItems":    t.Len(),
+		}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func applySchemaConstraint(prop map[string]interface{}, c schemaConstraint) {
+	if len(c.Enum) > 0 {
+		prop["enum"] = c.Enum
+	}
+	if c.Minimum != nil {
+		prop["minimum"] = *c.Minimum
+	}
+	if c.Maximum != nil {
+		prop["maximum"] = *c.Maximum
+	}
+}
@@ -0,0 +1,140 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// validateSchema checks value against the subset of JSON Schema emitted by
+// ConfigJSONSchema and returns the paths that do not conform.
+func validateSchema(root, schema map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")]
+		return validateSchema(root, def.(map[string]interface{}), value, path)
+	}
+	var errs []string
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			found = found || allowed == value
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+	if n, ok := value.(float64); ok {
+		if lo, ok := schema["minimum"].(float64); ok && n < lo {
+			errs = append(errs, fmt.Sprintf("%s: %v is below %v", path, n, lo))
+		}
+		if hi, ok := schema["maximum"].(float64); ok && n > hi {
+			errs = append(errs, fmt.Sprintf("%s: %v is above %v", path, n, hi))
+		}
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: %v is not an object", path, value))
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if prop, ok := props[key].(map[string]interface{}); ok {
+				errs = append(errs, validateSchema(root, prop, object[key], path+"."+key)...)
+			} else if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, validateSchema(root, extra, object[key], path+"."+key)...)
+			} else if schema["additionalProperties"] == false {
+				errs = append(errs, fmt.Sprintf("%s: unknown key %q", path, key))
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fmt.Sprintf("%s: %v is not an array", path, value))
+		}
+		for i, item := range array {
+			itemSchema, _ := schema["items"].(map[string]interface{})
+			if prefix, ok := schema["prefixItems"].([]interface{}); ok && i < len(prefix) {
+				itemSchema, _ = prefix[i].(map[string]interface{})
+			}
+			if itemSchema != nil {
+				errs = append(errs, validateSchema(root, itemSchema, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: %v is not a string", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: %v is not a boolean", path, value))
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok || (schema["type"] == "integer" && n != float64(int64(n))) {
+			errs = append(errs, fmt.Sprintf("%s: %v is not an %s", path, value, schema["type"]))
+		}
+	}
+	return errs
+}
+
+func TestConfigJSONSchemaAcceptsExampleConfigs(t *testing.T) {
+	examples, _ := filepath.Glob(filepath.Join("..", "..", "..", "examples", "kreuzberg.*"))
+	if len(examples) == 0 {
+		t.Skip("example config files not found")
+	}
+	data, err := ConfigJSONSchema()
+	if err != nil {
+		t.Fatalf("ConfigJSONSchema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	for _, path := range examples {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			document, err := configFileDocument(path)
+			if err != nil {
+				t.Fatalf("configFileDocument() error = %v", err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(document, &value); err != nil {
+				t.Fatalf("config document is not valid JSON: %v", err)
+			}
+			for _, msg := range validateSchema(schema, schema, value, "$") {
+				t.Error(msg)
+			}
+		})
+	}
+}
+
+func TestConfigJSONSchemaAcceptsNativeAliases(t *testing.T) {
+	gen := &schemaGenerator{constraints: staticSchemaConstraints, defs: map[string]interface{}{}}
+	root := gen.structSchema(reflect.TypeOf(ExtractionConfig{}))
+	root["$defs"] = gen.defs
+
+	valid := map[string]interface{}{
+		"chunking":     map[string]interface{}{"max_characters": float64(500), "overlap": float64(50), "trim": false, "chunker_type": "Markdown"},
+		"pdf_options":  map[string]interface{}{"hierarchy": map[string]interface{}{"enabled": true, "k_clusters": float64(4)}},
+		"ocr":          map[string]interface{}{"output_format": "markdown"},
+		"html_options": map[string]interface{}{"option_from_a_newer_release": true},
+	}
+	if errs := validateSchema(root, root, valid, "$"); len(errs) > 0 {
+		t.Errorf("native config rejected: %v", errs)
+	}
+
+	invalid := map[string]interface{}{"chunking": map[string]interface{}{"max_chrs": float64(500)}}
+	if errs := validateSchema(root, root, invalid, "$"); len(errs) != 1 {
+		t.Errorf("errors = %v, want the unknown chunking key", errs)
+	}
+}
@@ -0,0 +1,112 @@
+package kreuzberg_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func decodeConfigSchema(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	return schema
+}
+
+func schemaProperty(t *testing.T, schema map[string]interface{}, def, field string) map[string]interface{} {
+	t.Helper()
+	container := schema
+	if def != "" {
+		defs, ok := schema["$defs"].(map[string]interface{})
+		if !ok {
+			t.Fatal("schema is missing $defs")
+		}
+		container, ok = defs[def].(map[string]interface{})
+		if !ok {
+			t.Fatalf("schema is missing $defs/%s", def)
+		}
+	}
+	props, ok := container["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("%s has no properties", def)
+	}
+	prop, ok := props[field].(map[string]interface{})
+	if !ok {
+		t.Fatalf("%s is missing property %s", def, field)
+	}
+	return prop
+}
+
+func enumContains(prop map[string]interface{}, value string) bool {
+	values, _ := prop["enum"].([]interface{})
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConfigJSONSchema(t *testing.T) {
+	data, err := kreuzberg.ConfigJSONSchema()
+	if err != nil {
+		t.Fatalf("ConfigJSONSchema() error = %v", err)
+	}
+	schema := decodeConfigSchema(t, data)
+
+	if schema["$schema"] != kreuzberg.ConfigSchemaDraft {
+		t.Errorf("$schema = %v, want %s", schema["$schema"], kreuzberg.ConfigSchemaDraft)
+	}
+
+	if ref := schemaProperty(t, schema, "", "ocr")["$ref"]; ref != "#/$defs/OCRConfig" {
+		t.Errorf("ocr $ref = %v, want #/$defs/OCRConfig", ref)
+	}
+	if schemaProperty(t, schema, "", "profile")["type"] != "string" {
+		t.Error("profile should be a string property so profile-based config files validate")
+	}
+	if !enumContains(schemaProperty(t, schema, "", "output_format"), "markdown") {
+		t.Error("output_format enum should contain markdown")
+	}
+	if !enumContains(schemaProperty(t, schema, "", "result_format"), "element_based") {
+		t.Error("result_format enum should contain element_based")
+	}
+	if !enumContains(schemaProperty(t, schema, "OCRConfig", "backend"), "tesseract") {
+		t.Error("OCR backend enum should contain tesseract")
+	}
+
+	psm := schemaProperty(t, schema, "TesseractConfig", "psm")
+	if psm["minimum"] != float64(0) || psm["maximum"] != float64(13) {
+		t.Errorf("psm range = [%v, %v], want [0, 13]", psm["minimum"], psm["maximum"])
+	}
+
+	ngram := schemaProperty(t, schema, "KeywordConfig", "ngram_range")
+	if ngram["type"] != "array" || ngram["minItems"] != float64(2) {
+		t.Errorf("ngram_range should be a two-element array, got %v", ngram)
+	}
+}
+
+func TestWriteConfigJSONSchema(t *testing.T) {
+	if err := kreuzberg.WriteConfigJSONSchema(""); err == nil {
+		t.Error("expected error for empty path")
+	}
+
+	path := filepath.Join(t.TempDir(), "kreuzberg.schema.json")
+	if err := kreuzberg.WriteConfigJSONSchema(path); err != nil {
+		t.Fatalf("WriteConfigJSONSchema() error = %v", err)
+	}
+
+	// #nosec G304 -- path is inside the test's temp directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read schema: %v", err)
+	}
+	schema := decodeConfigSchema(t, data)
+	if schema["$id"] != kreuzberg.ConfigSchemaID {
+		t.Errorf("$id = %v, want %s", schema["$id"], kreuzberg.ConfigSchemaID)
+	}
+}
@@ -87,13 +87,17 @@ type ExtractionConfig struct {
 	MaxConcurrentExtractions *int                     `json:"max_concurrent_extractions,omitempty"`
 	OutputFormat             string                   `json:"output_format,omitempty"`
 	ResultFormat             string                   `json:"result_format,omitempty"`
+	// Router, when set, picks a config for each document after MIME detection. The
+	// routed config is layered on top of the other fields. It is not serialized.
+	Router *ConfigRouter `json:"-"`
 }
 
 // OCRConfig selects and configures OCR backends.
 type OCRConfig struct {
-	Backend   string           `json:"backend,omitempty"`
-	Language  *string          `json:"language,omitempty"`
-	Tesseract *TesseractConfig `json:"tesseract_config,omitempty"`
+	Backend      string           `json:"backend,omitempty"`
+	Language     *string          `json:"language,omitempty"`
+	Tesseract    *TesseractConfig `json:"tesseract_config,omitempty"`
+	OutputFormat string           `json:"output_format,omitempty"`
 }
 
 // TesseractConfig exposes fine-grained controls for the Tesseract backend.
@@ -134,12 +138,15 @@ type ImagePreprocessingConfig struct {
 
 // ChunkingConfig configures text chunking for downstream RAG/Retrieval workloads.
 type ChunkingConfig struct {
-	MaxChars     *int    `json:"max_chars,omitempty"`
-	MaxOverlap   *int    `json:"max_overlap,omitempty"`
-	ChunkSize    *int    `json:"chunk_size,omitempty"`
-	ChunkOverlap *int    `json:"chunk_overlap,omitempty"`
-	Preset       *string `json:"preset,omitempty"`
-	Enabled      *bool   `json:"enabled,omitempty"`
+	MaxChars     *int             `json:"max_chars,omitempty"`
+	MaxOverlap   *int             `json:"max_overlap,omitempty"`
+	ChunkSize    *int             `json:"chunk_size,omitempty"`
+	ChunkOverlap *int             `json:"chunk_overlap,omitempty"`
+	Preset       *string          `json:"preset,omitempty"`
+	Enabled      *bool            `json:"enabled,omitempty"`
+	Trim         *bool            `json:"trim,omitempty"`
+	ChunkerType  string           `json:"chunker_type,omitempty"`
+	Embedding    *EmbeddingConfig `json:"embedding,omitempty"`
 }
 
 // ImageExtractionConfig controls inline image extraction from PDFs/Office docs.
@@ -160,10 +167,11 @@ type FontConfig struct {
 
 // PdfConfig exposes PDF-specific options.
 type PdfConfig struct {
-	ExtractImages   *bool       `json:"extract_images,omitempty"`
-	Passwords       []string    `json:"passwords,omitempty"`
-	ExtractMetadata *bool       `json:"extract_metadata,omitempty"`
-	FontConfig      *FontConfig `json:"font_config,omitempty"`
+	ExtractImages   *bool            `json:"extract_images,omitempty"`
+	Passwords       []string         `json:"passwords,omitempty"`
+	ExtractMetadata *bool            `json:"extract_metadata,omitempty"`
+	FontConfig      *FontConfig      `json:"font_config,omitempty"`
+	Hierarchy       *HierarchyConfig `json:"hierarchy,omitempty"`
 }
 
 // HierarchyConfig controls PDF hierarchy extraction based on font sizes.
@@ -289,6 +297,11 @@ type PageConfig struct {
 	ExtractPages      *bool   `json:"extract_pages,omitempty"`
 	InsertPageMarkers *bool   `json:"insert_page_markers,omitempty"`
 	MarkerFormat      *string `json:"marker_format,omitempty"`
+	// Ranges selects the pages to return, e.g. "1-3,10,-2"; see ParsePageRanges. PDF
+	// extraction only processes the selected pages. For other formats every page is
+	// extracted and the binding narrows the result. ExtractionResult.PageSelection
+	// reports the selection.
+	Ranges *string `json:"ranges,omitempty"`
 }
 
 // OutputFormat controls the format of extracted content.
@@ -0,0 +1,331 @@
+package kreuzberg
+
+import (
+	"math"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatePrecision is the most specific component present in a parsed date.
+type DatePrecision int
+
+const (
+	// DatePrecisionYear marks a date with only a year, e.g. "2023".
+	DatePrecisionYear DatePrecision = iota + 1
+	// DatePrecisionMonth marks a date with a year and month.
+	DatePrecisionMonth
+	// DatePrecisionDay marks a calendar date without a time.
+	DatePrecisionDay
+	// DatePrecisionHour marks a date with an hour.
+	DatePrecisionHour
+	// DatePrecisionMinute marks a date with hours and minutes.
+	DatePrecisionMinute
+	// DatePrecisionSecond marks a date with seconds or finer.
+	DatePrecisionSecond
+)
+
+// DocumentDate is a date parsed by ParseDocumentDate.
+type DocumentDate struct {
+	// Time is the instant in UTC. Missing components default to the start of the period,
+	// e.g. "2023-05" is 2023-05-01T00:00:00Z.
+	Time time.Time
+	// Precision is the most specific component present in the source.
+	Precision DatePrecision
+	// HasZone reports whether the source carried a time zone; dates without one are
+	// taken as UTC.
+	HasZone bool
+}
+
+var (
+	pdfDatePattern = regexp.MustCompile(
+		`^(?:D:)?(\d{4})(\d{2})?(\d{2})?(\d{2})?(\d{2})?(\d{2})?\s*(Z(?:\d{2}'?(?:\d{2}'?)?)?|[+\-]\d{2}'?(?:\d{2}'?)?)?$`)
+	isoDatePattern = regexp.MustCompile(
+		`^(\d{4})(?:-(\d{2})(?:-(\d{2})(?:[T ](\d{2})(?::(\d{2})(?::(\d{2})(?:[.,](\d+))?)?)?)?)?)?\s*(Z|UTC|GMT|[+\-]\d{2}(?::?\d{2})?)?$`)
+	isoBasicPattern = regexp.MustCompile(
+		`^(\d{4})(\d{2})(\d{2})T(\d{2})(\d{2})(\d{2})?(?:[.,](\d+))?(Z|[+\-]\d{2}:?\d{2})?$`)
+	officeSerialPattern = regexp.MustCompile(`^\d{5}(?:\.\d+)?$`)
+	fileTimePattern     = regexp.MustCompile(`^\d{17,18}$`)
+)
+
+// textDateLayouts are tried after RFC 2822 for dates written out in words.
+var textDateLayouts = []struct {
+	layout    string
+	precision DatePrecision
+	zone      bool
+}{
+	{time.RFC850, DatePrecisionSecond, true},
+	{time.ANSIC, DatePrecisionSecond, false},
+	{time.UnixDate, DatePrecisionSecond, true},
+	{"Mon Jan 2 15:04:05 2006 -0700", DatePrecisionSecond, true},
+	{"January 2, 2006 15:04:05", DatePrecisionSecond, false},
+	{"January 2, 2006", DatePrecisionDay, false},
+	{"Jan 2, 2006", DatePrecisionDay, false},
+	{"2 January 2006", DatePrecisionDay, false},
+	{"2 Jan 2006", DatePrecisionDay, false},
+	{"January 2006", DatePrecisionMonth, false},
+}
+
+// ParseDocumentDate parses a document date in any of the formats produced by the
+// extractors: PDF dates ("D:20230105120000+01'00'", also partial such as "D:2023"),
+// ISO 8601 and RFC 3339 (including partial dates like "2023-05"), RFC 2822 email dates,
+// Office timestamps (W3C dates, Excel serial day numbers and Windows FILETIME values),
+// and dates written out in English words. The result is normalized to UTC.
+func ParseDocumentDate(s string) (DocumentDate, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DocumentDate{}, false
+	}
+	for _, parse := range []func(string) (DocumentDate, bool){
+		parsePDFDate, parseISODate, parseOfficeDate, parseTextDate,
+	} {
+		if date, ok := parse(s); ok {
+			date.Time = date.Time.UTC()
+			return date, true
+		}
+	}
+	return DocumentDate{}, false
+}
+
+// CreatedTime parses CreatedAt, falling back to the PDF creation date, with
+// ParseDocumentDate.
+func (m *Metadata) CreatedTime() (time.Time, bool) {
+	return m.resolveDate(m.CreatedAt, func(pdf *PdfMetadata) *string { return pdf.CreatedAt })
+}
+
+// ModifiedTime parses ModifiedAt, falling back to the PDF modification date, with
+// ParseDocumentDate.
+func (m *Metadata) ModifiedTime() (time.Time, bool) {
+	return m.resolveDate(m.ModifiedAt, func(pdf *PdfMetadata) *string { return pdf.ModifiedAt })
+}
+
+// MetadataDates holds the parsed document dates stored by Metadata.ResolveDates.
+type MetadataDates struct {
+	Created  *DocumentDate
+	Modified *DocumentDate
+}
+
+// ResolveDates parses the creation and modification dates once and stores them in
+// ParsedDates, so that callers such as search indexers can read typed dates directly.
+// It reports whether any date was parsed.
+func (m *Metadata) ResolveDates() bool {
+	dates := &MetadataDates{}
+	if raw := m.dateSource(m.CreatedAt, func(pdf *PdfMetadata) *string { return pdf.CreatedAt }); raw != nil {
+		if date, ok := ParseDocumentDate(*raw); ok {
+			dates.Created = &date
+		}
+	}
+	if raw := m.dateSource(m.ModifiedAt, func(pdf *PdfMetadata) *string { return pdf.ModifiedAt }); raw != nil {
+		if date, ok := ParseDocumentDate(*raw); ok {
+			dates.Modified = &date
+		}
+	}
+	if dates.Created == nil && dates.Modified == nil {
+		m.ParsedDates = nil
+		return false
+	}
+	m.ParsedDates = dates
+	return true
+}
+
+// CreatedTime parses CreatedAt with ParseDocumentDate.
+func (p *PdfMetadata) CreatedTime() (time.Time, bool) {
+	return parseDocumentTime(p.CreatedAt)
+}
+
+// ModifiedTime parses ModifiedAt with ParseDocumentDate.
+func (p *PdfMetadata) ModifiedTime() (time.Time, bool) {
+	return parseDocumentTime(p.ModifiedAt)
+}
+
+func (m *Metadata) resolveDate(value *string, pdf func(*PdfMetadata) *string) (time.Time, bool) {
+	return parseDocumentTime(m.dateSource(value, pdf))
+}
+
+func (m *Metadata) dateSource(value *string, pdf func(*PdfMetadata) *string) *string {
+	if value != nil && strings.TrimSpace(*value) != "" {
+		return value
+	}
+	if m.Format.Pdf != nil {
+		return pdf(m.Format.Pdf)
+	}
+	return nil
+}
+
+func parseDocumentTime(value *string) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	date, ok := ParseDocumentDate(*value)
+	return date.Time, ok
+}
+
+// dateParts collects parsed components; -1 marks a missing one.
+type dateParts struct {
+	year, month, day, hour, minute, second, nanos int
+	loc                                           *time.Location
+}
+
+func (p dateParts) build() (DocumentDate, bool) {
+	precision := DatePrecisionYear
+	components := []int{p.month, p.day, p.hour, p.minute, p.second}
+	defaults := []int{1, 1, 0, 0, 0}
+	values := make([]int, len(components))
+	for i, v := range components {
+		if v < 0 {
+			values[i] = defaults[i]
+			continue
+		}
+		values[i] = v
+		precision = DatePrecision(i + 2)
+	}
+	month, day, hour, minute, second := values[0], values[1], values[2], values[3], values[4]
+	if month < 1 || month > 12 || day < 1 || hour > 23 || minute > 59 || second > 60 {
+		return DocumentDate{}, false
+	}
+	loc := p.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+	t := time.Date(p.year, time.Month(month), day, hour, minute, second, p.nanos, loc)
+	if t.Day() != day {
+		return DocumentDate{}, false
+	}
+	return DocumentDate{Time: t, Precision: precision, HasZone: p.loc != nil}, true
+}
+
+func parsePDFDate(s string) (DocumentDate, bool) {
+	m := pdfDatePattern.FindStringSubmatch(s)
+	if m == nil {
+		return DocumentDate{}, false
+	}
+	parts := dateParts{year: atoiOr(m[1], -1), month: atoiOr(m[2], -1), day: atoiOr(m[3], -1),
+		hour: atoiOr(m[4], -1), minute: atoiOr(m[5], -1), second: atoiOr(m[6], -1)}
+	zone := strings.ReplaceAll(m[7], "'", "")
+	if strings.HasPrefix(zone, "Z") {
+		// Z marks UT; some writers still append an offset, which is then 00'00'.
+		zone = "Z"
+	}
+	if zone != "" {
+		// Without the "D:" prefix a zone needs a time, so "2023-05" is not read as
+		// 2023 at UTC-05.
+		if m[4] == "" && !strings.HasPrefix(s, "D:") {
+			return DocumentDate{}, false
+		}
+		loc, ok := parseZone(zone)
+		if !ok {
+			return DocumentDate{}, false
+		}
+		parts.loc = loc
+	}
+	return parts.build()
+}
+
+func parseISODate(s string) (DocumentDate, bool) {
+	m := isoDatePattern.FindStringSubmatch(s)
+	if m == nil {
+		b := isoBasicPattern.FindStringSubmatch(s)
+		if b == nil {
+			return DocumentDate{}, false
+		}
+		m = b
+	}
+	parts := dateParts{year: atoiOr(m[1], -1), month: atoiOr(m[2], -1), day: atoiOr(m[3], -1),
+		hour: atoiOr(m[4], -1), minute: atoiOr(m[5], -1), second: atoiOr(m[6], -1)}
+	if fraction := m[7]; fraction != "" {
+		if len(fraction) > 9 {
+			fraction = fraction[:9]
+		}
+		parts.nanos = atoiOr(fraction+strings.Repeat("0", 9-len(fraction)), 0)
+	}
+	if m[8] != "" {
+		loc, ok := parseZone(m[8])
+		if !ok {
+			return DocumentDate{}, false
+		}
+		parts.loc = loc
+	}
+	return parts.build()
+}
+
+// parseOfficeDate handles Excel serial day numbers (1900 date system) and Windows
+// FILETIME values (100-nanosecond intervals since 1601-01-01 UTC).
+func parseOfficeDate(s string) (DocumentDate, bool) {
+	switch {
+	case fileTimePattern.MatchString(s):
+		ticks, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return DocumentDate{}, false
+		}
+		const unixEpochTicks = 116444736000000000
+		t := time.Unix(0, 0).Add(time.Duration(ticks-unixEpochTicks) * 100)
+		return DocumentDate{Time: t, Precision: DatePrecisionSecond, HasZone: true}, true
+	case officeSerialPattern.MatchString(s):
+		serial, err := strconv.ParseFloat(s, 64)
+		if err != nil || serial < 1 || serial > 2958465 {
+			return DocumentDate{}, false
+		}
+		days := math.Floor(serial)
+		seconds := math.Round((serial - days) * 86400)
+		t := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+		precision := DatePrecisionDay
+		if seconds > 0 {
+			precision = DatePrecisionSecond
+		}
+		return DocumentDate{Time: t, Precision: precision}, true
+	}
+	return DocumentDate{}, false
+}
+
+func parseTextDate(s string) (DocumentDate, bool) {
+	if t, err := mail.ParseDate(s); err == nil {
+		return DocumentDate{Time: t, Precision: DatePrecisionSecond, HasZone: true}, true
+	}
+	for _, layout := range textDateLayouts {
+		if t, err := time.Parse(layout.layout, s); err == nil {
+			return DocumentDate{Time: t, Precision: layout.precision, HasZone: layout.zone}, true
+		}
+	}
+	return DocumentDate{}, false
+}
+
+// parseZone parses "Z", "UTC", "GMT", "+01", "+0100" and "+01:00".
+func parseZone(zone string) (*time.Location, bool) {
+	switch strings.ToUpper(zone) {
+	case "Z", "UTC", "GMT":
+		return time.UTC, true
+	}
+	sign := 1
+	if zone[0] == '-' {
+		sign = -1
+	}
+	digits := strings.ReplaceAll(zone[1:], ":", "")
+	hours := atoiOr(digits[:2], -1)
+	minutes := 0
+	if len(digits) == 4 {
+		minutes = atoiOr(digits[2:], -1)
+	} else if len(digits) != 2 {
+		return nil, false
+	}
+	if hours < 0 || hours > 14 || minutes < 0 || minutes > 59 {
+		return nil, false
+	}
+	offset := sign * (hours*3600 + minutes*60)
+	if offset == 0 {
+		return time.UTC, true
+	}
+	return time.FixedZone("", offset), true
+}
+
+func atoiOr(s string, fallback int) int {
+	if s == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+	return n
+}
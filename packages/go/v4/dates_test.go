@@ -0,0 +1,83 @@
+package kreuzberg_test
+
+import (
+	"testing"
+	"time"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func TestParseDocumentDate(t *testing.T) {
+	cases := []struct {
+		input     string
+		want      string
+		precision kreuzberg.DatePrecision
+		hasZone   bool
+	}{
+		{"D:20230105120000+01'00'", "2023-01-05T11:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"D:20230105120000Z", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"D:20230105120000Z00'00'", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"2023-01-05T12:00:00+01:00", "2023-01-05T11:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"D:202301", "2023-01-01T00:00:00Z", kreuzberg.DatePrecisionMonth, false},
+		{"D:2023", "2023-01-01T00:00:00Z", kreuzberg.DatePrecisionYear, false},
+		{"20230105093000-05'30", "2023-01-05T15:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"2023-01-05T12:00:00.250+02:00", "2023-01-05T10:00:00.25Z", kreuzberg.DatePrecisionSecond, true},
+		{"2023-01-05 12:30", "2023-01-05T12:30:00Z", kreuzberg.DatePrecisionMinute, false},
+		{"2023-05", "2023-05-01T00:00:00Z", kreuzberg.DatePrecisionMonth, false},
+		{"20230105T120000Z", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"Thu, 5 Jan 2023 12:00:00 -0800", "2023-01-05T20:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"Thu, 05 Jan 2023 12:00:00 +0000 (UTC)", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"44931.5", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, false},
+		{"133173936000000000", "2023-01-05T12:00:00Z", kreuzberg.DatePrecisionSecond, true},
+		{"January 5, 2023", "2023-01-05T00:00:00Z", kreuzberg.DatePrecisionDay, false},
+	}
+	for _, tc := range cases {
+		date, ok := kreuzberg.ParseDocumentDate(tc.input)
+		if !ok {
+			t.Errorf("ParseDocumentDate(%q) failed", tc.input)
+			continue
+		}
+		if got := date.Time.Format(time.RFC3339Nano); got != tc.want {
+			t.Errorf("ParseDocumentDate(%q) = %s, want %s", tc.input, got, tc.want)
+		}
+		if date.Precision != tc.precision || date.HasZone != tc.hasZone {
+			t.Errorf("ParseDocumentDate(%q) precision %d zone %v, want %d %v", tc.input, date.Precision, date.HasZone, tc.precision, tc.hasZone)
+		}
+		if date.Time.Location() != time.UTC {
+			t.Errorf("ParseDocumentDate(%q) not normalized to UTC", tc.input)
+		}
+	}
+
+	for _, input := range []string{"", "yesterday", "2023-02-30", "D:20231301", "2023-01-05T25:00"} {
+		if date, ok := kreuzberg.ParseDocumentDate(input); ok {
+			t.Errorf("ParseDocumentDate(%q) = %v, want failure", input, date.Time)
+		}
+	}
+}
+
+func TestMetadataDateAccessors(t *testing.T) {
+	created := "D:20230105120000Z"
+	pdfModified := "2024-03-01"
+	meta := kreuzberg.Metadata{
+		CreatedAt: &created,
+		Format:    kreuzberg.FormatMetadata{Type: kreuzberg.FormatPDF, Pdf: &kreuzberg.PdfMetadata{ModifiedAt: &pdfModified}},
+	}
+
+	if got, ok := meta.CreatedTime(); !ok || !got.Equal(time.Date(2023, 1, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedTime() = %v, %v", got, ok)
+	}
+	if got, ok := meta.ModifiedTime(); !ok || got.Format("2006-01-02") != "2024-03-01" {
+		t.Errorf("ModifiedTime() should fall back to the PDF date, got %v, %v", got, ok)
+	}
+	if _, ok := meta.Format.Pdf.CreatedTime(); ok {
+		t.Error("PdfMetadata.CreatedTime() should fail without a value")
+	}
+
+	if !meta.ResolveDates() || meta.ParsedDates.Created == nil || meta.ParsedDates.Modified.Precision != kreuzberg.DatePrecisionDay {
+		t.Errorf("ResolveDates() = %+v", meta.ParsedDates)
+	}
+	empty := kreuzberg.Metadata{}
+	if empty.ResolveDates() || empty.ParsedDates != nil {
+		t.Error("ResolveDates() should report false without dates")
+	}
+}
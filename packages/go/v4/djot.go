@@ -0,0 +1,631 @@
+package kreuzberg
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DjotWalkFunc is called by DjotContent.Walk when entering or leaving a node.
+//
+// When returned from the enter hook, false skips the node's children and its leave
+// hook. When returned from the leave hook, false stops the walk.
+type DjotWalkFunc func(c *DjotCursor) bool
+
+// DjotCursor describes the node currently visited by DjotContent.Walk. Exactly one of
+// Block, Inline and Footnote is non-nil. The node may be modified in place through the
+// returned pointer, or replaced and deleted with the cursor methods.
+type DjotCursor struct {
+	block    *FormattedBlock
+	inline   *InlineElement
+	footnote *Footnote
+	parent   *FormattedBlock
+	within   *Footnote
+	depth    int
+
+	replaced        bool
+	blockReplace    []FormattedBlock
+	inlineReplace   []InlineElement
+	footnoteReplace []Footnote
+}
+
+// Block returns the visited block, or nil.
+func (c *DjotCursor) Block() *FormattedBlock { return c.block }
+
+// Inline returns the visited inline element, or nil.
+func (c *DjotCursor) Inline() *InlineElement { return c.inline }
+
+// Footnote returns the visited footnote definition, or nil.
+func (c *DjotCursor) Footnote() *Footnote { return c.footnote }
+
+// Parent returns the block containing the visited node, or nil at the top level of the
+// document or of a footnote.
+func (c *DjotCursor) Parent() *FormattedBlock { return c.parent }
+
+// WithinFootnote returns the footnote whose content is being visited, or nil.
+func (c *DjotCursor) WithinFootnote() *Footnote { return c.within }
+
+// Depth returns the block nesting depth: 0 for top-level blocks and footnotes, the
+// depth of the enclosing block plus one for children and inline elements.
+func (c *DjotCursor) Depth() int { return c.depth }
+
+// ReplaceBlock replaces the visited block with zero or more blocks. Replacements are
+// not walked. It panics if the cursor is not on a block.
+func (c *DjotCursor) ReplaceBlock(blocks ...FormattedBlock) {
+	if c.block == nil {
+		panic("kreuzberg: DjotCursor.ReplaceBlock called on a non-block node")
+	}
+	c.replaced = true
+	c.blockReplace = blocks
+}
+
+// ReplaceInline replaces the visited inline element with zero or more elements.
+// Replacements are not walked. It panics if the cursor is not on an inline element.
+func (c *DjotCursor) ReplaceInline(inlines ...InlineElement) {
+	if c.inline == nil {
+		panic("kreuzberg: DjotCursor.ReplaceInline called on a non-inline node")
+	}
+	c.replaced = true
+	c.inlineReplace = inlines
+}
+
+// ReplaceFootnote replaces the visited footnote with zero or more footnotes.
+// Replacements are not walked. It panics if the cursor is not on a footnote.
+func (c *DjotCursor) ReplaceFootnote(footnotes ...Footnote) {
+	if c.footnote == nil {
+		panic("kreuzberg: DjotCursor.ReplaceFootnote called on a non-footnote node")
+	}
+	c.replaced = true
+	c.footnoteReplace = footnotes
+}
+
+// Delete removes the visited node.
+func (c *DjotCursor) Delete() {
+	c.replaced = true
+	c.blockReplace, c.inlineReplace, c.footnoteReplace = nil, nil, nil
+}
+
+// Walk traverses the document depth-first: each block is entered, then its inline
+// elements and child blocks are walked, then it is left. Footnote definitions are
+// walked after the blocks, with their content blocks as children. Either hook may be
+// nil. Changes made through the cursor are applied to d in place.
+//
+// Walk does not update PlainText, Links or Images; re-derive them if needed.
+func (d *DjotContent) Walk(enter, leave DjotWalkFunc) {
+	if d == nil {
+		return
+	}
+	w := &djotWalker{enter: enter, leave: leave}
+	d.Blocks = w.blocks(d.Blocks, nil, nil, 0)
+	d.Footnotes = w.footnotes(d.Footnotes)
+}
+
+type djotWalker struct {
+	enter, leave DjotWalkFunc
+	stopped      bool
+}
+
+func (w *djotWalker) visit(c *DjotCursor, hook DjotWalkFunc) bool {
+	if hook == nil {
+		return true
+	}
+	return hook(c)
+}
+
+func (w *djotWalker) blocks(blocks []FormattedBlock, parent *FormattedBlock, within *Footnote, depth int) []FormattedBlock {
+	if blocks == nil || w.stopped {
+		return blocks
+	}
+	out := make([]FormattedBlock, 0, len(blocks))
+	for i := range blocks {
+		if w.stopped {
+			out = append(out, blocks[i:]...)
+			break
+		}
+		block := blocks[i]
+		c := &DjotCursor{block: &block, parent: parent, within: within, depth: depth}
+		if w.visit(c, w.enter) && !c.replaced {
+			block.InlineContent = w.inlines(block.InlineContent, &block, within, depth+1)
+			block.Children = w.blocks(block.Children, &block, within, depth+1)
+			if !w.stopped && !w.visit(c, w.leave) {
+				w.stopped = true
+			}
+		}
+		if c.replaced {
+			out = append(out, c.blockReplace...)
+		} else {
+			out = append(out, block)
+		}
+	}
+	return out
+}
+
+func (w *djotWalker) inlines(inlines []InlineElement, parent *FormattedBlock, within *Footnote, depth int) []InlineElement {
+	if inlines == nil || w.stopped {
+		return inlines
+	}
+	out := make([]InlineElement, 0, len(inlines))
+	for i := range inlines {
+		if w.stopped {
+			out = append(out, inlines[i:]...)
+			break
+		}
+		inline := inlines[i]
+		c := &DjotCursor{inline: &inline, parent: parent, within: within, depth: depth}
+		if w.visit(c, w.enter) && !c.replaced && !w.visit(c, w.leave) {
+			w.stopped = true
+		}
+		if c.replaced {
+			out = append(out, c.inlineReplace...)
+		} else {
+			out = append(out, inline)
+		}
+	}
+	return out
+}
+
+func (w *djotWalker) footnotes(footnotes []Footnote) []Footnote {
+	if footnotes == nil || w.stopped {
+		return footnotes
+	}
+	out := make([]Footnote, 0, len(footnotes))
+	for i := range footnotes {
+		if w.stopped {
+			out = append(out, footnotes[i:]...)
+			break
+		}
+		footnote := footnotes[i]
+		c := &DjotCursor{footnote: &footnote}
+		if w.visit(c, w.enter) && !c.replaced {
+			footnote.Content = w.blocks(footnote.Content, nil, &footnote, 1)
+			if !w.stopped && !w.visit(c, w.leave) {
+				w.stopped = true
+			}
+		}
+		if c.replaced {
+			out = append(out, c.footnoteReplace...)
+		} else {
+			out = append(out, footnote)
+		}
+	}
+	return out
+}
+
+// SerializeDjot converts content back to Djot markup: blocks first, then footnote
+// definitions. Text is escaped so that re-parsing the output yields the same tree.
+//
+// Tables are written as pipe tables at the position of their table block. Tables that
+// no block refers to follow the blocks.
+func SerializeDjot(content *DjotContent) string {
+	if content == nil {
+		return ""
+	}
+	w := &djotWriter{tables: content.Tables, placed: make([]bool, len(content.Tables))}
+	parts := make([]string, 0, len(content.Blocks)+len(content.Tables)+len(content.Footnotes))
+	for i := range content.Blocks {
+		if s := w.block(&content.Blocks[i]); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	for i := range content.Footnotes {
+		parts = append(parts, w.footnote(&content.Footnotes[i]))
+	}
+	for i := range content.Tables {
+		if !w.placed[i] {
+			parts = append(parts, djotTable(&content.Tables[i]))
+		}
+	}
+	return joinRendered(parts)
+}
+
+// djotWriter serializes blocks, tracking which tables have been written.
+type djotWriter struct {
+	tables []Table
+	placed []bool
+}
+
+// djotTableIndex returns the index into a DjotContent's tables of a table block.
+func djotTableIndex(block *FormattedBlock, tables int) (int, bool) {
+	if block.BlockType != BlockTypeTable || block.TableIndex == nil || *block.TableIndex >= uint64(tables) {
+		return 0, false
+	}
+	return int(*block.TableIndex), true
+}
+
+// djotTable writes table as a pipe table whose first row is the header.
+func djotTable(table *Table) string {
+	rows := make([]string, 0, len(table.Cells)+1)
+	for i, row := range table.Cells {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = strings.ReplaceAll(escapeDjotText(strings.TrimSpace(cell)), "|", `\|`)
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			rows = append(rows, "|"+strings.Repeat("---|", len(row)))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (w *djotWriter) footnote(footnote *Footnote) string {
+	body := w.blocks(footnote.Content)
+	return prefixLines("[^"+footnote.Label+"]: ", "  ", body)
+}
+
+func (w *djotWriter) blocks(blocks []FormattedBlock) string {
+	parts := make([]string, 0, len(blocks))
+	for i := range blocks {
+		if s := w.block(&blocks[i]); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (w *djotWriter) block(block *FormattedBlock) string {
+	body := w.blockBody(block)
+	if block.BlockType == BlockTypeSection || block.BlockType == BlockTypeListItem || (block.BlockType == BlockTypeTable && body == "") {
+		return body
+	}
+	if attrs := djotAttributes(block.Attributes); attrs != "" {
+		return attrs + "\n" + body
+	}
+	return body
+}
+
+func (w *djotWriter) blockBody(block *FormattedBlock) string {
+	switch block.BlockType {
+	case BlockTypeTable:
+		index, ok := djotTableIndex(block, len(w.tables))
+		if !ok || w.placed[index] {
+			return ""
+		}
+		w.placed[index] = true
+		return djotTable(&w.tables[index])
+	case BlockTypeHeading:
+		text := strings.ReplaceAll(djotInlines(block.InlineContent), "\n", " ")
+		return strings.Repeat("#", blockLevel(block)) + " " + text
+	case BlockTypeParagraph:
+		return escapeDjotLineStarts(djotInlines(block.InlineContent))
+	case BlockTypeCodeBlock:
+		code := blockCode(block)
+		fence := codeFence(code)
+		info := ""
+		if lang := blockLanguage(block); lang != "" {
+			info = " " + lang
+		}
+		return djotFenced(fence+info, code, fence)
+	case BlockTypeRawBlock:
+		code := blockCode(block)
+		fence := codeFence(code)
+		return djotFenced(fence+" ="+blockLanguage(block), code, fence)
+	case BlockTypeMathDisplay:
+		math := blockCode(block)
+		fence := inlineCodeFence(math)
+		return "$$" + fence + padInlineCode(math) + fence
+	case BlockTypeBlockquote:
+		parts := []string{}
+		if len(block.InlineContent) > 0 {
+			parts = append(parts, escapeDjotLineStarts(djotInlines(block.InlineContent)))
+		}
+		if children := w.blocks(block.Children); children != "" {
+			parts = append(parts, children)
+		}
+		return prefixLines("> ", "> ", strings.Join(parts, "\n\n"))
+	case BlockTypeBulletList, BlockTypeOrderedList, BlockTypeTaskList:
+		items := make([]string, 0, len(block.Children))
+		for i := range block.Children {
+			items = append(items, w.listItem(block, &block.Children[i], i))
+		}
+		return strings.Join(items, "\n")
+	case BlockTypeListItem:
+		return w.listItem(nil, block, 0)
+	case BlockTypeDefinitionList:
+		parts := make([]string, 0, len(block.Children))
+		for i := range block.Children {
+			child := &block.Children[i]
+			if child.BlockType == BlockTypeDefinitionTerm {
+				parts = append(parts, ": "+strings.ReplaceAll(djotInlines(child.InlineContent), "\n", " "))
+				continue
+			}
+			parts = append(parts, indentLines(w.definition(child), "  "))
+		}
+		return strings.Join(parts, "\n\n")
+	case BlockTypeDefinitionTerm:
+		return ": " + strings.ReplaceAll(djotInlines(block.InlineContent), "\n", " ")
+	case BlockTypeDefinitionDesc:
+		return w.definition(block)
+	case BlockTypeDiv:
+		fence := strings.Repeat(":", 3+djotDivDepth(block.Children))
+		parts := []string{fence}
+		if len(block.InlineContent) > 0 {
+			parts = append(parts, escapeDjotLineStarts(djotInlines(block.InlineContent)))
+		}
+		if children := w.blocks(block.Children); children != "" {
+			parts = append(parts, children)
+		}
+		return strings.Join(parts, "\n\n") + "\n\n" + fence
+	case BlockTypeSection:
+		body := w.blocks(block.Children)
+		if attrs := djotAttributes(block.Attributes); attrs != "" && body != "" {
+			return attrs + "\n" + body
+		}
+		return body
+	case BlockTypeThematicBreak:
+		return "* * *"
+	default:
+		parts := []string{}
+		if len(block.InlineContent) > 0 {
+			parts = append(parts, escapeDjotLineStarts(djotInlines(block.InlineContent)))
+		}
+		if children := w.blocks(block.Children); children != "" {
+			parts = append(parts, children)
+		}
+		return strings.Join(parts, "\n\n")
+	}
+}
+
+// listItem renders item with the marker for its position in list. The checked
+// state of task list items is stored by the extractor as a "checked" attribute.
+func (w *djotWriter) listItem(list, item *FormattedBlock, index int) string {
+	marker := "- "
+	attrs := item.Attributes
+	if list != nil {
+		switch list.BlockType {
+		case BlockTypeOrderedList:
+			marker = strconv.Itoa(index+1) + ". "
+		case BlockTypeTaskList:
+			var checked bool
+			checked, attrs = splitCheckedAttribute(attrs)
+			marker = "- [ ] "
+			if checked {
+				marker = "- [x] "
+			}
+		}
+	}
+
+	parts := []string{}
+	if len(item.InlineContent) > 0 {
+		parts = append(parts, escapeDjotLineStarts(djotInlines(item.InlineContent)))
+	}
+	if children := w.blocks(item.Children); children != "" {
+		parts = append(parts, children)
+	}
+	body := strings.Join(parts, "\n\n")
+	if body == "" {
+		body = "\\ "
+	}
+
+	out := prefixLines(marker, strings.Repeat(" ", len(marker)), body)
+	if s := djotAttributes(attrs); s != "" {
+		out = s + "\n" + out
+	}
+	return out
+}
+
+func splitCheckedAttribute(attrs *Attributes) (bool, *Attributes) {
+	if attrs == nil {
+		return false, nil
+	}
+	checked := false
+	rest := &Attributes{ID: attrs.ID, Classes: attrs.Classes}
+	for _, kv := range attrs.KeyValues {
+		if kv[0] == "checked" {
+			checked = kv[1] == "true"
+			continue
+		}
+		rest.KeyValues = append(rest.KeyValues, kv)
+	}
+	return checked, rest
+}
+
+func (w *djotWriter) definition(block *FormattedBlock) string {
+	parts := []string{}
+	if len(block.InlineContent) > 0 {
+		parts = append(parts, escapeDjotLineStarts(djotInlines(block.InlineContent)))
+	}
+	if children := w.blocks(block.Children); children != "" {
+		parts = append(parts, children)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func djotDivDepth(blocks []FormattedBlock) int {
+	depth := 0
+	for i := range blocks {
+		d := djotDivDepth(blocks[i].Children)
+		if blocks[i].BlockType == BlockTypeDiv {
+			d++
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+func djotFenced(open, body, close string) string {
+	if body == "" {
+		return open + "\n" + close
+	}
+	return open + "\n" + body + "\n" + close
+}
+
+// prefixLines prefixes the first line of text with first and the remaining non-empty
+// lines with rest.
+func prefixLines(first, rest, text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line == "":
+			lines[i] = strings.TrimRight(rest, " ")
+		default:
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func djotInlines(inlines []InlineElement) string {
+	var b strings.Builder
+	for i := range inlines {
+		b.WriteString(djotInline(&inlines[i]))
+	}
+	return b.String()
+}
+
+func djotInline(inline *InlineElement) string {
+	content := inline.Content
+	attrs := djotAttributes(inline.Attributes)
+
+	var s string
+	switch inline.ElementType {
+	case InlineTypeText:
+		s = escapeDjotText(content)
+		if attrs != "" {
+			s = "[" + s + "]"
+		}
+	case InlineTypeStrong:
+		s = djotDelimited("*", "*", content)
+	case InlineTypeEmphasis:
+		s = djotDelimited("_", "_", content)
+	case InlineTypeHighlight:
+		s = "{=" + escapeDjotText(content) + "=}"
+	case InlineTypeSubscript:
+		s = djotDelimited("~", "~", content)
+	case InlineTypeSuperscript:
+		s = djotDelimited("^", "^", content)
+	case InlineTypeInsert:
+		s = "{+" + escapeDjotText(content) + "+}"
+	case InlineTypeDelete:
+		s = "{-" + escapeDjotText(content) + "-}"
+	case InlineTypeCode:
+		fence := inlineCodeFence(content)
+		s = fence + padInlineCode(content) + fence
+	case InlineTypeLink:
+		href := inline.Metadata["href"]
+		if content == "" {
+			s = "<" + href + ">"
+		} else {
+			s = "[" + escapeDjotText(content) + "](" + escapeDjotDestination(href) + ")"
+		}
+	case InlineTypeImage:
+		s = "![" + escapeDjotText(content) + "](" + escapeDjotDestination(inline.Metadata["src"]) + ")"
+	case InlineTypeSpan:
+		s = escapeDjotText(content)
+		if attrs != "" {
+			s = "[" + s + "]"
+		}
+	case InlineTypeMath:
+		fence := inlineCodeFence(content)
+		s = "$" + fence + padInlineCode(content) + fence
+		if inline.Metadata["display"] == "true" {
+			s = "$" + s
+		}
+	case InlineTypeRawInline:
+		format := inline.Metadata["format"]
+		if format == "" {
+			format = "html"
+		}
+		fence := inlineCodeFence(content)
+		s = fence + padInlineCode(content) + fence + "{=" + format + "}"
+		attrs = ""
+	case InlineTypeFootnoteRef:
+		s = "[^" + content + "]"
+	case InlineTypeSymbol:
+		s = ":" + content + ":"
+	default:
+		s = escapeDjotText(content)
+	}
+	return s + attrs
+}
+
+// djotDelimited wraps content in delimiters, using the explicit {* *} form when the
+// content starts or ends with whitespace.
+func djotDelimited(open, close, content string) string {
+	if content == "" {
+		return ""
+	}
+	escaped := escapeDjotText(content)
+	if strings.TrimSpace(content) != content {
+		return "{" + open + escaped + close + "}"
+	}
+	return open + escaped + close
+}
+
+func inlineCodeFence(code string) string {
+	longest, run := 0, 0
+	for _, r := range code {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func padInlineCode(code string) string {
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return " " + code + " "
+	}
+	return code
+}
+
+func djotAttributes(attrs *Attributes) string {
+	if attrs == nil {
+		return ""
+	}
+	parts := []string{}
+	if attrs.ID != nil && *attrs.ID != "" {
+		parts = append(parts, "#"+*attrs.ID)
+	}
+	for _, class := range attrs.Classes {
+		parts = append(parts, "."+class)
+	}
+	for _, kv := range attrs.KeyValues {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(kv[1])
+		parts = append(parts, kv[0]+`="`+value+`"`)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+var djotTextEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"{", `\{`, "}", `\}`, "~", `\~`, "^", `\^`, "<", `\<`,
+)
+
+// djotSymbolPattern matches text that Djot would parse as a :symbol:.
+var djotSymbolPattern = regexp.MustCompile(`:([A-Za-z0-9+-]+):`)
+
+func escapeDjotText(text string) string {
+	return djotSymbolPattern.ReplaceAllString(djotTextEscaper.Replace(text), `\:$1:`)
+}
+
+func escapeDjotDestination(dest string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(dest)
+}
+
+var djotBlockStartPattern = regexp.MustCompile(`^(\s*)([#>|:+-]|\d+[.)])`)
+
+// escapeDjotLineStarts escapes characters at the start of a line that would otherwise
+// begin a heading, blockquote, list, definition, table or thematic break.
+func escapeDjotLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if m := djotBlockStartPattern.FindStringSubmatchIndex(line); m != nil {
+			punct := m[5] - 1
+			lines[i] = line[:punct] + `\` + line[punct:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
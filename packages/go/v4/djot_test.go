@@ -0,0 +1,213 @@
+package kreuzberg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func djotText(s string) []kreuzberg.InlineElement {
+	return []kreuzberg.InlineElement{{ElementType: kreuzberg.InlineTypeText, Content: s}}
+}
+
+func djotPara(inlines ...kreuzberg.InlineElement) kreuzberg.FormattedBlock {
+	return kreuzberg.FormattedBlock{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: inlines}
+}
+
+func newDjotTestContent() *kreuzberg.DjotContent {
+	h1, h2 := uint64(1), uint64(2)
+	lang := "go"
+	code := "x := `a`"
+	return &kreuzberg.DjotContent{
+		Blocks: []kreuzberg.FormattedBlock{
+			{BlockType: kreuzberg.BlockTypeHeading, Level: &h1, InlineContent: djotText("Title"),
+				Attributes: &kreuzberg.Attributes{ID: kreuzberg.StringPtr("top"), Classes: []string{"lead"}}},
+			djotPara(
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeText, Content: "Use *stars* at 10:30:00 "},
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeStrong, Content: "bold"},
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeText, Content: " and "},
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeLink, Content: "a link", Metadata: map[string]string{"href": "https://example.com/a_(b)"}},
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeImage, Content: "logo", Metadata: map[string]string{"src": "logo.png"}},
+				kreuzberg.InlineElement{ElementType: kreuzberg.InlineTypeFootnoteRef, Content: "1"},
+			),
+			{BlockType: kreuzberg.BlockTypeHeading, Level: &h2, InlineContent: djotText("Steps")},
+			{BlockType: kreuzberg.BlockTypeOrderedList, Children: []kreuzberg.FormattedBlock{
+				{BlockType: kreuzberg.BlockTypeListItem, Children: []kreuzberg.FormattedBlock{djotPara(djotText("first")...)}},
+				{BlockType: kreuzberg.BlockTypeListItem, Children: []kreuzberg.FormattedBlock{
+					djotPara(djotText("second")...),
+					{BlockType: kreuzberg.BlockTypeTaskList, Children: []kreuzberg.FormattedBlock{
+						{BlockType: kreuzberg.BlockTypeListItem, Attributes: &kreuzberg.Attributes{KeyValues: [][2]string{{"checked", "true"}}},
+							Children: []kreuzberg.FormattedBlock{djotPara(djotText("done")...)}},
+					}},
+				}},
+			}},
+			{BlockType: kreuzberg.BlockTypeCodeBlock, Language: &lang, Code: &code},
+			{BlockType: kreuzberg.BlockTypeBlockquote, Children: []kreuzberg.FormattedBlock{djotPara(djotText("# not a heading")...)}},
+		},
+		Footnotes: []kreuzberg.Footnote{
+			{Label: "1", Content: []kreuzberg.FormattedBlock{djotPara(append(djotText("See "), kreuzberg.InlineElement{
+				ElementType: kreuzberg.InlineTypeLink, Content: "notes", Metadata: map[string]string{"href": "http://old.example.com/notes"},
+			})...)}},
+		},
+	}
+}
+
+func TestSerializeDjot(t *testing.T) {
+	got := kreuzberg.SerializeDjot(newDjotTestContent())
+	want := "{#top .lead}\n# Title\n\n" +
+		"Use \\*stars\\* at 10\\:30:00 *bold* and [a link](https://example.com/a_\\(b\\))![logo](logo.png)[^1]\n\n" +
+		"## Steps\n\n" +
+		"1. first\n2. second\n\n   - [x] done\n\n" +
+		"``` go\nx := `a`\n```\n\n" +
+		"> \\# not a heading\n\n" +
+		"[^1]: See [notes](http://old.example.com/notes)\n"
+	if got != want {
+		t.Errorf("SerializeDjot() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDjotWalkTransforms(t *testing.T) {
+	content := newDjotTestContent()
+	content.Walk(func(c *kreuzberg.DjotCursor) bool {
+		if inline := c.Inline(); inline != nil {
+			switch inline.ElementType {
+			case kreuzberg.InlineTypeLink:
+				inline.Metadata["href"] = strings.Replace(inline.Metadata["href"], "http://old.", "https://new.", 1)
+			case kreuzberg.InlineTypeImage:
+				c.Delete()
+			}
+		}
+		if block := c.Block(); block != nil && block.BlockType == kreuzberg.BlockTypeHeading {
+			level := *block.Level + 1
+			block.Level = &level
+		}
+		return true
+	}, nil)
+
+	got := kreuzberg.SerializeDjot(content)
+	for _, want := range []string{"## Title", "### Steps", "[notes](https://new.example.com/notes)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "logo.png") {
+		t.Errorf("image should have been stripped:\n%s", got)
+	}
+}
+
+func TestDjotWalkSkipStopAndReplace(t *testing.T) {
+	content := newDjotTestContent()
+
+	var entered []string
+	content.Walk(func(c *kreuzberg.DjotCursor) bool {
+		switch {
+		case c.Block() != nil:
+			entered = append(entered, string(c.Block().BlockType))
+			if c.Block().BlockType == kreuzberg.BlockTypeCodeBlock {
+				c.ReplaceBlock(djotPara(djotText("code removed")...))
+			}
+			return c.Block().BlockType != kreuzberg.BlockTypeOrderedList
+		case c.Footnote() != nil:
+			entered = append(entered, "footnote")
+		}
+		return true
+	}, func(c *kreuzberg.DjotCursor) bool {
+		return c.Block() == nil || c.Block().BlockType != kreuzberg.BlockTypeBlockquote
+	})
+
+	want := []string{"heading", "paragraph", "heading", "ordered_list", "code_block", "blockquote", "paragraph"}
+	if strings.Join(entered, ",") != strings.Join(want, ",") {
+		t.Errorf("entered = %v, want %v", entered, want)
+	}
+	if got := kreuzberg.SerializeDjot(content); !strings.Contains(got, "code removed") || strings.Contains(got, "x := ") {
+		t.Errorf("code block should have been replaced:\n%s", got)
+	}
+	if len(content.Footnotes) != 1 {
+		t.Errorf("footnotes should survive a stopped walk, got %d", len(content.Footnotes))
+	}
+}
+
+func TestDjotWalkFootnotes(t *testing.T) {
+	content := newDjotTestContent()
+	var labels []string
+	content.Walk(func(c *kreuzberg.DjotCursor) bool {
+		if c.Inline() != nil && c.WithinFootnote() != nil {
+			labels = append(labels, c.WithinFootnote().Label+":"+c.Inline().Content)
+		}
+		return true
+	}, nil)
+	if strings.Join(labels, ",") != "1:See ,1:notes" {
+		t.Errorf("footnote inlines = %v", labels)
+	}
+}
+
+func TestSerializeDjotTables(t *testing.T) {
+	content := &kreuzberg.DjotContent{
+		Blocks: []kreuzberg.FormattedBlock{
+			djotPara(djotText("Before")...),
+			{BlockType: kreuzberg.BlockTypeTable, TableIndex: kreuzberg.Uint64Ptr(1)},
+			djotPara(djotText("After")...),
+		},
+		Tables: []kreuzberg.Table{
+			{Cells: [][]string{{"unplaced"}}},
+			{Cells: [][]string{{"A", "B|C"}, {"*1*", "2"}}},
+		},
+	}
+	want := "Before\n\n| A | B\\|C |\n|---|---|\n| \\*1\\* | 2 |\n\nAfter\n\n| unplaced |\n|---|\n"
+	if got := kreuzberg.SerializeDjot(content); got != want {
+		t.Errorf("SerializeDjot() = %q, want %q", got, want)
+	}
+}
+
+func TestSerializeDjotFixtureRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("..", "..", "..", "test_documents", "*", "*.djot"))
+	if err != nil {
+		t.Fatalf("glob fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Skip("no djot fixtures found")
+	}
+
+	for _, path := range fixtures {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			first, err := kreuzberg.ExtractFileSync(path, nil)
+			if err != nil {
+				t.Fatalf("ExtractFileSync() error = %v", err)
+			}
+			if first.DjotContent == nil {
+				t.Fatal("expected djot content for djot fixture")
+			}
+			if source, _ := os.ReadFile(path); bytes.Contains(source, []byte("[^")) && len(first.DjotContent.Footnotes) == 0 {
+				t.Fatal("expected footnotes for a fixture with footnote definitions")
+			}
+			serialized := kreuzberg.SerializeDjot(first.DjotContent)
+
+			second, err := kreuzberg.ExtractBytesSync([]byte(serialized), "text/djot", nil)
+			if err != nil {
+				t.Fatalf("ExtractBytesSync() error = %v", err)
+			}
+			if second.DjotContent == nil {
+				t.Fatal("expected djot content after round trip")
+			}
+			for _, part := range []struct {
+				name          string
+				first, second any
+			}{
+				{"blocks", first.DjotContent.Blocks, second.DjotContent.Blocks},
+				{"tables", first.DjotContent.Tables, second.DjotContent.Tables},
+				{"footnotes", first.DjotContent.Footnotes, second.DjotContent.Footnotes},
+			} {
+				want, _ := json.Marshal(part.first)
+				got, _ := json.Marshal(part.second)
+				if string(got) != string(want) {
+					t.Errorf("round trip changed the %s:\nserialized:\n%s\nwant: %s\ngot:  %s", part.name, serialized, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package kreuzberg
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Default limits applied by ExtractTree when the corresponding TreeOptions field is zero.
+const (
+	DefaultTreeMaxDepth     = 5
+	DefaultTreeMaxNodes     = 1000
+	DefaultTreeMaxTotalSize = 512 << 20
+)
+
+// TreeOptions controls ExtractTree.
+type TreeOptions struct {
+	// Config is used to extract every node. Nil uses the defaults.
+	Config *ExtractionConfig
+	// MaxDepth is the deepest level whose members are unpacked; the root file is at
+	// depth 0. Defaults to DefaultTreeMaxDepth.
+	MaxDepth int
+	// MaxNodes caps the number of nodes in the tree, including the root. Defaults to
+	// DefaultTreeMaxNodes.
+	MaxNodes int
+	// MaxTotalSize caps the total number of bytes unpacked from archives and
+	// attachments, measured after decompression. Defaults to DefaultTreeMaxTotalSize.
+	MaxTotalSize int64
+}
+
+// TreeNodeSource tells how a TreeNode was obtained.
+type TreeNodeSource string
+
+const (
+	TreeNodeFile            TreeNodeSource = "file"
+	TreeNodeArchiveMember   TreeNodeSource = "archive_member"
+	TreeNodeEmailAttachment TreeNodeSource = "email_attachment"
+)
+
+// TreeNode is one document in the tree built by ExtractTree.
+type TreeNode struct {
+	// Name is the member name inside the parent, or the base name of the root file.
+	Name string
+	// Path joins the names from the root down to this node with "/", e.g.
+	// "mail.eml/invoices.zip/2024/march.pdf".
+	Path     string
+	Source   TreeNodeSource
+	MimeType string
+	// Size is the size of the node's data in bytes.
+	Size  int64
+	Depth int
+	// Result is the extraction result, or nil when extraction failed. Archives have
+	// no Result: ZIP, TAR and gzip archives are represented by their members, and 7z
+	// archives are neither unpacked nor extracted, so they are Truncated.
+	Result *ExtractionResult
+	// Err records what went wrong for this node: a failed extraction, a container
+	// that could not be unpacked, or a limit that stopped unpacking. Other nodes are
+	// unaffected.
+	Err error
+	// Truncated is set when a limit, or an archive format ExtractTree cannot unpack,
+	// left some of the node's members out of the tree.
+	Truncated bool
+	Parent    *TreeNode
+	Children  []*TreeNode
+}
+
+// Walk calls fn for n and its descendants in depth-first order. Returning false
+// skips the node's children.
+func (n *TreeNode) Walk(fn func(*TreeNode) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// Find returns the node with the given Path, or nil.
+func (n *TreeNode) Find(nodePath string) *TreeNode {
+	var found *TreeNode
+	n.Walk(func(node *TreeNode) bool {
+		if found != nil {
+			return false
+		}
+		if node.Path == nodePath {
+			found = node
+			return false
+		}
+		return strings.HasPrefix(nodePath, node.Path+"/")
+	})
+	return found
+}
+
+// Failed returns the nodes with a non-nil Err in depth-first order.
+func (n *TreeNode) Failed() []*TreeNode {
+	var failed []*TreeNode
+	n.Walk(func(node *TreeNode) bool {
+		if node.Err != nil {
+			failed = append(failed, node)
+		}
+		return true
+	})
+	return failed
+}
+
+// Count returns the number of nodes in the tree rooted at n.
+func (n *TreeNode) Count() int {
+	count := 0
+	n.Walk(func(*TreeNode) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// ExtractTree extracts the file at path and, recursively, the members of archives
+// (ZIP, TAR and gzip) and the attachments of emails (EML) it contains, returning them
+// as a tree. Archives themselves are not extracted, only their members; 7z archives
+// cannot be unpacked and are returned Truncated, without members.
+//
+// Failures below the root are recorded on the affected node and do not stop the walk.
+// The returned error is non-nil only when the root file cannot be read. Depth, node
+// count and unpacked size are limited by opts to guard against archive bombs; a node
+// whose members were cut off by a limit has Truncated set and Err describing the limit.
+func ExtractTree(filePath string, opts *TreeOptions) (*TreeNode, error) {
+	if filePath == "" {
+		return nil, newValidationErrorWithContext("path is required", nil, ErrorCodeValidation, nil)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, newIOErrorWithContext(fmt.Sprintf("failed to read %s", filePath), err, ErrorCodeIo, nil)
+	}
+
+	b := newTreeBuilder(opts)
+	root := &TreeNode{
+		Name:   filepath.Base(filePath),
+		Source: TreeNodeFile,
+		Size:   int64(len(data)),
+	}
+	root.Path = root.Name
+	root.MimeType, err = DetectMimeTypeFromPath(filePath)
+	if err != nil {
+		root.MimeType, err = detectTreeMimeType(root.Name, "", data)
+	}
+	b.nodes = 1
+	if err != nil {
+		root.Err = err
+		return root, nil
+	}
+	b.extract(root, data)
+	return root, nil
+}
+
+type treeBuilder struct {
+	config    *ExtractionConfig
+	maxDepth  int
+	maxNodes  int
+	remaining int64
+	nodes     int
+}
+
+func newTreeBuilder(opts *TreeOptions) *treeBuilder {
+	var resolved TreeOptions
+	if opts != nil {
+		resolved = *opts
+	}
+	if resolved.MaxDepth <= 0 {
+		resolved.MaxDepth = DefaultTreeMaxDepth
+	}
+	if resolved.MaxNodes <= 0 {
+		resolved.MaxNodes = DefaultTreeMaxNodes
+	}
+	if resolved.MaxTotalSize <= 0 {
+		resolved.MaxTotalSize = DefaultTreeMaxTotalSize
+	}
+	return &treeBuilder{
+		config:    resolved.Config,
+		maxDepth:  resolved.MaxDepth,
+		maxNodes:  resolved.MaxNodes,
+		remaining: resolved.MaxTotalSize,
+	}
+}
+
+// extract extracts node from data and unpacks its members, if it is a container.
+func (b *treeBuilder) extract(node *TreeNode, data []byte) {
+	kind := treeContainerKind(node.MimeType, node.Name)
+	if !kind.archive() {
+		node.Result, node.Err = ExtractBytesSync(data, node.MimeType, b.config)
+	}
+	if kind == containerNone {
+		return
+	}
+	if node.Depth >= b.maxDepth {
+		node.Truncated = true
+		node.Err = errors.Join(node.Err, newValidationErrorWithContext(fmt.Sprintf("members not extracted: maximum depth of %d reached", b.maxDepth), nil, ErrorCodeValidation, nil))
+		return
+	}
+
+	members, err := unpackContainer(kind, node.Name, data, &b.remaining)
+	for _, member := range members {
+		if b.nodes >= b.maxNodes {
+			node.Truncated = true
+			err = errors.Join(newValidationErrorWithContext(fmt.Sprintf("members not extracted: maximum of %d nodes reached", b.maxNodes), nil, ErrorCodeValidation, nil), err)
+			break
+		}
+		b.nodes++
+		child := &TreeNode{
+			Name:   member.name,
+			Path:   node.Path + "/" + member.name,
+			Source: member.source,
+			Size:   int64(len(member.data)),
+			Depth:  node.Depth + 1,
+			Parent: node,
+		}
+		node.Children = append(node.Children, child)
+		if child.MimeType, child.Err = detectTreeMimeType(member.name, member.declaredType, member.data); child.Err != nil {
+			continue
+		}
+		b.extract(child, member.data)
+	}
+	if err != nil {
+		if isTreeSizeLimit(err) || kind == containerUnsupportedArchive {
+			node.Truncated = true
+		}
+		node.Err = errors.Join(node.Err, err)
+	}
+}
+
+// detectTreeMimeType picks the MIME type of a member from the type declared by its
+// container, its content and, as a last resort, its file extension.
+func detectTreeMimeType(name, declared string, data []byte) (string, error) {
+	if declared = normalizeMimeType(declared); declared != "" && declared != "application/octet-stream" {
+		return declared, nil
+	}
+	detected, err := DetectMimeType(data)
+	if err == nil && detected != "" && detected != "application/octet-stream" {
+		return detected, nil
+	}
+	if byExt := normalizeMimeType(mime.TypeByExtension(path.Ext(name))); byExt != "" {
+		return byExt, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", newUnsupportedFormatErrorWithContext(name, fmt.Sprintf("cannot determine MIME type of %s", name), nil, ErrorCodeUnsupportedFormat, nil)
+}
@@ -0,0 +1,359 @@
+package kreuzberg
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string][]byte, order ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip create %s: %v", name, err)
+		}
+		if _, err := w.Write(files[name]); err != nil {
+			t.Fatalf("zip write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildTar(t *testing.T, names []string, contents [][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for i, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(contents[i])), Typeflag: tar.TypeReg, Format: tar.FormatUSTAR}); err != nil {
+			t.Fatalf("tar header: %v", err)
+		}
+		if _, err := tw.Write(contents[i]); err != nil {
+			t.Fatalf("tar write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("tar close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func gzipBytes(t *testing.T, name string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Name = name
+	if _, err := zw.Write(data); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func memberNames(members []treeMember) []string {
+	names := make([]string, len(members))
+	for i, m := range members {
+		names[i] = m.name
+	}
+	return names
+}
+
+func TestUnpackZip(t *testing.T) {
+	data := buildZip(t, map[string][]byte{
+		"docs/":          nil,
+		"docs/a.txt":     []byte("alpha"),
+		"b.txt":          []byte("bravo"),
+		"nested/c.zip":   buildZip(t, map[string][]byte{"c.txt": []byte("charlie")}, "c.txt"),
+		"docs/empty.txt": {},
+	}, "docs/", "docs/a.txt", "b.txt", "nested/c.zip", "docs/empty.txt")
+
+	remaining := int64(1 << 20)
+	members, err := unpackContainer(containerZip, "bundle.zip", data, &remaining)
+	if err != nil {
+		t.Fatalf("unpackContainer() error = %v", err)
+	}
+	if got, want := strings.Join(memberNames(members), ","), "docs/a.txt,b.txt,nested/c.zip,docs/empty.txt"; got != want {
+		t.Fatalf("members = %s, want %s", got, want)
+	}
+	if string(members[0].data) != "alpha" || members[0].source != TreeNodeArchiveMember {
+		t.Errorf("first member = %+v", members[0])
+	}
+	if treeContainerKind("application/zip", members[2].name) != containerZip {
+		t.Error("nested zip should be a container")
+	}
+	used := int64(len("alpha") + len("bravo") + len(members[2].data))
+	if remaining != 1<<20-used {
+		t.Errorf("remaining = %d, want %d", remaining, 1<<20-used)
+	}
+}
+
+func TestUnpackSizeLimitStopsBombs(t *testing.T) {
+	bomb := buildZip(t, map[string][]byte{
+		"small.txt": []byte("ok"),
+		"zeros.bin": make([]byte, 4<<20),
+	}, "small.txt", "zeros.bin")
+	if len(bomb) > 64<<10 {
+		t.Fatalf("test archive unexpectedly large: %d bytes", len(bomb))
+	}
+
+	remaining := int64(1024)
+	members, err := unpackContainer(containerZip, "bomb.zip", bomb, &remaining)
+	if !isTreeSizeLimit(err) {
+		t.Fatalf("error = %v, want size limit", err)
+	}
+	if len(members) != 1 || members[0].name != "small.txt" {
+		t.Errorf("members = %v, want only small.txt", memberNames(members))
+	}
+
+	// A gzip stream is limited while inflating, without relying on declared sizes.
+	remaining = 1024
+	_, err = unpackContainer(containerGzip, "zeros.bin.gz", gzipBytes(t, "zeros.bin", make([]byte, 4<<20)), &remaining)
+	if !isTreeSizeLimit(err) {
+		t.Fatalf("gzip error = %v, want size limit", err)
+	}
+}
+
+func TestUnpackTarAndGzip(t *testing.T) {
+	tarball := buildTar(t, []string{"a.txt", "dir/b.txt"}, [][]byte{[]byte("alpha"), []byte("bravo")})
+
+	remaining := int64(1 << 20)
+	members, err := unpackContainer(containerTar, "x.tar", tarball, &remaining)
+	if err != nil {
+		t.Fatalf("tar error = %v", err)
+	}
+	if got := strings.Join(memberNames(members), ","); got != "a.txt,dir/b.txt" {
+		t.Errorf("tar members = %s", got)
+	}
+
+	members, err = unpackContainer(containerGzip, "x.tar.gz", gzipBytes(t, "", tarball), &remaining)
+	if err != nil {
+		t.Fatalf("tar.gz error = %v", err)
+	}
+	if got := strings.Join(memberNames(members), ","); got != "a.txt,dir/b.txt" {
+		t.Errorf("tar.gz members = %s", got)
+	}
+
+	members, err = unpackContainer(containerGzip, "notes.txt.gz", gzipBytes(t, "", []byte("hello")), &remaining)
+	if err != nil {
+		t.Fatalf("gz error = %v", err)
+	}
+	if len(members) != 1 || members[0].name != "notes.txt" || string(members[0].data) != "hello" {
+		t.Errorf("gz members = %+v", members)
+	}
+}
+
+const testEmail = "From: a@example.com\r\n" +
+	"To: b@example.com\r\n" +
+	"Subject: Files\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"See attached.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html\r\n" +
+	"\r\n" +
+	"<p>See attached.</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/zip\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"Content-Disposition: attachment; filename=\"=?UTF-8?Q?Rechnungen_M=C3=A4rz.zip?=\"\r\n" +
+	"\r\n" +
+	"%s\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"C:\\\\temp\\\\notes.txt\"\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"caf=C3=A9\r\n" +
+	"--outer\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"Subject: Forwarded\r\n" +
+	"\r\n" +
+	"Inner body\r\n" +
+	"--outer--\r\n"
+
+func TestUnpackEmailAttachments(t *testing.T) {
+	zipData := buildZip(t, map[string][]byte{"a.txt": []byte("alpha")}, "a.txt")
+	encoded := base64.StdEncoding.EncodeToString(zipData)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded)
+	message := strings.Replace(testEmail, "%s", wrapped.String(), 1)
+
+	remaining := int64(1 << 20)
+	members, err := unpackContainer(containerEmail, "mail.eml", []byte(message), &remaining)
+	if err != nil {
+		t.Fatalf("unpackContainer() error = %v", err)
+	}
+	if got, want := strings.Join(memberNames(members), "|"), "Rechnungen März.zip|notes.txt|attachment-3.eml"; got != want {
+		t.Fatalf("members = %s, want %s", got, want)
+	}
+	if !bytes.Equal(members[0].data, zipData) || members[0].declaredType != "application/zip" {
+		t.Errorf("zip attachment not decoded: %d bytes, type %s", len(members[0].data), members[0].declaredType)
+	}
+	if string(members[1].data) != "café" {
+		t.Errorf("quoted-printable attachment = %q", members[1].data)
+	}
+	if members[2].declaredType != "message/rfc822" || !strings.Contains(string(members[2].data), "Inner body") {
+		t.Errorf("attached message = %+v", members[2])
+	}
+	for _, m := range members {
+		if m.source != TreeNodeEmailAttachment {
+			t.Errorf("%s source = %s", m.name, m.source)
+		}
+	}
+}
+
+func TestTreeContainerKind(t *testing.T) {
+	cases := map[string]containerKind{
+		"application/zip": containerZip,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": containerNone,
+		"application/x-tar":           containerTar,
+		"application/gzip":            containerGzip,
+		"message/rfc822":              containerEmail,
+		"application/x-7z-compressed": containerUnsupportedArchive,
+		"application/pdf":             containerNone,
+	}
+	for mimeType, want := range cases {
+		if got := treeContainerKind(mimeType, "file"); got != want {
+			t.Errorf("treeContainerKind(%s) = %d, want %d", mimeType, got, want)
+		}
+	}
+	if treeContainerKind("text/plain", "forwarded.EML") != containerEmail {
+		t.Error("an .eml name should be an email container")
+	}
+}
+
+func TestTreeNodeNavigation(t *testing.T) {
+	root := &TreeNode{Name: "mail.eml", Path: "mail.eml"}
+	archive := &TreeNode{Name: "a.zip", Path: "mail.eml/a.zip", Parent: root}
+	doc := &TreeNode{Name: "x/doc.pdf", Path: "mail.eml/a.zip/x/doc.pdf", Parent: archive, Err: newParsingErrorWithContext("bad pdf", nil, ErrorCodeParsing, nil)}
+	note := &TreeNode{Name: "note.txt", Path: "mail.eml/note.txt", Parent: root}
+	archive.Children = []*TreeNode{doc}
+	root.Children = []*TreeNode{archive, note}
+
+	if root.Count() != 4 {
+		t.Errorf("Count() = %d, want 4", root.Count())
+	}
+	if got := root.Find("mail.eml/a.zip/x/doc.pdf"); got != doc {
+		t.Errorf("Find() = %v, want doc", got)
+	}
+	if root.Find("mail.eml/missing") != nil {
+		t.Error("Find() of a missing path should be nil")
+	}
+	if failed := root.Failed(); len(failed) != 1 || failed[0] != doc {
+		t.Errorf("Failed() = %v", failed)
+	}
+}
+
+func TestExtractTree(t *testing.T) {
+	inner := buildZip(t, map[string][]byte{"inner.txt": []byte("inner text")}, "inner.txt")
+	outer := buildZip(t, map[string][]byte{
+		"readme.txt": []byte("outer text"),
+		"inner.zip":  inner,
+	}, "readme.txt", "inner.zip")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "outer.zip")
+	if err := os.WriteFile(path, outer, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	root, err := ExtractTree(path, nil)
+	if err != nil {
+		t.Fatalf("ExtractTree() error = %v", err)
+	}
+	if root.Count() != 4 {
+		t.Fatalf("Count() = %d, want 4", root.Count())
+	}
+	leaf := root.Find("outer.zip/inner.zip/inner.txt")
+	if leaf == nil || leaf.Result == nil || !strings.Contains(leaf.Result.Content, "inner text") {
+		t.Fatalf("inner.txt node = %+v", leaf)
+	}
+	if leaf.Depth != 2 || leaf.Parent.Name != "inner.zip" {
+		t.Errorf("inner.txt depth %d, parent %s", leaf.Depth, leaf.Parent.Name)
+	}
+
+	limited, err := ExtractTree(path, &TreeOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("ExtractTree() error = %v", err)
+	}
+	nested := limited.Find("outer.zip/inner.zip")
+	if nested == nil || !nested.Truncated || len(nested.Children) != 0 || nested.Err == nil {
+		t.Errorf("inner.zip should be truncated at depth 1: %+v", nested)
+	}
+
+	counted, err := ExtractTree(path, &TreeOptions{MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("ExtractTree() error = %v", err)
+	}
+	if counted.Count() != 2 || !counted.Truncated {
+		t.Errorf("node limit: Count() = %d, Truncated = %v", counted.Count(), counted.Truncated)
+	}
+}
+
+func TestExtractTreeCompressedBomb(t *testing.T) {
+	// 32 MiB of one letter compresses to a few dozen KiB.
+	text := bytes.Repeat([]byte("a"), 32<<20)
+	dir := t.TempDir()
+	for name, data := range map[string][]byte{
+		"bomb.zip":    buildZip(t, map[string][]byte{"bomb.txt": text}, "bomb.txt"),
+		"bomb.txt.gz": gzipBytes(t, "bomb.txt", text),
+	} {
+		if len(data) > 1<<20 {
+			t.Fatalf("%s is %d bytes, want a highly compressed archive", name, len(data))
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+
+		root, err := ExtractTree(path, &TreeOptions{MaxTotalSize: 1 << 20})
+		if err != nil {
+			t.Fatalf("ExtractTree(%s) error = %v", name, err)
+		}
+		// The archive is not handed to the native extractors, so the only error is the
+		// size limit.
+		if root.Result != nil || root.Err == nil || root.Err.Error() != (&treeSizeLimitError{}).Error() {
+			t.Errorf("%s: Result = %v, Err = %v; want only the size limit", name, root.Result, root.Err)
+		}
+		if !root.Truncated || len(root.Children) != 0 {
+			t.Errorf("%s: Truncated = %v with %d children", name, root.Truncated, len(root.Children))
+		}
+	}
+}
+
+func TestExtractTreeSevenZipIsTruncated(t *testing.T) {
+	// A 7z signature is enough: the archive is neither unpacked nor extracted.
+	data := append([]byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}, make([]byte, 26)...)
+	node := &TreeNode{Name: "docs.7z", Path: "docs.7z", MimeType: "application/x-7z-compressed", Size: int64(len(data))}
+	newTreeBuilder(nil).extract(node, data)
+	if node.Result != nil || node.Err == nil {
+		t.Errorf("Result = %v, Err = %v; want no result and an error", node.Result, node.Err)
+	}
+	if !node.Truncated || len(node.Children) != 0 {
+		t.Errorf("Truncated = %v with %d children", node.Truncated, len(node.Children))
+	}
+}
@@ -0,0 +1,295 @@
+package kreuzberg
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"path"
+	"strings"
+)
+
+type containerKind int
+
+const (
+	containerNone containerKind = iota
+	containerZip
+	containerTar
+	containerGzip
+	containerEmail
+	containerUnsupported
+	containerUnsupportedArchive
+)
+
+// archive reports whether kind is an archive. Archives are not extracted natively:
+// the native extractors read every member into memory without a size limit, so only
+// the members unpacked within the ExtractTree limits are extracted. Members of
+// archives that cannot be unpacked, such as 7z, are not extracted at all.
+func (kind containerKind) archive() bool {
+	switch kind {
+	case containerZip, containerTar, containerGzip, containerUnsupportedArchive:
+		return true
+	}
+	return false
+}
+
+// treeContainerKind tells whether documents of mimeType have members ExtractTree can
+// unpack. Formats built on ZIP, such as DOCX or EPUB, are documents, not containers.
+func treeContainerKind(mimeType, name string) containerKind {
+	switch normalizeMimeType(mimeType) {
+	case "application/zip", "application/x-zip-compressed":
+		return containerZip
+	case "application/x-tar", "application/tar", "application/x-gtar", "application/x-ustar":
+		return containerTar
+	case "application/gzip", "application/x-gzip":
+		return containerGzip
+	case "message/rfc822":
+		return containerEmail
+	case "application/x-7z-compressed":
+		return containerUnsupportedArchive
+	case "application/vnd.ms-outlook":
+		return containerUnsupported
+	}
+	if strings.EqualFold(path.Ext(name), ".eml") {
+		return containerEmail
+	}
+	return containerNone
+}
+
+type treeMember struct {
+	name         string
+	declaredType string
+	source       TreeNodeSource
+	data         []byte
+}
+
+// treeSizeLimitError reports that unpacking stopped at the total size limit.
+type treeSizeLimitError struct{}
+
+func (e *treeSizeLimitError) Error() string {
+	return "members not extracted: maximum total unpacked size reached"
+}
+
+func isTreeSizeLimit(err error) bool {
+	var limitErr *treeSizeLimitError
+	return errors.As(err, &limitErr)
+}
+
+// readTreeMember reads r to the end, charging the bytes read against remaining. It
+// never reads more than one byte past the budget, so compressed bombs are not inflated.
+func readTreeMember(r io.Reader, remaining *int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, *remaining+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > *remaining {
+		return nil, &treeSizeLimitError{}
+	}
+	*remaining -= int64(len(data))
+	return data, nil
+}
+
+// unpackContainer returns the members of a container. Members read before an error are
+// returned along with it.
+func unpackContainer(kind containerKind, name string, data []byte, remaining *int64) ([]treeMember, error) {
+	var (
+		members []treeMember
+		err     error
+	)
+	switch kind {
+	case containerZip:
+		members, err = unpackZip(data, remaining)
+	case containerTar:
+		members, err = unpackTar(bytes.NewReader(data), remaining)
+	case containerGzip:
+		members, err = unpackGzip(name, data, remaining)
+	case containerEmail:
+		members, err = unpackEmail(data, remaining)
+	default:
+		return nil, newUnsupportedFormatErrorWithContext(name, fmt.Sprintf("members of %s cannot be unpacked", name), nil, ErrorCodeUnsupportedFormat, nil)
+	}
+	if err == nil || isTreeSizeLimit(err) {
+		return members, err
+	}
+	return members, newParsingErrorWithContext(fmt.Sprintf("failed to unpack %s", name), err, ErrorCodeParsing, nil)
+}
+
+func unpackZip(data []byte, remaining *int64) ([]treeMember, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var members []treeMember
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if file.UncompressedSize64 > uint64(*remaining) {
+			return members, &treeSizeLimitError{}
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return members, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		content, err := readTreeMember(rc, remaining)
+		rc.Close()
+		if err != nil {
+			return members, err
+		}
+		members = append(members, treeMember{name: file.Name, source: TreeNodeArchiveMember, data: content})
+	}
+	return members, nil
+}
+
+func unpackTar(r io.Reader, remaining *int64) ([]treeMember, error) {
+	tr := tar.NewReader(r)
+	var members []treeMember
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return members, nil
+		}
+		if err != nil {
+			return members, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if header.Size > *remaining {
+			return members, &treeSizeLimitError{}
+		}
+		content, err := readTreeMember(tr, remaining)
+		if err != nil {
+			return members, err
+		}
+		members = append(members, treeMember{name: header.Name, source: TreeNodeArchiveMember, data: content})
+	}
+}
+
+// unpackGzip unpacks a compressed tarball, or else returns the decompressed file as
+// the only member.
+func unpackGzip(name string, data []byte, remaining *int64) ([]treeMember, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	content, err := readTreeMember(zr, remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTarArchive(content) {
+		// The tarball's bytes were already charged; its members are not charged again.
+		unlimited := int64(len(content))
+		return unpackTar(bytes.NewReader(content), &unlimited)
+	}
+
+	inner := path.Base(zr.Name)
+	if zr.Name == "" {
+		inner = strings.TrimSuffix(path.Base(name), path.Ext(name))
+	}
+	return []treeMember{{name: inner, source: TreeNodeArchiveMember, data: content}}, nil
+}
+
+func isTarArchive(data []byte) bool {
+	return len(data) >= 262 && string(data[257:262]) == "ustar"
+}
+
+// unpackEmail returns the attachments of an RFC 822 message, including attached
+// messages, in the order they appear.
+func unpackEmail(data []byte, remaining *int64) ([]treeMember, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var members []treeMember
+	err = walkEmailPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body, remaining, true, &members)
+	return members, err
+}
+
+func walkEmailPart(contentType, encoding, disposition string, body io.Reader, remaining *int64, top bool, members *[]treeMember) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			err = walkEmailPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part.Header.Get("Content-Disposition"), part, remaining, false, members)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if top {
+		return nil
+	}
+
+	fileName := emailPartFileName(disposition, params)
+	dispositionType, _, _ := mime.ParseMediaType(disposition)
+	isAttachment := dispositionType == "attachment" || fileName != "" || mediaType == "message/rfc822"
+	if !isAttachment {
+		return nil
+	}
+
+	content, err := readTreeMember(decodeTransferEncoding(body, encoding), remaining)
+	if err != nil {
+		return err
+	}
+	if fileName == "" {
+		fileName = fmt.Sprintf("attachment-%d", len(*members)+1)
+		if mediaType == "message/rfc822" {
+			fileName += ".eml"
+		}
+	}
+	*members = append(*members, treeMember{name: fileName, declaredType: mediaType, source: TreeNodeEmailAttachment, data: content})
+	return nil
+}
+
+// emailPartFileName returns the decoded file name from the Content-Disposition
+// filename or the Content-Type name parameter.
+func emailPartFileName(disposition string, typeParams map[string]string) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(disposition); err == nil {
+		name = params["filename"]
+	}
+	if name == "" {
+		name = typeParams["name"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	// Keep only the base name of names such as "C:\\Users\\me\\report.pdf".
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexAny(name, "/\\"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func decodeTransferEncoding(r io.Reader, encoding string) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
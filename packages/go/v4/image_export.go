@@ -0,0 +1,278 @@
+package kreuzberg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultImageDocumentName is the document name used in exported file names when
+// ImageExportOptions.DocumentName is empty.
+const DefaultImageDocumentName = "document"
+
+// ImageSink receives the files written by ExportImages.
+type ImageSink interface {
+	WriteFile(name string, data []byte) error
+}
+
+// ImageSinkFunc adapts a function to an ImageSink.
+type ImageSinkFunc func(name string, data []byte) error
+
+// WriteFile calls f(name, data).
+func (f ImageSinkFunc) WriteFile(name string, data []byte) error {
+	return f(name, data)
+}
+
+type dirImageSink struct {
+	dir string
+}
+
+// NewDirImageSink returns an ImageSink that writes files into dir, creating it if
+// needed.
+func NewDirImageSink(dir string) ImageSink {
+	return &dirImageSink{dir: dir}
+}
+
+func (s *dirImageSink) WriteFile(name string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0o644)
+}
+
+// ImageExportOptions controls ExportImages.
+type ImageExportOptions struct {
+	// DocumentName prefixes every file name. It is reduced to a safe file name
+	// component and defaults to DefaultImageDocumentName.
+	DocumentName string
+	// SkipMasks leaves out images with IsMask set.
+	SkipMasks bool
+	// KeepDuplicates writes every image, even when an identical one was already written.
+	KeepDuplicates bool
+	// NoManifest skips writing the manifest file; it is still returned.
+	NoManifest bool
+}
+
+// ImageOccurrence locates one occurrence of an exported image in the document.
+type ImageOccurrence struct {
+	PageNumber *uint64 `json:"page_number,omitempty"`
+	ImageIndex uint64  `json:"image_index"`
+}
+
+// ImageManifestEntry describes one exported file.
+type ImageManifestEntry struct {
+	File   string  `json:"file"`
+	SHA256 string  `json:"sha256"`
+	Format string  `json:"format"`
+	Size   int     `json:"size"`
+	Width  *uint32 `json:"width,omitempty"`
+	Height *uint32 `json:"height,omitempty"`
+	IsMask bool    `json:"is_mask"`
+	// Occurrences lists every place the image appears; duplicates share one file.
+	Occurrences []ImageOccurrence `json:"occurrences"`
+}
+
+// ImageManifest describes the files written by ExportImages. It is written next to the
+// images as "<document>-images.json".
+type ImageManifest struct {
+	Document     string               `json:"document"`
+	Images       []ImageManifestEntry `json:"images"`
+	Duplicates   int                  `json:"duplicates"`
+	SkippedMasks int                  `json:"skipped_masks"`
+	SkippedEmpty int                  `json:"skipped_empty"`
+}
+
+// ManifestFileName returns the name under which the manifest is written.
+func (m *ImageManifest) ManifestFileName() string {
+	return m.Document + "-images.json"
+}
+
+// ExportImages writes images to sink under deterministic names of the form
+// "<document>-p<page>-img<index>.<ext>" ("<document>-img<index>.<ext>" for images
+// without a page), and returns the manifest.
+//
+// Images with identical data are written once, with every occurrence listed in the
+// manifest, unless KeepDuplicates is set. Missing Width and Height are filled in, on
+// the images themselves and in the manifest, by decoding the image header with the
+// standard image package (PNG, JPEG and GIF). Images without data are skipped.
+func ExportImages(images []ExtractedImage, sink ImageSink, opts *ImageExportOptions) (*ImageManifest, error) {
+	if sink == nil {
+		return nil, newValidationErrorWithContext("image sink must not be nil", nil, ErrorCodeValidation, nil)
+	}
+	var resolved ImageExportOptions
+	if opts != nil {
+		resolved = *opts
+	}
+	manifest := &ImageManifest{Document: imageFileComponent(resolved.DocumentName), Images: []ImageManifestEntry{}}
+
+	seen := map[string]int{}
+	for i := range images {
+		img := &images[i]
+		if len(img.Data) == 0 {
+			manifest.SkippedEmpty++
+			continue
+		}
+		if img.IsMask && resolved.SkipMasks {
+			manifest.SkippedMasks++
+			continue
+		}
+		fillImageDimensions(img)
+
+		sum := sha256.Sum256(img.Data)
+		hash := hex.EncodeToString(sum[:])
+		occurrence := ImageOccurrence{PageNumber: img.PageNumber, ImageIndex: img.ImageIndex}
+		if idx, ok := seen[hash]; ok && !resolved.KeepDuplicates {
+			manifest.Images[idx].Occurrences = append(manifest.Images[idx].Occurrences, occurrence)
+			manifest.Duplicates++
+			continue
+		}
+
+		name := manifest.Document
+		if img.PageNumber != nil {
+			name += fmt.Sprintf("-p%d", *img.PageNumber)
+		}
+		name += fmt.Sprintf("-img%d.%s", img.ImageIndex, ImageExtension(img))
+		if err := sink.WriteFile(name, img.Data); err != nil {
+			return nil, newIOErrorWithContext(fmt.Sprintf("failed to write image %s", name), err, ErrorCodeIo, nil)
+		}
+		seen[hash] = len(manifest.Images)
+		manifest.Images = append(manifest.Images, ImageManifestEntry{
+			File:        name,
+			SHA256:      hash,
+			Format:      img.Format,
+			Size:        len(img.Data),
+			Width:       img.Width,
+			Height:      img.Height,
+			IsMask:      img.IsMask,
+			Occurrences: []ImageOccurrence{occurrence},
+		})
+	}
+
+	if !resolved.NoManifest {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, newSerializationErrorWithContext("failed to encode image manifest", err, ErrorCodeValidation, nil)
+		}
+		if err := sink.WriteFile(manifest.ManifestFileName(), data); err != nil {
+			return nil, newIOErrorWithContext("failed to write image manifest", err, ErrorCodeIo, nil)
+		}
+	}
+	return manifest, nil
+}
+
+// ExportImages exports the images of the result with ExportImages: Images, followed by
+// the images of each page. Images present in both places are written once.
+func (r *ExtractionResult) ExportImages(sink ImageSink, opts *ImageExportOptions) (*ImageManifest, error) {
+	var images []ExtractedImage
+	if r != nil {
+		images = append(images, r.Images...)
+		for _, page := range r.Pages {
+			for _, img := range page.Images {
+				if img.PageNumber == nil {
+					pageNumber := page.PageNumber
+					img.PageNumber = &pageNumber
+				}
+				images = append(images, img)
+			}
+		}
+	}
+	manifest, err := ExportImages(images, sink, opts)
+	if err != nil || r == nil {
+		return manifest, err
+	}
+	// Copy filled-in dimensions back to the result.
+	n := copy(r.Images, images)
+	for i := range r.Pages {
+		for j := range r.Pages[i].Images {
+			r.Pages[i].Images[j].Width, r.Pages[i].Images[j].Height = images[n].Width, images[n].Height
+			n++
+		}
+	}
+	return manifest, nil
+}
+
+// ImageExtension returns the file extension (without a dot) for img, derived from
+// Format or, when that is not recognized, from the data itself. It falls back to "bin".
+func ImageExtension(img *ExtractedImage) string {
+	format := strings.ToLower(strings.TrimSpace(img.Format))
+	format = strings.TrimPrefix(format, "image/")
+	format = strings.TrimPrefix(format, ".")
+	switch format {
+	case "jpeg", "jpg", "dct", "dctdecode":
+		return "jpg"
+	case "png", "gif", "bmp", "webp", "svg", "jp2", "jpx":
+		return format
+	case "svg+xml":
+		return "svg"
+	case "tif", "tiff":
+		return "tiff"
+	case "jpeg2000", "jpxdecode":
+		return "jp2"
+	case "x-icon", "vnd.microsoft.icon", "ico":
+		return "ico"
+	}
+	switch http.DetectContentType(img.Data) {
+	case "image/jpeg":
+		return "jpg"
+	case "image/png":
+		return "png"
+	case "image/gif":
+		return "gif"
+	case "image/bmp":
+		return "bmp"
+	case "image/webp":
+		return "webp"
+	case "image/x-icon":
+		return "ico"
+	}
+	if bytes.HasPrefix(img.Data, []byte("II*\x00")) || bytes.HasPrefix(img.Data, []byte("MM\x00*")) {
+		return "tiff"
+	}
+	return "bin"
+}
+
+// fillImageDimensions sets missing Width and Height from the image header.
+func fillImageDimensions(img *ExtractedImage) {
+	if img.Width != nil && img.Height != nil {
+		return
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(img.Data))
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return
+	}
+	width, height := uint32(cfg.Width), uint32(cfg.Height)
+	if img.Width == nil {
+		img.Width = &width
+	}
+	if img.Height == nil {
+		img.Height = &height
+	}
+}
+
+// imageFileComponent reduces name to a string safe to use in a file name.
+func imageFileComponent(name string) string {
+	name = strings.TrimSuffix(filepath.Base(strings.TrimSpace(name)), filepath.Ext(name))
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	cleaned = strings.Trim(cleaned, "._")
+	if cleaned == "" {
+		return DefaultImageDocumentName
+	}
+	return cleaned
+}
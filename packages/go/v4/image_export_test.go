@@ -0,0 +1,106 @@
+package kreuzberg_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportImagesDedupesAndFillsDimensions(t *testing.T) {
+	logo := testPNG(t, 4, 3)
+	photo := testPNG(t, 8, 2)
+	images := []kreuzberg.ExtractedImage{
+		{Data: logo, Format: "PNG", ImageIndex: 0, PageNumber: u64(1)},
+		{Data: photo, Format: "", ImageIndex: 1, PageNumber: u64(1)},
+		{Data: logo, Format: "PNG", ImageIndex: 2, PageNumber: u64(2)},
+		{Data: []byte{1, 2, 3}, Format: "jpeg", ImageIndex: 3, IsMask: true},
+		{Format: "PNG", ImageIndex: 4},
+	}
+
+	written := map[string][]byte{}
+	sink := kreuzberg.ImageSinkFunc(func(name string, data []byte) error {
+		written[name] = data
+		return nil
+	})
+	manifest, err := kreuzberg.ExportImages(images, sink, &kreuzberg.ImageExportOptions{DocumentName: "reports/Q1 report.pdf", SkipMasks: true})
+	if err != nil {
+		t.Fatalf("ExportImages() error = %v", err)
+	}
+
+	var names []string
+	for name := range written {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"Q1_report-images.json", "Q1_report-p1-img0.png", "Q1_report-p1-img1.png"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("written files = %v, want %v", names, want)
+	}
+	if manifest.Duplicates != 1 || manifest.SkippedMasks != 1 || manifest.SkippedEmpty != 1 || len(manifest.Images) != 2 {
+		t.Errorf("manifest = %+v", manifest)
+	}
+	if occ := manifest.Images[0].Occurrences; len(occ) != 2 || *occ[1].PageNumber != 2 || occ[1].ImageIndex != 2 {
+		t.Errorf("logo occurrences = %+v", occ)
+	}
+	if images[1].Width == nil || *images[1].Width != 8 || *manifest.Images[1].Height != 2 {
+		t.Errorf("dimensions not filled: %+v", manifest.Images[1])
+	}
+
+	var decoded kreuzberg.ImageManifest
+	if err := json.Unmarshal(written["Q1_report-images.json"], &decoded); err != nil || decoded.Images[0].SHA256 != manifest.Images[0].SHA256 {
+		t.Errorf("manifest JSON = %s (%v)", written["Q1_report-images.json"], err)
+	}
+}
+
+func TestExportImagesToDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	result := &kreuzberg.ExtractionResult{Pages: []kreuzberg.PageContent{
+		{PageNumber: 3, Images: []kreuzberg.ExtractedImage{{Data: testPNG(t, 2, 2), Format: "png", ImageIndex: 5}}},
+	}}
+	manifest, err := result.ExportImages(kreuzberg.NewDirImageSink(dir), &kreuzberg.ImageExportOptions{NoManifest: true})
+	if err != nil {
+		t.Fatalf("ExportImages() error = %v", err)
+	}
+	if len(manifest.Images) != 1 || manifest.Images[0].File != "document-p3-img5.png" {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "document-p3-img5.png")); err != nil {
+		t.Errorf("image not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifest.ManifestFileName())); !os.IsNotExist(err) {
+		t.Errorf("manifest should not be written, stat error = %v", err)
+	}
+	if w := result.Pages[0].Images[0].Width; w == nil || *w != 2 {
+		t.Errorf("page image width = %v", w)
+	}
+}
+
+func TestImageExtension(t *testing.T) {
+	cases := map[string]string{"JPEG": "jpg", "image/png": "png", "TIFF": "tiff", "jpx": "jpx", "": "png"}
+	for format, want := range cases {
+		img := &kreuzberg.ExtractedImage{Format: format, Data: testPNG(t, 1, 1)}
+		if got := kreuzberg.ImageExtension(img); got != want {
+			t.Errorf("ImageExtension(%q) = %q, want %q", format, got, want)
+		}
+	}
+	if got := kreuzberg.ImageExtension(&kreuzberg.ExtractedImage{Format: "weird", Data: []byte("???")}); got != "bin" {
+		t.Errorf("unknown format = %q", got)
+	}
+}
@@ -0,0 +1,277 @@
+package kreuzberg
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MinTextLayerCharsPerPage is the number of non-whitespace characters a page needs
+// for Inspect to count it as having a text layer. It matches the threshold below which
+// the native PDF extractor falls back to OCR.
+const MinTextLayerCharsPerPage = 32
+
+// InspectSamplePages is the number of pages, spread over the document, whose text
+// Inspect extracts from longer PDFs.
+const InspectSamplePages = 8
+
+// TextLayer estimates whether a document carries extractable text.
+type TextLayer string
+
+const (
+	// TextLayerPresent means every page has text.
+	TextLayerPresent TextLayer = "present"
+	// TextLayerPartial means some pages have text and others, such as scans, do not.
+	TextLayerPartial TextLayer = "partial"
+	// TextLayerAbsent means no page has text; the document is image-only.
+	TextLayerAbsent TextLayer = "absent"
+	// TextLayerUnknown means the document could not be read, e.g. because it is
+	// encrypted.
+	TextLayerUnknown TextLayer = "unknown"
+)
+
+// DocumentInspection summarises a document for scheduling decisions.
+type DocumentInspection struct {
+	MimeType string
+	// Size is the size of the document in bytes.
+	Size int64
+	// PageCount is the number of pages, slides or sheets, or 0 when unknown.
+	PageCount int
+	// Pages is the page structure reported by the extractor, if any.
+	Pages *PageStructure
+	// Encrypted is set for password-protected documents, including PDFs that open
+	// without a password but carry an encryption dictionary.
+	Encrypted bool
+	// Pdf holds the PDF metadata of PDF documents.
+	Pdf *PdfMetadata
+	// TextLayer estimates whether the document has extractable text.
+	TextLayer TextLayer
+	// TextPages counts the pages with at least MinTextLayerCharsPerPage non-whitespace
+	// characters. It is an estimate when SampledPages is set.
+	TextPages int
+	// TextChars counts the non-whitespace characters of the text layer. It is an
+	// estimate when SampledPages is set.
+	TextChars int
+	// SampledPages is the number of pages whose text was examined when only a sample
+	// of the pages was extracted, or 0 when every page was.
+	SampledPages int
+	// NeedsOCR is set for images and for PDFs with pages lacking a text layer, whose
+	// content can only be extracted with OCR.
+	NeedsOCR bool
+}
+
+// Inspect returns a DocumentInspection of the file at path without running OCR,
+// image extraction or post-processing.
+//
+// A PDF is opened once to count its pages. Its metadata and the text of at most
+// InspectSamplePages pages spread over the document are then extracted, so PageCount,
+// Encrypted and Pdf are exact while TextPages and TextChars are extrapolated from the
+// sample. Other formats are extracted whole, so inspecting them costs about as much as
+// extracting them without OCR. Raster images are not read at all.
+//
+// An encrypted document is not an error: the inspection reports Encrypted with an
+// unknown text layer.
+func Inspect(path string) (*DocumentInspection, error) {
+	if path == "" {
+		return nil, newValidationErrorWithContext("path is required", nil, ErrorCodeValidation, nil)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, newIOErrorWithContext(fmt.Sprintf("failed to stat %s", path), err, ErrorCodeIo, nil)
+	}
+	mimeType, err := DetectMimeTypeFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+	pageCount := func() (int, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, newIOErrorWithContext(fmt.Sprintf("failed to read %s", path), err, ErrorCodeIo, nil)
+		}
+		return countPDFPages(data)
+	}
+	return inspect(mimeType, info.Size(), pageCount, func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		return ExtractFileSync(path, cfg)
+	})
+}
+
+// InspectBytes is Inspect for in-memory documents.
+func InspectBytes(data []byte) (*DocumentInspection, error) {
+	if len(data) == 0 {
+		return nil, newValidationErrorWithContext("data cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+	mimeType, err := DetectMimeType(data)
+	if err != nil {
+		return nil, err
+	}
+	pageCount := func() (int, error) { return countPDFPages(data) }
+	return inspect(mimeType, int64(len(data)), pageCount, func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		return ExtractBytesSync(data, mimeType, cfg)
+	})
+}
+
+// inspectionConfig asks for page-separated text and metadata and nothing else.
+func inspectionConfig() *ExtractionConfig {
+	return &ExtractionConfig{
+		UseCache:                BoolPtr(false),
+		EnableQualityProcessing: BoolPtr(false),
+		ForceOCR:                BoolPtr(false),
+		Images:                  &ImageExtractionConfig{ExtractImages: BoolPtr(false)},
+		PdfOptions: &PdfConfig{
+			ExtractImages:   BoolPtr(false),
+			ExtractMetadata: BoolPtr(true),
+		},
+		Pages: &PageConfig{ExtractPages: BoolPtr(true)},
+	}
+}
+
+// inspectionSampleRanges returns PageConfig.Ranges selecting InspectSamplePages pages
+// spread evenly over a document of total pages, always including the first and last
+// page, or "" when the document is short enough to be extracted whole.
+func inspectionSampleRanges(total int) string {
+	if total <= InspectSamplePages {
+		return ""
+	}
+	pages := make([]string, InspectSamplePages)
+	for i := range pages {
+		pages[i] = strconv.Itoa(1 + i*(total-1)/(InspectSamplePages-1))
+	}
+	return strings.Join(pages, ",")
+}
+
+// inspect inspects a document of mimeType. pageCount counts the pages of a PDF
+// without extracting it.
+func inspect(mimeType string, size int64, pageCount func() (int, error), extract func(*ExtractionConfig) (*ExtractionResult, error)) (*DocumentInspection, error) {
+	inspection := &DocumentInspection{MimeType: mimeType, Size: size}
+	normalized := normalizeMimeType(mimeType)
+
+	// Raster images have no text layer; extracting them without OCR would tell
+	// nothing more.
+	if strings.HasPrefix(normalized, "image/") && normalized != "image/svg+xml" {
+		inspection.PageCount = 1
+		inspection.TextLayer = TextLayerAbsent
+		inspection.NeedsOCR = true
+		return inspection, nil
+	}
+
+	config := inspectionConfig()
+	if normalized == "application/pdf" && pageCount != nil {
+		total, err := pageCount()
+		if err != nil {
+			if IsEncrypted(err) {
+				inspection.Encrypted = true
+				inspection.TextLayer = TextLayerUnknown
+				return inspection, nil
+			}
+			return nil, err
+		}
+		if ranges := inspectionSampleRanges(total); ranges != "" {
+			config.Pages.Ranges = &ranges
+		}
+	}
+
+	result, err := extract(config)
+	if err != nil {
+		if IsEncrypted(err) {
+			inspection.Encrypted = true
+			inspection.TextLayer = TextLayerUnknown
+			return inspection, nil
+		}
+		return nil, err
+	}
+	inspection.fromResult(result)
+	return inspection, nil
+}
+
+func (d *DocumentInspection) fromResult(result *ExtractionResult) {
+	d.Pages = result.Metadata.Pages
+	if d.Pages != nil {
+		d.PageCount = int(d.Pages.TotalCount)
+	}
+	if pdf, ok := result.Metadata.PdfMetadata(); ok {
+		d.Pdf = pdf
+		if pdf.IsEncrypted != nil {
+			d.Encrypted = *pdf.IsEncrypted
+		}
+		if d.PageCount == 0 && pdf.PageCount != nil {
+			d.PageCount = *pdf.PageCount
+		}
+	}
+
+	pageTexts := inspectionPageTexts(result)
+	if d.PageCount == 0 {
+		d.PageCount = len(pageTexts)
+	}
+	textPages, textChars := 0, 0
+	for _, text := range pageTexts {
+		chars := countNonSpace(text)
+		textChars += chars
+		if chars >= MinTextLayerCharsPerPage {
+			textPages++
+		}
+	}
+
+	pages := max(d.PageCount, len(pageTexts))
+	d.TextPages, d.TextChars = textPages, textChars
+	if result.PageSelection != nil && len(pageTexts) > 0 && len(pageTexts) < d.PageCount {
+		// Only a sample of the pages was extracted: classify the sample and
+		// extrapolate the counts to the whole document.
+		pages = len(pageTexts)
+		d.SampledPages = pages
+		d.TextPages = extrapolate(textPages, pages, d.PageCount)
+		d.TextChars = extrapolate(textChars, pages, d.PageCount)
+		if textPages > 0 && textPages < pages {
+			d.TextPages = min(max(d.TextPages, 1), d.PageCount-1)
+		}
+	}
+
+	switch {
+	case textPages == 0:
+		d.TextLayer = TextLayerAbsent
+	case textPages < pages:
+		d.TextLayer = TextLayerPartial
+	default:
+		d.TextLayer = TextLayerPresent
+	}
+	d.NeedsOCR = d.TextLayer != TextLayerPresent && normalizeMimeType(d.MimeType) == "application/pdf"
+}
+
+// extrapolate scales count, measured over sampled pages, to total pages.
+func extrapolate(count, sampled, total int) int {
+	return int((int64(count)*int64(total) + int64(sampled)/2) / int64(sampled))
+}
+
+// inspectionPageTexts splits the extracted text by page, using the per-page content
+// when available, then the page boundaries, and else treating the whole content as a
+// single page.
+func inspectionPageTexts(result *ExtractionResult) []string {
+	if len(result.Pages) > 0 {
+		texts := make([]string, len(result.Pages))
+		for i, page := range result.Pages {
+			texts[i] = page.Content
+		}
+		return texts
+	}
+	if pages := result.Metadata.Pages; pages != nil && len(pages.Boundaries) > 0 {
+		texts := make([]string, 0, len(pages.Boundaries))
+		for _, boundary := range pages.Boundaries {
+			start := min(int(boundary.ByteStart), len(result.Content))
+			end := min(int(boundary.ByteEnd), len(result.Content))
+			texts = append(texts, result.Content[start:max(start, end)])
+		}
+		return texts
+	}
+	return []string{result.Content}
+}
+
+func countNonSpace(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,197 @@
+package kreuzberg
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestInspectionFromResult(t *testing.T) {
+	text := strings.Repeat("word ", 20)
+	cases := []struct {
+		name      string
+		mimeType  string
+		pages     []string
+		want      TextLayer
+		textPages int
+		needsOCR  bool
+	}{
+		{"searchable", "application/pdf", []string{text, text}, TextLayerPresent, 2, false},
+		{"mixed", "application/pdf", []string{text, "  \n", text}, TextLayerPartial, 2, true},
+		{"scanned", "application/pdf", []string{"", "3"}, TextLayerAbsent, 0, true},
+		{"empty docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", []string{""}, TextLayerAbsent, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := &ExtractionResult{MimeType: tc.mimeType}
+			for i, content := range tc.pages {
+				result.Pages = append(result.Pages, PageContent{PageNumber: uint64(i + 1), Content: content})
+			}
+			inspection := &DocumentInspection{MimeType: tc.mimeType}
+			inspection.fromResult(result)
+			if inspection.TextLayer != tc.want || inspection.TextPages != tc.textPages || inspection.NeedsOCR != tc.needsOCR {
+				t.Errorf("inspection = %+v, want text layer %s with %d text pages, NeedsOCR %v", inspection, tc.want, tc.textPages, tc.needsOCR)
+			}
+			if inspection.PageCount != len(tc.pages) {
+				t.Errorf("PageCount = %d, want %d", inspection.PageCount, len(tc.pages))
+			}
+		})
+	}
+}
+
+func TestInspectionFromResultUsesMetadata(t *testing.T) {
+	encrypted := true
+	pageCount := 3
+	content := strings.Repeat("a", 40) + strings.Repeat("b", 40)
+	result := &ExtractionResult{
+		Content: content,
+		Metadata: Metadata{
+			Format: FormatMetadata{Type: FormatPDF, Pdf: &PdfMetadata{IsEncrypted: &encrypted, PageCount: &pageCount}},
+			Pages: &PageStructure{
+				TotalCount: 3,
+				UnitType:   PageUnitTypePage,
+				Boundaries: []PageBoundary{
+					{ByteStart: 0, ByteEnd: 40, PageNumber: 1},
+					{ByteStart: 40, ByteEnd: 80, PageNumber: 2},
+					{ByteStart: 80, ByteEnd: 80, PageNumber: 3},
+				},
+			},
+		},
+	}
+	inspection := &DocumentInspection{MimeType: "application/pdf"}
+	inspection.fromResult(result)
+	if !inspection.Encrypted || inspection.Pdf == nil || inspection.Pages == nil {
+		t.Errorf("inspection = %+v, want encrypted PDF with page structure", inspection)
+	}
+	if inspection.PageCount != 3 || inspection.TextPages != 2 || inspection.TextChars != 80 {
+		t.Errorf("PageCount = %d, TextPages = %d, TextChars = %d", inspection.PageCount, inspection.TextPages, inspection.TextChars)
+	}
+	if inspection.TextLayer != TextLayerPartial || !inspection.NeedsOCR {
+		t.Errorf("TextLayer = %s, NeedsOCR = %v", inspection.TextLayer, inspection.NeedsOCR)
+	}
+}
+
+func TestInspectReportsEncryptedDocuments(t *testing.T) {
+	inspection, err := inspect("application/pdf", 10, nil, func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		if cfg.OCR != nil || cfg.PdfOptions == nil || cfg.PdfOptions.ExtractMetadata == nil || !*cfg.PdfOptions.ExtractMetadata {
+			t.Errorf("inspection config = %+v", cfg)
+		}
+		return nil, newParsingErrorWithContext("PDF error: PDF is password-protected", nil, ErrorCodeParsing, nil)
+	})
+	if err != nil {
+		t.Fatalf("inspect() error = %v", err)
+	}
+	if !inspection.Encrypted || inspection.TextLayer != TextLayerUnknown || inspection.Size != 10 {
+		t.Errorf("inspection = %+v", inspection)
+	}
+
+	if _, err := inspect("application/pdf", 10, nil, func(*ExtractionConfig) (*ExtractionResult, error) {
+		return nil, newParsingErrorWithContext("Invalid PDF: bad xref", nil, ErrorCodeParsing, nil)
+	}); err == nil {
+		t.Error("expected parsing errors to be returned")
+	}
+}
+
+func TestInspectSamplesLongPDFs(t *testing.T) {
+	text := strings.Repeat("word ", 20)
+	pageCount := func() (int, error) { return 100, nil }
+	inspection, err := inspect("application/pdf", 10, pageCount, func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		if cfg.Pages.Ranges == nil || *cfg.Pages.Ranges != "1,15,29,43,57,71,85,100" {
+			t.Errorf("Ranges = %v, want a sample of 8 pages", cfg.Pages.Ranges)
+		}
+		result := &ExtractionResult{
+			Metadata:      Metadata{Pages: &PageStructure{TotalCount: 100, UnitType: PageUnitTypePage}},
+			PageSelection: &PageSelection{TotalPages: 100, Pages: []int{1, 15, 29, 43, 57, 71, 85, 100}},
+		}
+		for i, page := range result.PageSelection.Pages {
+			content := text
+			if i >= 6 {
+				content = ""
+			}
+			result.Pages = append(result.Pages, PageContent{PageNumber: uint64(page), Content: content})
+		}
+		return result, nil
+	})
+	if err != nil {
+		t.Fatalf("inspect() error = %v", err)
+	}
+	if inspection.PageCount != 100 || inspection.SampledPages != 8 {
+		t.Errorf("PageCount = %d, SampledPages = %d, want 100 and 8", inspection.PageCount, inspection.SampledPages)
+	}
+	if inspection.TextPages != 75 || inspection.TextChars != 6000 {
+		t.Errorf("TextPages = %d, TextChars = %d, want 75 and 6000", inspection.TextPages, inspection.TextChars)
+	}
+	if inspection.TextLayer != TextLayerPartial || !inspection.NeedsOCR {
+		t.Errorf("TextLayer = %s, NeedsOCR = %v", inspection.TextLayer, inspection.NeedsOCR)
+	}
+
+	short := func() (int, error) { return InspectSamplePages, nil }
+	if _, err := inspect("application/pdf", 10, short, func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		if cfg.Pages.Ranges != nil {
+			t.Errorf("Ranges = %q, want every page of a short PDF", *cfg.Pages.Ranges)
+		}
+		return &ExtractionResult{}, nil
+	}); err != nil {
+		t.Fatalf("inspect() error = %v", err)
+	}
+}
+
+func TestInspectReportsEncryptedPageCount(t *testing.T) {
+	pageCount := func() (int, error) {
+		return 0, newParsingErrorWithContext("PDF error: PDF is password-protected", nil, ErrorCodeParsing, nil)
+	}
+	inspection, err := inspect("application/pdf", 10, pageCount, func(*ExtractionConfig) (*ExtractionResult, error) {
+		t.Fatal("encrypted PDFs must not be extracted")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("inspect() error = %v", err)
+	}
+	if !inspection.Encrypted || inspection.TextLayer != TextLayerUnknown {
+		t.Errorf("inspection = %+v", inspection)
+	}
+}
+
+func TestInspectSkipsExtractionForImages(t *testing.T) {
+	inspection, err := inspect("image/png", 42, nil, func(*ExtractionConfig) (*ExtractionResult, error) {
+		t.Fatal("images must not be extracted")
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("inspect() error = %v", err)
+	}
+	if inspection.TextLayer != TextLayerAbsent || !inspection.NeedsOCR || inspection.PageCount != 1 {
+		t.Errorf("inspection = %+v", inspection)
+	}
+}
+
+func TestInspectPDF(t *testing.T) {
+	path := getTestFilePath("pdf/multi_page.pdf")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	inspection, err := Inspect(path)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if inspection.MimeType != "application/pdf" || inspection.Size == 0 || inspection.PageCount < 2 {
+		t.Errorf("inspection = %+v", inspection)
+	}
+	if inspection.TextLayer != TextLayerPresent || inspection.NeedsOCR {
+		t.Errorf("TextLayer = %s, NeedsOCR = %v", inspection.TextLayer, inspection.NeedsOCR)
+	}
+}
+
+func TestInspectEncryptedPDF(t *testing.T) {
+	path := getTestFilePath(encryptedPDFFixture)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	inspection, err := Inspect(path)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if !inspection.Encrypted || inspection.TextLayer != TextLayerUnknown {
+		t.Errorf("inspection = %+v, want an encrypted PDF with an unknown text layer", inspection)
+	}
+}
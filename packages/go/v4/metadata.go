@@ -47,6 +47,10 @@ func (m *Metadata) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// Standard fields that do not have the expected type, such as the comma-separated
+	// keywords string of ODT documents, are kept in Additional instead of being dropped.
+	mistyped := map[string]struct{}{}
+
 	decodeString := func(key string) *string {
 		value, exists := raw[key]
 		if !exists {
@@ -54,6 +58,7 @@ func (m *Metadata) UnmarshalJSON(data []byte) error {
 		}
 		var out string
 		if err := json.Unmarshal(value, &out); err != nil {
+			mistyped[key] = struct{}{}
 			return nil
 		}
 		return &out
@@ -66,6 +71,7 @@ func (m *Metadata) UnmarshalJSON(data []byte) error {
 		}
 		var out []string
 		if err := json.Unmarshal(value, &out); err != nil {
+			mistyped[key] = struct{}{}
 			return nil
 		}
 		return out
@@ -125,7 +131,9 @@ func (m *Metadata) UnmarshalJSON(data []byte) error {
 	m.Additional = make(map[string]json.RawMessage)
 	for key, value := range raw {
 		if _, ok := recognized[key]; ok {
-			continue
+			if _, keep := mistyped[key]; !keep {
+				continue
+			}
 		}
 		m.Additional[key] = value
 	}
@@ -133,6 +141,10 @@ func (m *Metadata) UnmarshalJSON(data []byte) error {
 		m.Additional = nil
 	}
 
+	if isInferredFormat(m.Format.Type) && !m.decodeInferredFormat() {
+		m.Format.Type = FormatUnknown
+	}
+
 	return nil
 }
 
@@ -259,14 +271,19 @@ func (m *Metadata) decodeFormat(data []byte) error {
 		}
 		m.Format.OCR = &meta
 	default:
-		m.Format.Type = FormatUnknown
+		// Formats recognized from the MIME type are decoded once Additional is known.
+		if !isInferredFormat(m.Format.Type) {
+			m.Format.Type = FormatUnknown
+		}
 	}
 	return nil
 }
 
 func (m Metadata) encodeFormat() (map[string]json.RawMessage, error) {
 	result := make(map[string]json.RawMessage)
-	if m.Format.Type == FormatUnknown || m.Format.Type == "" {
+	// Formats recognized from the MIME type are unknown to the native library and
+	// their fields are already serialized from the standard fields and Additional.
+	if m.Format.Type == FormatUnknown || m.Format.Type == "" || isInferredFormat(m.Format.Type) {
 		return result, nil
 	}
 
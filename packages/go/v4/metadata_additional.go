@@ -0,0 +1,161 @@
+package kreuzberg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetAdditional decodes the custom metadata field key into a T. ok is false when the
+// field is absent; a field that does not decode into T is reported as a validation
+// error naming the key and the mismatched types.
+func GetAdditional[T any](m *Metadata, key string) (value T, ok bool, err error) {
+	if m == nil {
+		return value, false, nil
+	}
+	raw, exists := m.Additional[key]
+	if !exists {
+		return value, false, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, true, metadataDecodeError(key, err)
+	}
+	return value, true, nil
+}
+
+// DecodeInto decodes the metadata into dst, a pointer to a struct or map, using its
+// JSON tags. dst sees the flattened JSON form produced by MarshalJSON, so it can mix
+// standard fields such as "title", format-specific fields such as "page_count" and
+// custom fields from Additional.
+func (m *Metadata) DecodeInto(dst any) error {
+	if m == nil {
+		return newValidationErrorWithContext("metadata cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return newSerializationErrorWithContext("failed to encode metadata", err, ErrorCodeValidation, nil)
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return metadataDecodeError("", err)
+	}
+	return nil
+}
+
+// SetAdditional stores value, encoded as JSON, as the custom metadata field key. The
+// field survives ResultToJSON/ResultFromJSON round-trips and is visible to the native
+// pipeline when set from a post-processor. Keys used by standard or format-specific
+// metadata fields are rejected, since they would not decode back into Additional.
+func (m *Metadata) SetAdditional(key string, value any) error {
+	if m == nil {
+		return newValidationErrorWithContext("metadata cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	if err := m.checkAdditionalKey(key); err != nil {
+		return err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return newSerializationErrorWithContext(fmt.Sprintf("failed to encode metadata field %q", key), err, ErrorCodeValidation, nil)
+	}
+	if m.Additional == nil {
+		m.Additional = map[string]json.RawMessage{}
+	}
+	m.Additional[key] = data
+	return nil
+}
+
+// MergeAdditional encodes src, a struct or map, as a JSON object and stores each of
+// its fields with SetAdditional. It is the inverse of DecodeInto for custom fields.
+// Nothing is written when any key is reserved.
+func (m *Metadata) MergeAdditional(src any) error {
+	if m == nil {
+		return newValidationErrorWithContext("metadata cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	data, err := json.Marshal(src)
+	if err != nil {
+		return newSerializationErrorWithContext("failed to encode custom metadata", err, ErrorCodeValidation, nil)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return newValidationErrorWithContext(fmt.Sprintf("custom metadata must encode to a JSON object, got %s", jsonKind(data)), nil, ErrorCodeValidation, nil)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if err := m.checkAdditionalKey(key); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	if m.Additional == nil {
+		m.Additional = map[string]json.RawMessage{}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		m.Additional[key] = fields[key]
+	}
+	return nil
+}
+
+func (m *Metadata) checkAdditionalKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return newValidationErrorWithContext("metadata key cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+	if _, reserved := metadataCoreKeys[key]; reserved {
+		return newValidationErrorWithContext(fmt.Sprintf("metadata key %q is reserved for a standard field", key), nil, ErrorCodeValidation, nil)
+	}
+	for _, field := range formatFieldSets[m.Format.Type] {
+		if field == key {
+			return newValidationErrorWithContext(fmt.Sprintf("metadata key %q is reserved for %s metadata", key, m.Format.Type), nil, ErrorCodeValidation, nil)
+		}
+	}
+	return nil
+}
+
+// metadataDecodeError turns a JSON decoding error into a validation error that names
+// the offending field.
+func metadataDecodeError(key string, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := key
+		if typeErr.Field != "" {
+			if field != "" {
+				field += "."
+			}
+			field += typeErr.Field
+		}
+		message := fmt.Sprintf("metadata field %q: cannot decode JSON %s into %s", field, typeErr.Value, typeErr.Type)
+		return newValidationErrorWithContext(message, err, ErrorCodeValidation, nil)
+	}
+	var invalidErr *json.InvalidUnmarshalError
+	if errors.As(err, &invalidErr) {
+		return newValidationErrorWithContext(fmt.Sprintf("cannot decode metadata into %s: a non-nil pointer is required", invalidErr.Type), err, ErrorCodeValidation, nil)
+	}
+	if key != "" {
+		return newSerializationErrorWithContext(fmt.Sprintf("failed to decode metadata field %q", key), err, ErrorCodeValidation, nil)
+	}
+	return newSerializationErrorWithContext("failed to decode metadata", err, ErrorCodeValidation, nil)
+}
+
+func jsonKind(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "nothing"
+	}
+	switch trimmed[0] {
+	case '[':
+		return "an array"
+	case '"':
+		return "a string"
+	case 't', 'f':
+		return "a boolean"
+	case 'n':
+		return "null"
+	case '{':
+		return "an object"
+	default:
+		return "a number"
+	}
+}
@@ -0,0 +1,105 @@
+package kreuzberg_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+type reviewInfo struct {
+	Reviewer string   `json:"reviewer"`
+	Score    float64  `json:"score"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+func TestAdditionalRoundTrip(t *testing.T) {
+	result := &kreuzberg.ExtractionResult{Content: "x", MimeType: "text/plain"}
+	if err := result.Metadata.SetAdditional("review", reviewInfo{Reviewer: "ann", Score: 0.9, Tags: []string{"ok"}}); err != nil {
+		t.Fatalf("SetAdditional() error = %v", err)
+	}
+	if err := result.Metadata.MergeAdditional(map[string]any{"tenant": "acme", "priority": 2}); err != nil {
+		t.Fatalf("MergeAdditional() error = %v", err)
+	}
+
+	encoded, err := kreuzberg.ResultToJSON(result)
+	if err != nil {
+		t.Fatalf("ResultToJSON() error = %v", err)
+	}
+	decoded, err := kreuzberg.ResultFromJSON(encoded)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+
+	review, ok, err := kreuzberg.GetAdditional[reviewInfo](&decoded.Metadata, "review")
+	if err != nil || !ok || review.Reviewer != "ann" || review.Score != 0.9 || len(review.Tags) != 1 {
+		t.Errorf("GetAdditional() = %+v, %v, %v", review, ok, err)
+	}
+	if priority, _, err := kreuzberg.GetAdditional[int](&decoded.Metadata, "priority"); err != nil || priority != 2 {
+		t.Errorf("priority = %d, %v", priority, err)
+	}
+	if _, ok, err := kreuzberg.GetAdditional[string](&decoded.Metadata, "missing"); ok || err != nil {
+		t.Errorf("missing key should report ok=false without error, got %v, %v", ok, err)
+	}
+}
+
+func TestAdditionalTypeMismatch(t *testing.T) {
+	var meta kreuzberg.Metadata
+	if err := meta.SetAdditional("review", map[string]any{"reviewer": "ann", "score": "high"}); err != nil {
+		t.Fatalf("SetAdditional() error = %v", err)
+	}
+
+	_, ok, err := kreuzberg.GetAdditional[reviewInfo](&meta, "review")
+	var validation *kreuzberg.ValidationError
+	if !ok || !errors.As(err, &validation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `"review.score"`) || !strings.Contains(err.Error(), "float64") {
+		t.Errorf("error should name the field and types: %v", err)
+	}
+
+	var dst reviewInfo
+	if err := meta.DecodeInto(dst); err == nil {
+		t.Error("DecodeInto() should reject a non-pointer")
+	}
+}
+
+func TestDecodeIntoMixesStandardAndCustomFields(t *testing.T) {
+	title := "Report"
+	pages := 3
+	meta := kreuzberg.Metadata{
+		Title:  &title,
+		Format: kreuzberg.FormatMetadata{Type: kreuzberg.FormatPDF, Pdf: &kreuzberg.PdfMetadata{PageCount: &pages}},
+	}
+	if err := meta.SetAdditional("tenant", "acme"); err != nil {
+		t.Fatalf("SetAdditional() error = %v", err)
+	}
+
+	var doc struct {
+		Title     string `json:"title"`
+		PageCount int    `json:"page_count"`
+		Tenant    string `json:"tenant"`
+	}
+	if err := meta.DecodeInto(&doc); err != nil {
+		t.Fatalf("DecodeInto() error = %v", err)
+	}
+	if doc.Title != "Report" || doc.PageCount != 3 || doc.Tenant != "acme" {
+		t.Errorf("DecodeInto() = %+v", doc)
+	}
+}
+
+func TestSetAdditionalRejectsReservedKeys(t *testing.T) {
+	meta := kreuzberg.Metadata{Format: kreuzberg.FormatMetadata{Type: kreuzberg.FormatPDF}}
+	for _, key := range []string{"title", "page_count", ""} {
+		if err := meta.SetAdditional(key, 1); err == nil {
+			t.Errorf("SetAdditional(%q) should fail", key)
+		}
+	}
+	if err := meta.MergeAdditional(map[string]any{"ok": 1, "created_at": "x"}); err == nil || meta.Additional["ok"] != nil {
+		t.Errorf("MergeAdditional() should fail without writing, got %v", err)
+	}
+	if err := meta.MergeAdditional([]int{1}); err == nil {
+		t.Error("MergeAdditional() should reject non-objects")
+	}
+}
@@ -0,0 +1,576 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// The native library only tags PDF, spreadsheet, email, presentation, archive, image,
+// XML, text, HTML and OCR metadata with a format_type. The extractors for the formats
+// below write their metadata as standard fields and custom fields instead, so the
+// typed structs are recognized from the result's MIME type and decoded from the
+// flattened metadata. They are read-only views: the underlying values stay in the
+// standard fields and Additional, which is what gets serialized.
+
+var inferredFormatsByMimeType = map[string]FormatType{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": FormatDOCX,
+	"application/vnd.oasis.opendocument.text":                                 FormatODT,
+	"application/epub+zip":                                                    FormatEPUB,
+	"application/x-epub+zip":                                                  FormatEPUB,
+	"application/vnd.epub+zip":                                                FormatEPUB,
+	"application/x-fictionbook+xml":                                           FormatFictionBook,
+	"application/x-fictionbook":                                               FormatFictionBook,
+	"text/x-fictionbook":                                                      FormatFictionBook,
+	"application/x-ipynb+json":                                                FormatJupyter,
+	"application/x-latex":                                                     FormatLaTeX,
+	"text/x-tex":                                                              FormatLaTeX,
+	"text/x-rst":                                                              FormatRST,
+	"text/prs.fallenstein.rst":                                                FormatRST,
+	"text/x-org":                                                              FormatOrgMode,
+	"text/org":                                                                FormatOrgMode,
+	"application/x-org":                                                       FormatOrgMode,
+	"application/x-typst":                                                     FormatTypst,
+	"text/x-typst":                                                            FormatTypst,
+	"application/x-bibtex":                                                    FormatBibTeX,
+	"text/x-bibtex":                                                           FormatBibTeX,
+	"text/x-opml":                                                             FormatOPML,
+	"application/xml+opml":                                                    FormatOPML,
+	"application/x-jats+xml":                                                  FormatJATS,
+	"text/jats":                                                               FormatJATS,
+	"application/docbook+xml":                                                 FormatDocBook,
+	"text/docbook":                                                            FormatDocBook,
+	"application/rtf":                                                         FormatRTF,
+	"text/rtf":                                                                FormatRTF,
+}
+
+func isInferredFormat(format FormatType) bool {
+	switch format {
+	case FormatDOCX, FormatODT, FormatEPUB, FormatFictionBook, FormatJupyter, FormatLaTeX,
+		FormatRST, FormatOrgMode, FormatTypst, FormatBibTeX, FormatOPML, FormatJATS,
+		FormatDocBook, FormatRTF:
+		return true
+	}
+	return false
+}
+
+// DocxMetadata contains the core, application and custom properties of Word documents.
+type DocxMetadata struct {
+	Title                   *string  `json:"title,omitempty"`
+	Subject                 *string  `json:"subject,omitempty"`
+	Authors                 []string `json:"authors,omitempty"`
+	Keywords                []string `json:"keywords,omitempty"`
+	Description             *string  `json:"description,omitempty"`
+	Category                *string  `json:"category,omitempty"`
+	ContentStatus           *string  `json:"content_status,omitempty"`
+	Language                *string  `json:"language,omitempty"`
+	CreatedBy               *string  `json:"created_by,omitempty"`
+	ModifiedBy              *string  `json:"modified_by,omitempty"`
+	CreatedAt               *string  `json:"created_at,omitempty"`
+	ModifiedAt              *string  `json:"modified_at,omitempty"`
+	Revision                *string  `json:"revision,omitempty"`
+	PageCount               *int     `json:"page_count,omitempty"`
+	WordCount               *int     `json:"word_count,omitempty"`
+	CharacterCount          *int     `json:"character_count,omitempty"`
+	LineCount               *int     `json:"line_count,omitempty"`
+	ParagraphCount          *int     `json:"paragraph_count,omitempty"`
+	Template                *string  `json:"template,omitempty"`
+	Company                 *string  `json:"company,omitempty"`
+	Application             *string  `json:"application,omitempty"`
+	TotalEditingTimeMinutes *int     `json:"total_editing_time_minutes,omitempty"`
+	// Custom holds the custom document properties, keyed by property name.
+	Custom map[string]json.RawMessage `json:"-"`
+}
+
+// OdtMetadata contains the document properties of OpenDocument text files.
+type OdtMetadata struct {
+	Title          *string  `json:"title,omitempty"`
+	Subject        *string  `json:"subject,omitempty"`
+	Authors        []string `json:"authors,omitempty"`
+	Keywords       []string `json:"-"`
+	Description    *string  `json:"description,omitempty"`
+	Language       *string  `json:"language,omitempty"`
+	CreatedBy      *string  `json:"created_by,omitempty"`
+	InitialCreator *string  `json:"initial_creator,omitempty"`
+	CreatedAt      *string  `json:"created_at,omitempty"`
+	ModifiedAt     *string  `json:"modified_at,omitempty"`
+	Generator      *string  `json:"generator,omitempty"`
+	// EditingDuration is an ISO 8601 duration such as "PT1H5M".
+	EditingDuration *string `json:"editing_duration,omitempty"`
+	EditingCycles   *string `json:"editing_cycles,omitempty"`
+	PageCount       *int    `json:"page_count,omitempty"`
+	WordCount       *int    `json:"word_count,omitempty"`
+	CharacterCount  *int    `json:"character_count,omitempty"`
+	ParagraphCount  *int    `json:"paragraph_count,omitempty"`
+	TableCount      *int    `json:"table_count,omitempty"`
+	ImageCount      *int    `json:"image_count,omitempty"`
+}
+
+// EpubMetadata contains the Dublin Core fields of an EPUB package (OPF) document.
+type EpubMetadata struct {
+	Title       *string  `json:"title,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	Language    *string  `json:"language,omitempty"`
+	Date        *string  `json:"created_at,omitempty"`
+	Identifier  *string  `json:"identifier,omitempty"`
+	Publisher   *string  `json:"publisher,omitempty"`
+	Subject     *string  `json:"subject,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Rights      *string  `json:"rights,omitempty"`
+}
+
+// FictionBookMetadata contains the title-info fields of FictionBook (FB2) documents.
+type FictionBookMetadata struct {
+	Genre    *string `json:"subject,omitempty"`
+	Date     *string `json:"created_at,omitempty"`
+	Language *string `json:"language,omitempty"`
+}
+
+// JupyterMetadata contains the notebook-level metadata of Jupyter notebooks.
+type JupyterMetadata struct {
+	KernelSpec   *JupyterKernelSpec   `json:"kernelspec,omitempty"`
+	LanguageInfo *JupyterLanguageInfo `json:"language_info,omitempty"`
+	NbFormat     *int                 `json:"nbformat,omitempty"`
+}
+
+// JupyterKernelSpec identifies the kernel a notebook was written for.
+type JupyterKernelSpec struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name,omitempty"`
+	Language    string `json:"language,omitempty"`
+}
+
+// JupyterLanguageInfo describes the programming language of a notebook.
+type JupyterLanguageInfo struct {
+	Name              string `json:"name"`
+	Version           string `json:"version,omitempty"`
+	MimeType          string `json:"mimetype,omitempty"`
+	FileExtension     string `json:"file_extension,omitempty"`
+	PygmentsLexer     string `json:"pygments_lexer,omitempty"`
+	NbconvertExporter string `json:"nbconvert_exporter,omitempty"`
+	// CodemirrorMode is either a mode name or an object such as {"name": "ipython", "version": 3}.
+	CodemirrorMode json.RawMessage `json:"codemirror_mode,omitempty"`
+}
+
+// LatexMetadata contains the \title, \author and \date of LaTeX documents.
+type LatexMetadata struct {
+	Title  *string `json:"title,omitempty"`
+	Author *string `json:"author,omitempty"`
+	Date   *string `json:"date,omitempty"`
+}
+
+// RstMetadata contains the bibliographic fields of reStructuredText documents.
+type RstMetadata struct {
+	Title   *string `json:"title,omitempty"`
+	Author  *string `json:"author,omitempty"`
+	Date    *string `json:"date,omitempty"`
+	Version *string `json:"version,omitempty"`
+	// Fields holds the remaining field list entries, keyed by lowercased field name.
+	Fields map[string]string `json:"-"`
+}
+
+// OrgModeMetadata contains the in-buffer settings of Org-mode documents.
+type OrgModeMetadata struct {
+	Title    *string  `json:"title,omitempty"`
+	Author   *string  `json:"author,omitempty"`
+	Authors  []string `json:"authors,omitempty"`
+	Date     *string  `json:"date,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+	// Directives holds the remaining "#+KEY: value" lines, keyed by lowercased key.
+	Directives map[string]string `json:"-"`
+}
+
+// TypstMetadata contains the document settings of Typst documents.
+type TypstMetadata struct {
+	Title    *string  `json:"title,omitempty"`
+	Author   *string  `json:"author,omitempty"`
+	Subject  *string  `json:"subject,omitempty"`
+	Date     *string  `json:"created_at,omitempty"`
+	Keywords []string `json:"-"`
+}
+
+// BibtexMetadata summarizes the entries of BibTeX bibliographies.
+type BibtexMetadata struct {
+	EntryCount   int              `json:"entry_count"`
+	Authors      []string         `json:"authors,omitempty"`
+	YearRange    *BibtexYearRange `json:"year_range,omitempty"`
+	EntryTypes   map[string]int   `json:"entry_types,omitempty"`
+	CitationKeys []string         `json:"citation_keys,omitempty"`
+}
+
+// BibtexYearRange lists the publication years found in a bibliography.
+type BibtexYearRange struct {
+	Min   int   `json:"min"`
+	Max   int   `json:"max"`
+	Years []int `json:"years"`
+}
+
+// OpmlMetadata contains the head elements of OPML outlines.
+type OpmlMetadata struct {
+	Title        *string `json:"title,omitempty"`
+	DateCreated  *string `json:"dateCreated,omitempty"`
+	DateModified *string `json:"dateModified,omitempty"`
+	OwnerName    *string `json:"ownerName,omitempty"`
+	OwnerEmail   *string `json:"ownerEmail,omitempty"`
+}
+
+// JatsMetadata contains the article front matter of JATS documents. The native library
+// reports it as a single "Label: value | ..." subject, which is split into fields.
+type JatsMetadata struct {
+	Title               *string  `json:"title,omitempty"`
+	Subtitle            *string  `json:"subtitle,omitempty"`
+	Authors             []string `json:"authors,omitempty"`
+	Affiliations        []string `json:"affiliations,omitempty"`
+	DOI                 *string  `json:"doi,omitempty"`
+	PII                 *string  `json:"pii,omitempty"`
+	Keywords            []string `json:"keywords,omitempty"`
+	PublicationDate     *string  `json:"publication_date,omitempty"`
+	Volume              *string  `json:"volume,omitempty"`
+	Issue               *string  `json:"issue,omitempty"`
+	Pages               *string  `json:"pages,omitempty"`
+	Journal             *string  `json:"journal,omitempty"`
+	ArticleType         *string  `json:"article_type,omitempty"`
+	Abstract            *string  `json:"abstract,omitempty"`
+	CorrespondingAuthor *string  `json:"corresponding_author,omitempty"`
+}
+
+// DocBookMetadata contains the info element of DocBook documents. The native library
+// reports the title and author as a "Title: ...; Author: ..." subject, which is split
+// into fields.
+type DocBookMetadata struct {
+	Title  *string `json:"title,omitempty"`
+	Author *string `json:"author,omitempty"`
+	Date   *string `json:"date,omitempty"`
+}
+
+// RtfMetadata contains the info group of RTF documents.
+type RtfMetadata struct {
+	Title          *string  `json:"title,omitempty"`
+	Subject        *string  `json:"subject,omitempty"`
+	Authors        []string `json:"authors,omitempty"`
+	CreatedBy      *string  `json:"created_by,omitempty"`
+	ModifiedBy     *string  `json:"modified_by,omitempty"`
+	CreatedAt      *string  `json:"created_at,omitempty"`
+	ModifiedAt     *string  `json:"modified_at,omitempty"`
+	Generator      *string  `json:"generator,omitempty"`
+	Revision       *string  `json:"revision,omitempty"`
+	PageCount      *int     `json:"page_count,omitempty"`
+	WordCount      *int     `json:"word_count,omitempty"`
+	CharacterCount *int     `json:"character_count,omitempty"`
+	LineCount      *int     `json:"line_count,omitempty"`
+	ParagraphCount *int     `json:"paragraph_count,omitempty"`
+}
+
+// DocxMetadata returns the DOCX metadata if present.
+func (m Metadata) DocxMetadata() (*DocxMetadata, bool) {
+	return m.Format.Docx, m.Format.Type == FormatDOCX && m.Format.Docx != nil
+}
+
+// OdtMetadata returns the ODT metadata if present.
+func (m Metadata) OdtMetadata() (*OdtMetadata, bool) {
+	return m.Format.Odt, m.Format.Type == FormatODT && m.Format.Odt != nil
+}
+
+// EpubMetadata returns the EPUB metadata if present.
+func (m Metadata) EpubMetadata() (*EpubMetadata, bool) {
+	return m.Format.Epub, m.Format.Type == FormatEPUB && m.Format.Epub != nil
+}
+
+// FictionBookMetadata returns the FictionBook metadata if present.
+func (m Metadata) FictionBookMetadata() (*FictionBookMetadata, bool) {
+	return m.Format.FictionBook, m.Format.Type == FormatFictionBook && m.Format.FictionBook != nil
+}
+
+// JupyterMetadata returns the Jupyter notebook metadata if present.
+func (m Metadata) JupyterMetadata() (*JupyterMetadata, bool) {
+	return m.Format.Jupyter, m.Format.Type == FormatJupyter && m.Format.Jupyter != nil
+}
+
+// LatexMetadata returns the LaTeX metadata if present.
+func (m Metadata) LatexMetadata() (*LatexMetadata, bool) {
+	return m.Format.Latex, m.Format.Type == FormatLaTeX && m.Format.Latex != nil
+}
+
+// RstMetadata returns the reStructuredText metadata if present.
+func (m Metadata) RstMetadata() (*RstMetadata, bool) {
+	return m.Format.Rst, m.Format.Type == FormatRST && m.Format.Rst != nil
+}
+
+// OrgModeMetadata returns the Org-mode metadata if present.
+func (m Metadata) OrgModeMetadata() (*OrgModeMetadata, bool) {
+	return m.Format.OrgMode, m.Format.Type == FormatOrgMode && m.Format.OrgMode != nil
+}
+
+// TypstMetadata returns the Typst metadata if present.
+func (m Metadata) TypstMetadata() (*TypstMetadata, bool) {
+	return m.Format.Typst, m.Format.Type == FormatTypst && m.Format.Typst != nil
+}
+
+// BibtexMetadata returns the BibTeX metadata if present.
+func (m Metadata) BibtexMetadata() (*BibtexMetadata, bool) {
+	return m.Format.Bibtex, m.Format.Type == FormatBibTeX && m.Format.Bibtex != nil
+}
+
+// OpmlMetadata returns the OPML metadata if present.
+func (m Metadata) OpmlMetadata() (*OpmlMetadata, bool) {
+	return m.Format.Opml, m.Format.Type == FormatOPML && m.Format.Opml != nil
+}
+
+// JatsMetadata returns the JATS metadata if present.
+func (m Metadata) JatsMetadata() (*JatsMetadata, bool) {
+	return m.Format.Jats, m.Format.Type == FormatJATS && m.Format.Jats != nil
+}
+
+// DocBookMetadata returns the DocBook metadata if present.
+func (m Metadata) DocBookMetadata() (*DocBookMetadata, bool) {
+	return m.Format.DocBook, m.Format.Type == FormatDocBook && m.Format.DocBook != nil
+}
+
+// RtfMetadata returns the RTF metadata if present.
+func (m Metadata) RtfMetadata() (*RtfMetadata, bool) {
+	return m.Format.Rtf, m.Format.Type == FormatRTF && m.Format.Rtf != nil
+}
+
+// inferFormat sets the format of metadata that carries no format_type from the MIME
+// type of its result and decodes the matching typed view.
+func (m *Metadata) inferFormat(mimeType string) {
+	if m.Format.Type != FormatUnknown {
+		return
+	}
+	format, ok := inferredFormatsByMimeType[normalizeMimeType(mimeType)]
+	if !ok {
+		return
+	}
+	m.Format.Type = format
+	if !m.decodeInferredFormat() {
+		m.Format.Type = FormatUnknown
+	}
+}
+
+// decodeInferredFormat decodes the typed view for m.Format.Type from the flattened
+// metadata. It reports false when the metadata does not fit the view.
+func (m *Metadata) decodeInferredFormat() bool {
+	decode := func(dst any) bool {
+		return m.DecodeInto(dst) == nil
+	}
+
+	switch m.Format.Type {
+	case FormatDOCX:
+		var meta DocxMetadata
+		if !decode(&meta) {
+			return false
+		}
+		meta.Custom = m.additionalWithPrefix("custom_")
+		m.Format.Docx = &meta
+	case FormatODT:
+		var meta OdtMetadata
+		if !decode(&meta) {
+			return false
+		}
+		meta.Keywords = m.keywordList()
+		m.Format.Odt = &meta
+	case FormatEPUB:
+		var meta EpubMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Epub = &meta
+	case FormatFictionBook:
+		var meta FictionBookMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.FictionBook = &meta
+	case FormatJupyter:
+		var meta JupyterMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Jupyter = &meta
+	case FormatLaTeX:
+		var meta LatexMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Latex = &meta
+	case FormatRST:
+		var meta RstMetadata
+		if !decode(&meta) {
+			return false
+		}
+		meta.Fields = additionalStrings(m.additionalWithPrefix("field_"))
+		m.Format.Rst = &meta
+	case FormatOrgMode:
+		var meta OrgModeMetadata
+		if !decode(&meta) {
+			return false
+		}
+		meta.Directives = additionalStrings(m.additionalWithPrefix("directive_"))
+		m.Format.OrgMode = &meta
+	case FormatTypst:
+		var meta TypstMetadata
+		if !decode(&meta) {
+			return false
+		}
+		meta.Keywords = m.keywordList()
+		m.Format.Typst = &meta
+	case FormatBibTeX:
+		var meta BibtexMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Bibtex = &meta
+	case FormatOPML:
+		var meta OpmlMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Opml = &meta
+	case FormatJATS:
+		m.Format.Jats = decodeJatsSubject(m.Subject)
+		if m.Format.Jats.PublicationDate == nil && m.CreatedAt != nil {
+			m.Format.Jats.PublicationDate = stringPtr(*m.CreatedAt)
+		}
+	case FormatDocBook:
+		m.Format.DocBook = decodeDocBookSubject(m.Subject)
+		if m.CreatedAt != nil {
+			m.Format.DocBook.Date = stringPtr(*m.CreatedAt)
+		}
+	case FormatRTF:
+		var meta RtfMetadata
+		if !decode(&meta) {
+			return false
+		}
+		m.Format.Rtf = &meta
+	default:
+		return false
+	}
+	return true
+}
+
+// additionalWithPrefix returns the custom fields whose key starts with prefix, keyed
+// by the rest of the key.
+func (m *Metadata) additionalWithPrefix(prefix string) map[string]json.RawMessage {
+	var out map[string]json.RawMessage
+	for key, value := range m.Additional {
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok || name == "" {
+			continue
+		}
+		if out == nil {
+			out = map[string]json.RawMessage{}
+		}
+		out[name] = value
+	}
+	return out
+}
+
+func additionalStrings(fields map[string]json.RawMessage) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(fields))
+	for key, value := range fields {
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			out[key] = s
+		}
+	}
+	return out
+}
+
+// keywordList returns the keywords whether they were reported as a list or, as ODT and
+// Typst do, as a single comma-separated string.
+func (m *Metadata) keywordList() []string {
+	if len(m.Keywords) > 0 {
+		return m.Keywords
+	}
+	var joined string
+	if raw, ok := m.Additional["keywords"]; !ok || json.Unmarshal(raw, &joined) != nil {
+		return nil
+	}
+	return splitMetadataList(joined, ",")
+}
+
+func splitMetadataList(s, sep string) []string {
+	var out []string
+	for _, part := range strings.Split(s, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+var jatsSubjectLabels = []string{
+	"Title", "Subtitle", "Authors", "Affiliations", "DOI", "PII", "Keywords",
+	"Publication Date", "Volume", "Issue", "Pages", "Journal", "Article Type",
+	"Abstract", "Corresponding Author",
+}
+
+func decodeJatsSubject(subject *string) *JatsMetadata {
+	parts := parseLabelledSubject(subject, " | ", jatsSubjectLabels)
+	meta := &JatsMetadata{
+		Title:               parts.get("Title"),
+		Subtitle:            parts.get("Subtitle"),
+		DOI:                 parts.get("DOI"),
+		PII:                 parts.get("PII"),
+		PublicationDate:     parts.get("Publication Date"),
+		Volume:              parts.get("Volume"),
+		Issue:               parts.get("Issue"),
+		Pages:               parts.get("Pages"),
+		Journal:             parts.get("Journal"),
+		ArticleType:         parts.get("Article Type"),
+		Abstract:            parts.get("Abstract"),
+		CorrespondingAuthor: parts.get("Corresponding Author"),
+	}
+	if v := parts.get("Authors"); v != nil {
+		meta.Authors = splitMetadataList(*v, ";")
+	}
+	if v := parts.get("Affiliations"); v != nil {
+		meta.Affiliations = splitMetadataList(*v, ";")
+	}
+	if v := parts.get("Keywords"); v != nil {
+		meta.Keywords = splitMetadataList(*v, ";")
+	}
+	return meta
+}
+
+func decodeDocBookSubject(subject *string) *DocBookMetadata {
+	parts := parseLabelledSubject(subject, "; ", []string{"Title", "Author"})
+	return &DocBookMetadata{Title: parts.get("Title"), Author: parts.get("Author")}
+}
+
+type labelledParts map[string]string
+
+func (p labelledParts) get(label string) *string {
+	if value, ok := p[label]; ok {
+		return &value
+	}
+	return nil
+}
+
+// parseLabelledSubject splits a "Label: value<sep>Label: value" subject into its parts.
+// A part without a known label is taken to be a continuation of the previous value,
+// which happens when a value itself contains sep.
+func parseLabelledSubject(subject *string, sep string, labels []string) labelledParts {
+	parts := labelledParts{}
+	if subject == nil {
+		return parts
+	}
+	current := ""
+	for _, part := range strings.Split(*subject, sep) {
+		matched := false
+		for _, label := range labels {
+			if value, ok := strings.CutPrefix(part, label+": "); ok {
+				if _, seen := parts[label]; !seen {
+					current, parts[label], matched = label, value, true
+					break
+				}
+			}
+		}
+		if !matched && current != "" {
+			parts[current] += sep + part
+		}
+	}
+	return parts
+}
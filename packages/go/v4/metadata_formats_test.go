@@ -0,0 +1,399 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatMetadataFromFixtures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		format   FormatType
+		check    func(t *testing.T, m Metadata)
+	}{
+		{
+			name:     "DOCX",
+			filename: "docx/sample_document.docx",
+			format:   FormatDOCX,
+			check: func(t *testing.T, m Metadata) {
+				docx, ok := m.DocxMetadata()
+				if !ok {
+					t.Fatal("expected DOCX metadata")
+				}
+				expectString(t, "created_by", docx.CreatedBy, "Christoph Auer")
+				expectString(t, "modified_by", docx.ModifiedBy, "Maxim Lysak")
+				expectString(t, "revision", docx.Revision, "7")
+				expectString(t, "application", docx.Application, "Microsoft Office Word")
+				expectInt(t, "page_count", docx.PageCount, 2)
+				expectInt(t, "word_count", docx.WordCount, 108)
+				expectInt(t, "total_editing_time_minutes", docx.TotalEditingTimeMinutes, 3)
+			},
+		},
+		{
+			name:     "ODT",
+			filename: "odt/bold.odt",
+			format:   FormatODT,
+			check: func(t *testing.T, m Metadata) {
+				odt, ok := m.OdtMetadata()
+				if !ok {
+					t.Fatal("expected ODT metadata")
+				}
+				expectString(t, "initial_creator", odt.InitialCreator, "Martin Linnemann")
+				expectInt(t, "page_count", odt.PageCount, 1)
+				expectInt(t, "word_count", odt.WordCount, 4)
+				if odt.Generator == nil || !strings.HasPrefix(*odt.Generator, "OpenOffice/4.0.1") {
+					t.Errorf("generator = %v, want OpenOffice/4.0.1 prefix", odt.Generator)
+				}
+			},
+		},
+		{
+			name:     "EPUB",
+			filename: "epub/features.epub",
+			format:   FormatEPUB,
+			check: func(t *testing.T, m Metadata) {
+				epub, ok := m.EpubMetadata()
+				if !ok {
+					t.Fatal("expected EPUB metadata")
+				}
+				expectString(t, "title", epub.Title, "EPUBTEST 0100 - Reflowable Content Tests")
+				expectString(t, "identifier", epub.Identifier, "com.github.epub-testsuite.epub30-test-0100")
+				if epub.Description == nil || !strings.HasPrefix(*epub.Description, "Tests for Content Documents") {
+					t.Errorf("description = %v", epub.Description)
+				}
+			},
+		},
+		{
+			name:     "FictionBook",
+			filename: "fictionbook/basic.fb2",
+			format:   FormatFictionBook,
+			check: func(t *testing.T, m Metadata) {
+				fb, ok := m.FictionBookMetadata()
+				if !ok {
+					t.Fatal("expected FictionBook metadata")
+				}
+				expectString(t, "genre", fb.Genre, "unrecognised")
+			},
+		},
+		{
+			name:     "Jupyter",
+			filename: "jupyter/rank.ipynb",
+			format:   FormatJupyter,
+			check: func(t *testing.T, m Metadata) {
+				nb, ok := m.JupyterMetadata()
+				if !ok {
+					t.Fatal("expected Jupyter metadata")
+				}
+				if nb.KernelSpec == nil || nb.KernelSpec.Name != "python3" || nb.KernelSpec.DisplayName != "Python 3 (ipykernel)" {
+					t.Errorf("kernelspec = %+v", nb.KernelSpec)
+				}
+				if nb.LanguageInfo == nil || nb.LanguageInfo.Name != "python" || nb.LanguageInfo.Version != "3.10.0" {
+					t.Errorf("language_info = %+v", nb.LanguageInfo)
+				}
+				expectInt(t, "nbformat", nb.NbFormat, 4)
+			},
+		},
+		{
+			name:     "LaTeX",
+			filename: "latex/latex_document.tex",
+			format:   FormatLaTeX,
+			check: func(t *testing.T, m Metadata) {
+				tex, ok := m.LatexMetadata()
+				if !ok {
+					t.Fatal("expected LaTeX metadata")
+				}
+				expectString(t, "title", tex.Title, "LaTeX Test Document")
+				expectString(t, "date", tex.Date, "September 2025")
+			},
+		},
+		{
+			name:     "RST",
+			filename: "rst/restructured_text.rst",
+			format:   FormatRST,
+			check: func(t *testing.T, m Metadata) {
+				rst, ok := m.RstMetadata()
+				if !ok {
+					t.Fatal("expected RST metadata")
+				}
+				expectString(t, "author", rst.Author, "Emily Chen")
+				expectString(t, "date", rst.Date, "2025-09-27")
+				expectString(t, "version", rst.Version, "1.0")
+				if got := rst.Fields["organization"]; got != "Testing Department" {
+					t.Errorf("fields[organization] = %q", got)
+				}
+			},
+		},
+		{
+			name:     "Org-mode",
+			filename: "org/comprehensive.org",
+			format:   FormatOrgMode,
+			check: func(t *testing.T, m Metadata) {
+				org, ok := m.OrgModeMetadata()
+				if !ok {
+					t.Fatal("expected Org-mode metadata")
+				}
+				if got := org.Directives["title"]; got != "Pandoc Test Suite" {
+					t.Errorf("directives[title] = %q", got)
+				}
+				if got := org.Directives["author"]; got != "John MacFarlane" {
+					t.Errorf("directives[author] = %q", got)
+				}
+			},
+		},
+		{
+			name:     "Typst",
+			filename: "typst/metadata.typ",
+			format:   FormatTypst,
+			check: func(t *testing.T, m Metadata) {
+				typ, ok := m.TypstMetadata()
+				if !ok {
+					t.Fatal("expected Typst metadata")
+				}
+				expectString(t, "title", typ.Title, "Metadata Example Document")
+				expectString(t, "author", typ.Author, "John Doe")
+				expectString(t, "subject", typ.Subject, "Testing Document Metadata")
+				if want := []string{"metadata", "testing", "extraction"}; !reflect.DeepEqual(typ.Keywords, want) {
+					t.Errorf("keywords = %v, want %v", typ.Keywords, want)
+				}
+			},
+		},
+		{
+			name:     "BibTeX",
+			filename: "bibtex/comprehensive.bib",
+			format:   FormatBibTeX,
+			check: func(t *testing.T, m Metadata) {
+				bib, ok := m.BibtexMetadata()
+				if !ok {
+					t.Fatal("expected BibTeX metadata")
+				}
+				if bib.EntryCount == 0 || bib.EntryCount != len(bib.CitationKeys) {
+					t.Errorf("entry_count = %d with %d citation keys", bib.EntryCount, len(bib.CitationKeys))
+				}
+				if len(bib.Authors) == 0 || len(bib.EntryTypes) == 0 {
+					t.Errorf("expected authors and entry types, got %+v", bib)
+				}
+				if bib.YearRange != nil && bib.YearRange.Min > bib.YearRange.Max {
+					t.Errorf("year_range = %+v", bib.YearRange)
+				}
+			},
+		},
+		{
+			name:     "OPML",
+			filename: "opml/feeds.opml",
+			format:   FormatOPML,
+			check: func(t *testing.T, m Metadata) {
+				opml, ok := m.OpmlMetadata()
+				if !ok {
+					t.Fatal("expected OPML metadata")
+				}
+				expectString(t, "title", opml.Title, "Tech News Feeds")
+				expectString(t, "ownerName", opml.OwnerName, "John Smith")
+				expectString(t, "ownerEmail", opml.OwnerEmail, "john@example.com")
+				expectString(t, "dateCreated", opml.DateCreated, "Mon, 06 Nov 2023 00:00:00 GMT")
+			},
+		},
+		{
+			name:     "JATS",
+			filename: "jats/sample_article.jats",
+			format:   FormatJATS,
+			check: func(t *testing.T, m Metadata) {
+				jats, ok := m.JatsMetadata()
+				if !ok {
+					t.Fatal("expected JATS metadata")
+				}
+				expectString(t, "title", jats.Title, "Effects of Caffeine on Human Health")
+				if len(jats.Keywords) == 0 || jats.Keywords[0] != "caffeine" {
+					t.Errorf("keywords = %v", jats.Keywords)
+				}
+			},
+		},
+		{
+			name:     "DocBook",
+			filename: "docbook/docbook-reader.docbook",
+			format:   FormatDocBook,
+			check: func(t *testing.T, m Metadata) {
+				db, ok := m.DocBookMetadata()
+				if !ok {
+					t.Fatal("expected DocBook metadata")
+				}
+				expectString(t, "title", db.Title, "Pandoc Test Suite")
+				expectString(t, "date", db.Date, "July 17, 2006")
+			},
+		},
+		{
+			name:     "RTF",
+			filename: "rtf/word_sample.rtf",
+			format:   FormatRTF,
+			check: func(t *testing.T, m Metadata) {
+				rtf, ok := m.RtfMetadata()
+				if !ok {
+					t.Fatal("expected RTF metadata")
+				}
+				expectString(t, "created_by", rtf.CreatedBy, "Christoph Auer")
+				expectString(t, "modified_by", rtf.ModifiedBy, "Maxim Lysak")
+				expectString(t, "revision", rtf.Revision, "7")
+				expectInt(t, "page_count", rtf.PageCount, 2)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := getTestFilePath(tc.filename)
+			if _, err := os.Stat(filePath); err != nil {
+				t.Skipf("test file not found: %s", filePath)
+			}
+
+			result, err := ExtractFileSync(filePath, nil)
+			if err != nil {
+				t.Fatalf("ExtractFileSync failed: %v", err)
+			}
+			if got := result.Metadata.FormatType(); got != tc.format {
+				t.Fatalf("FormatType() = %q, want %q (MIME type %s)", got, tc.format, result.MimeType)
+			}
+			tc.check(t, result.Metadata)
+		})
+	}
+}
+
+func TestFormatMetadataIsNotSerialized(t *testing.T) {
+	payload := `{"content":"x","mime_type":"application/vnd.oasis.opendocument.text","metadata":{"title":"Report","keywords":"alpha, beta,, gamma","generator":"LibreOffice","page_count":3},"tables":[]}`
+	result, err := ResultFromJSON(payload)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	odt, ok := result.Metadata.OdtMetadata()
+	if !ok {
+		t.Fatal("expected ODT metadata")
+	}
+	if want := []string{"alpha", "beta", "gamma"}; !reflect.DeepEqual(odt.Keywords, want) {
+		t.Errorf("keywords = %v, want %v", odt.Keywords, want)
+	}
+	expectString(t, "generator", odt.Generator, "LibreOffice")
+	expectInt(t, "page_count", odt.PageCount, 3)
+
+	encoded, err := ResultToJSON(result)
+	if err != nil {
+		t.Fatalf("ResultToJSON() error = %v", err)
+	}
+	var decoded struct {
+		Metadata map[string]json.RawMessage `json:"metadata"`
+	}
+	if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := decoded.Metadata["format_type"]; ok {
+		t.Errorf("format_type must not be serialized for inferred formats: %s", encoded)
+	}
+	if got := string(decoded.Metadata["keywords"]); got != `"alpha, beta,, gamma"` {
+		t.Errorf("keywords serialized as %s, want the original string", got)
+	}
+	if len(decoded.Metadata) != 4 {
+		t.Errorf("metadata keys = %d, want 4: %s", len(decoded.Metadata), encoded)
+	}
+}
+
+func TestFormatMetadataLabelledSubjects(t *testing.T) {
+	jatsPayload := `{"content":"","mime_type":"application/x-jats+xml","metadata":{"subject":"Title: On Pipes | Authors: Ann Lee; Bo Chen | DOI: 10.1/x | Keywords: flow; pipes | Abstract: Flow | pressure. | Volume: 2","created_at":"2020-01-02"}}`
+	result, err := ResultFromJSON(jatsPayload)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	jats, ok := result.Metadata.JatsMetadata()
+	if !ok {
+		t.Fatal("expected JATS metadata")
+	}
+	expectString(t, "title", jats.Title, "On Pipes")
+	expectString(t, "doi", jats.DOI, "10.1/x")
+	expectString(t, "abstract", jats.Abstract, "Flow | pressure.")
+	expectString(t, "volume", jats.Volume, "2")
+	expectString(t, "publication_date", jats.PublicationDate, "2020-01-02")
+	if want := []string{"Ann Lee", "Bo Chen"}; !reflect.DeepEqual(jats.Authors, want) {
+		t.Errorf("authors = %v, want %v", jats.Authors, want)
+	}
+	if want := []string{"flow", "pipes"}; !reflect.DeepEqual(jats.Keywords, want) {
+		t.Errorf("keywords = %v, want %v", jats.Keywords, want)
+	}
+
+	docbookPayload := `{"content":"","mime_type":"application/docbook+xml","metadata":{"subject":"Title: Guide; Author: Jo Smith","created_at":"2006"}}`
+	result, err = ResultFromJSON(docbookPayload)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	db, ok := result.Metadata.DocBookMetadata()
+	if !ok {
+		t.Fatal("expected DocBook metadata")
+	}
+	expectString(t, "title", db.Title, "Guide")
+	expectString(t, "author", db.Author, "Jo Smith")
+	expectString(t, "date", db.Date, "2006")
+}
+
+func TestFormatMetadataCustomFields(t *testing.T) {
+	payload := `{"content":"","mime_type":"application/vnd.openxmlformats-officedocument.wordprocessingml.document","metadata":{"authors":["Ann"],"keywords":["a","b"],"company":"Acme","custom_Project":"Apollo","custom_Budget":12}}`
+	result, err := ResultFromJSON(payload)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	docx, ok := result.Metadata.DocxMetadata()
+	if !ok {
+		t.Fatal("expected DOCX metadata")
+	}
+	expectString(t, "company", docx.Company, "Acme")
+	if !reflect.DeepEqual(docx.Keywords, []string{"a", "b"}) {
+		t.Errorf("keywords = %v", docx.Keywords)
+	}
+	if got := string(docx.Custom["Project"]); got != `"Apollo"` {
+		t.Errorf("custom[Project] = %s", got)
+	}
+	if got := string(docx.Custom["Budget"]); got != "12" {
+		t.Errorf("custom[Budget] = %s", got)
+	}
+
+	rstPayload := `{"content":"","mime_type":"text/x-rst","metadata":{"author":"Emily","field_organization":"QA","field_status":"draft"}}`
+	result, err = ResultFromJSON(rstPayload)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	rst, ok := result.Metadata.RstMetadata()
+	if !ok {
+		t.Fatal("expected RST metadata")
+	}
+	if want := map[string]string{"organization": "QA", "status": "draft"}; !reflect.DeepEqual(rst.Fields, want) {
+		t.Errorf("fields = %v, want %v", rst.Fields, want)
+	}
+}
+
+func TestFormatMetadataUnknownMimeType(t *testing.T) {
+	result, err := ResultFromJSON(`{"content":"","mime_type":"application/x-unknown","metadata":{"author":"x"}}`)
+	if err != nil {
+		t.Fatalf("ResultFromJSON() error = %v", err)
+	}
+	if got := result.Metadata.FormatType(); got != FormatUnknown {
+		t.Errorf("FormatType() = %q, want unknown", got)
+	}
+	if _, ok := result.Metadata.LatexMetadata(); ok {
+		t.Error("unexpected LaTeX metadata")
+	}
+}
+
+func expectString(t *testing.T, field string, got *string, want string) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s is nil, want %q", field, want)
+	} else if *got != want {
+		t.Errorf("%s = %q, want %q", field, *got, want)
+	}
+}
+
+func expectInt(t *testing.T, field string, got *int, want int) {
+	t.Helper()
+	if got == nil {
+		t.Errorf("%s is nil, want %d", field, want)
+	} else if *got != want {
+		t.Errorf("%s = %d, want %d", field, *got, want)
+	}
+}
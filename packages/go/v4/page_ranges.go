@@ -0,0 +1,350 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PageRange is an inclusive range of 1-indexed pages. Negative numbers count from the
+// end of the document: -1 is the last page.
+type PageRange struct {
+	First int
+	Last  int
+}
+
+// PageSelection reports the pages kept by PageConfig.Ranges.
+type PageSelection struct {
+	// Ranges is the requested selection.
+	Ranges string
+	// TotalPages is the page count of the whole document.
+	TotalPages int
+	// Pages lists the selected pages in ascending order.
+	Pages []int
+}
+
+// ParsePageRanges parses a comma-separated list of pages and page ranges:
+//
+//	"7"     page 7
+//	"1-3"   pages 1 to 3
+//	"10-"   page 10 to the last page
+//	"-2"    the last two pages
+//
+// Ranges may overlap and be given in any order.
+func ParsePageRanges(spec string) ([]PageRange, error) {
+	var ranges []PageRange
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		r, err := parsePageRange(item)
+		if err != nil {
+			return nil, newValidationErrorWithContext(fmt.Sprintf("invalid page range %q in %q", item, spec), err, ErrorCodeValidation, nil)
+		}
+		ranges = append(ranges, r)
+	}
+	if len(ranges) == 0 {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("page ranges %q select no pages", spec), nil, ErrorCodeValidation, nil)
+	}
+	return ranges, nil
+}
+
+func parsePageRange(item string) (PageRange, error) {
+	if rest, ok := strings.CutPrefix(item, "-"); ok {
+		count, err := parsePageNumber(rest)
+		if err != nil {
+			return PageRange{}, err
+		}
+		return PageRange{First: -count, Last: -1}, nil
+	}
+	first, last, isRange := strings.Cut(item, "-")
+	start, err := parsePageNumber(first)
+	if err != nil {
+		return PageRange{}, err
+	}
+	if !isRange {
+		return PageRange{First: start, Last: start}, nil
+	}
+	if strings.TrimSpace(last) == "" {
+		return PageRange{First: start, Last: -1}, nil
+	}
+	end, err := parsePageNumber(last)
+	if err != nil {
+		return PageRange{}, err
+	}
+	if end < start {
+		return PageRange{}, fmt.Errorf("range ends before it starts")
+	}
+	return PageRange{First: start, Last: end}, nil
+}
+
+func parsePageNumber(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("page numbers start at 1")
+	}
+	return n, nil
+}
+
+// Resolve returns the pages of r in a document of total pages, clipped to the
+// document.
+func (r PageRange) Resolve(total int) (first, last int) {
+	resolve := func(n int) int {
+		if n < 0 {
+			return total + 1 + n
+		}
+		return n
+	}
+	return max(resolve(r.First), 1), min(resolve(r.Last), total)
+}
+
+// resolvePageRanges returns the distinct pages selected by ranges in ascending order.
+func resolvePageRanges(ranges []PageRange, total int) []int {
+	seen := make(map[int]bool)
+	pages := []int{}
+	for _, r := range ranges {
+		first, last := r.Resolve(total)
+		for page := first; page <= last; page++ {
+			if !seen[page] {
+				seen[page] = true
+				pages = append(pages, page)
+			}
+		}
+	}
+	sort.Ints(pages)
+	return pages
+}
+
+// pageRangeRequest carries PageConfig.Ranges from the config to the result.
+type pageRangeRequest struct {
+	spec   string
+	ranges []PageRange
+	// keepPages is set when the caller asked for ExtractionResult.Pages.
+	keepPages bool
+}
+
+// preparePageRanges validates PageConfig.Ranges and returns the config to pass to the
+// native library, with page extraction enabled so that the result can be split by
+// page. The returned request is nil when no ranges are configured.
+func preparePageRanges(config *ExtractionConfig) (*ExtractionConfig, *pageRangeRequest, error) {
+	if config == nil || config.Pages == nil || config.Pages.Ranges == nil {
+		return config, nil, nil
+	}
+	ranges, err := ParsePageRanges(*config.Pages.Ranges)
+	if err != nil {
+		return nil, nil, err
+	}
+	request := &pageRangeRequest{
+		spec:      *config.Pages.Ranges,
+		ranges:    ranges,
+		keepPages: config.Pages.ExtractPages != nil && *config.Pages.ExtractPages,
+	}
+	native := MergeConfigs(config, nil)
+	native.Pages.ExtractPages = BoolPtr(true)
+	return native, request, nil
+}
+
+// selectedPagesKey is the Metadata.Additional key under which the native PDF extractor
+// reports the pages it extracted for PageConfig.Ranges.
+const selectedPagesKey = "selected_pages"
+
+// apply reports the selected pages on result. Results the native library already
+// narrowed are left as they are; other results are narrowed here, and results without
+// page information are left whole and get no PageSelection.
+func (s *pageRangeRequest) apply(result *ExtractionResult) {
+	if s == nil || result == nil {
+		return
+	}
+	if pages, ok := nativeSelectedPages(result); ok {
+		result.PageSelection = &PageSelection{Ranges: s.spec, TotalPages: resultPageCount(result), Pages: pages}
+	} else if total := resultPageCount(result); total > 0 {
+		pages := resolvePageRanges(s.ranges, total)
+		result.PageSelection = &PageSelection{Ranges: s.spec, TotalPages: total, Pages: pages}
+		selectResultPages(result, pages)
+	}
+	if !s.keepPages {
+		result.Pages = nil
+	}
+}
+
+// nativeSelectedPages returns the pages the native library extracted for
+// PageConfig.Ranges, if it applied the selection itself.
+func nativeSelectedPages(result *ExtractionResult) ([]int, bool) {
+	pages, ok, err := GetAdditional[[]int](&result.Metadata, selectedPagesKey)
+	if !ok || err != nil {
+		return nil, false
+	}
+	if pages == nil {
+		pages = []int{}
+	}
+	return pages, true
+}
+
+func resultPageCount(result *ExtractionResult) int {
+	if result.Metadata.Pages != nil && result.Metadata.Pages.TotalCount > 0 {
+		return int(result.Metadata.Pages.TotalCount)
+	}
+	if pdf, ok := result.Metadata.PdfMetadata(); ok && pdf.PageCount != nil && *pdf.PageCount > 0 {
+		return *pdf.PageCount
+	}
+	total := 0
+	for _, page := range result.Pages {
+		total = max(total, int(page.PageNumber))
+	}
+	return total
+}
+
+// pageRun is a stretch of Content made of consecutive selected pages, copied verbatim
+// to the narrowed content at a shifted offset.
+type pageRun struct {
+	start, end          int
+	shift               int
+	firstPage, lastPage int
+}
+
+// selectResultPages keeps the parts of result that belong to pages. Consecutive
+// selected pages keep the text between them; separate runs are joined by a blank
+// line. Chunks are trimmed to the runs they overlap; a trimmed chunk loses its
+// embedding and token count. DjotContent is left unchanged.
+func selectResultPages(result *ExtractionResult, pages []int) {
+	selected := make(map[int]bool, len(pages))
+	for _, page := range pages {
+		selected[page] = true
+	}
+	keep := func(page *uint64) bool { return page == nil || selected[int(*page)] }
+
+	var (
+		content    strings.Builder
+		runs       []pageRun
+		boundaries []PageBoundary
+	)
+	prevSelected := false
+	for _, b := range result.pageBoundaries() {
+		if !selected[b.page] {
+			prevSelected = false
+			continue
+		}
+		if n := len(runs); prevSelected && b.start >= runs[n-1].end {
+			content.WriteString(result.Content[runs[n-1].end:b.end])
+			runs[n-1].end = b.end
+			runs[n-1].lastPage = b.page
+		} else {
+			if content.Len() > 0 {
+				content.WriteString("\n\n")
+			}
+			runs = append(runs, pageRun{start: b.start, end: b.end, shift: content.Len() - b.start, firstPage: b.page, lastPage: b.page})
+			content.WriteString(result.Content[b.start:b.end])
+		}
+		shift := runs[len(runs)-1].shift
+		boundaries = append(boundaries, PageBoundary{
+			ByteStart:  uint64(b.start + shift),
+			ByteEnd:    uint64(b.end + shift),
+			PageNumber: uint64(b.page),
+		})
+		prevSelected = true
+	}
+	if len(runs) == 0 {
+		var texts []string
+		for _, page := range result.Pages {
+			if selected[int(page.PageNumber)] {
+				texts = append(texts, page.Content)
+			}
+		}
+		content.WriteString(strings.Join(texts, "\n\n"))
+	}
+	original := result.Content
+	result.Content = content.String()
+
+	var chunks []Chunk
+	for _, chunk := range result.Chunks {
+		start, end := int(chunk.Metadata.ByteStart), int(chunk.Metadata.ByteEnd)
+		for _, run := range runs {
+			first, last := max(start, run.start), min(end, run.end)
+			if first >= last {
+				continue
+			}
+			piece := chunk
+			if first != start || last != end {
+				piece.Content = original[first:last]
+				piece.Embedding = nil
+				piece.Metadata.TokenCount = nil
+			}
+			piece.Metadata.ByteStart = uint64(first + run.shift)
+			piece.Metadata.ByteEnd = uint64(last + run.shift)
+			if page := piece.Metadata.FirstPage; page != nil && int(*page) < run.firstPage {
+				piece.Metadata.FirstPage = Uint64Ptr(uint64(run.firstPage))
+			}
+			if page := piece.Metadata.LastPage; page != nil && int(*page) > run.lastPage {
+				piece.Metadata.LastPage = Uint64Ptr(uint64(run.lastPage))
+			}
+			chunks = append(chunks, piece)
+		}
+	}
+	for i := range chunks {
+		chunks[i].Metadata.ChunkIndex = uint64(i)
+		chunks[i].Metadata.TotalChunks = uint64(len(chunks))
+	}
+	result.Chunks = chunks
+
+	var (
+		tables  []Table
+		sources [][]int
+	)
+	for i, table := range result.Tables {
+		tablePages := result.TableSourcePages(i)
+		for _, page := range tablePages {
+			if selected[page] {
+				tables = append(tables, table)
+				sources = append(sources, tablePages)
+				break
+			}
+		}
+	}
+	result.Tables = append(result.Tables[:0], tables...)
+	if _, ok := result.Metadata.Additional[TableSourcePagesKey]; ok {
+		if raw, err := json.Marshal(sources); err == nil {
+			result.Metadata.Additional[TableSourcePagesKey] = raw
+		}
+	}
+
+	images := result.Images[:0]
+	for _, image := range result.Images {
+		if keep(image.PageNumber) {
+			images = append(images, image)
+		}
+	}
+	result.Images = images
+
+	elements := result.Elements[:0]
+	for _, element := range result.Elements {
+		if keep(element.Metadata.PageNumber) {
+			elements = append(elements, element)
+		}
+	}
+	result.Elements = elements
+
+	pageContents := result.Pages[:0]
+	for _, page := range result.Pages {
+		if selected[int(page.PageNumber)] {
+			pageContents = append(pageContents, page)
+		}
+	}
+	result.Pages = pageContents
+
+	if structure := result.Metadata.Pages; structure != nil {
+		structure.Boundaries = boundaries
+		infos := structure.Pages[:0]
+		for _, info := range structure.Pages {
+			if selected[int(info.Number)] {
+				infos = append(infos, info)
+			}
+		}
+		structure.Pages = infos
+	}
+}
@@ -0,0 +1,251 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePageRanges(t *testing.T) {
+	ranges, err := ParsePageRanges(" 1-3, 10,-2 ,7-")
+	if err != nil {
+		t.Fatalf("ParsePageRanges() error = %v", err)
+	}
+	want := []PageRange{{1, 3}, {10, 10}, {-2, -1}, {7, -1}}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("ranges = %v, want %v", ranges, want)
+	}
+
+	for _, spec := range []string{"", " , ", "0", "3-1", "a-b", "1-2-3", "--1", "-0"} {
+		_, err := ParsePageRanges(spec)
+		var validation *ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("ParsePageRanges(%q) error = %v, want validation error", spec, err)
+		}
+	}
+}
+
+func TestResolvePageRanges(t *testing.T) {
+	cases := []struct {
+		spec  string
+		total int
+		want  []int
+	}{
+		{"1-3,10,-2", 12, []int{1, 2, 3, 10, 11, 12}},
+		{"1-3,10,-2", 4, []int{1, 2, 3, 4}},
+		{"-5", 3, []int{1, 2, 3}},
+		{"8-", 10, []int{8, 9, 10}},
+		{"20-30", 10, []int{}},
+		{"3,1,3", 5, []int{1, 3}},
+	}
+	for _, tc := range cases {
+		ranges, err := ParsePageRanges(tc.spec)
+		if err != nil {
+			t.Fatalf("ParsePageRanges(%q) error = %v", tc.spec, err)
+		}
+		if got := resolvePageRanges(ranges, tc.total); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("resolvePageRanges(%q, %d) = %v, want %v", tc.spec, tc.total, got, tc.want)
+		}
+	}
+}
+
+func TestPreparePageRanges(t *testing.T) {
+	config := &ExtractionConfig{Pages: NewPageConfig(WithPageRanges("2-3"))}
+	native, request, err := preparePageRanges(config)
+	if err != nil {
+		t.Fatalf("preparePageRanges() error = %v", err)
+	}
+	if request == nil || request.keepPages || request.spec != "2-3" {
+		t.Errorf("request = %+v", request)
+	}
+	if native.Pages.ExtractPages == nil || !*native.Pages.ExtractPages {
+		t.Error("page extraction must be enabled for the native call")
+	}
+	if config.Pages.ExtractPages != nil {
+		t.Error("config was modified")
+	}
+
+	if native, request, err := preparePageRanges(nil); native != nil || request != nil || err != nil {
+		t.Errorf("preparePageRanges(nil) = %v, %v, %v", native, request, err)
+	}
+	if _, _, err := preparePageRanges(&ExtractionConfig{Pages: NewPageConfig(WithPageRanges("x"))}); err == nil {
+		t.Error("expected an error for invalid ranges")
+	}
+}
+
+// pagedResult builds a result of four pages with content "page N body", one chunk,
+// image, element and table per page.
+func pagedResult() *ExtractionResult {
+	result := &ExtractionResult{
+		Metadata: Metadata{Pages: &PageStructure{TotalCount: 4, UnitType: PageUnitTypePage}},
+	}
+	var content strings.Builder
+	for n := 1; n <= 4; n++ {
+		if n > 1 {
+			content.WriteString("\n\n")
+		}
+		text := "page " + string(rune('0'+n)) + " body"
+		start := content.Len()
+		content.WriteString(text)
+		page := uint64(n)
+		result.Metadata.Pages.Boundaries = append(result.Metadata.Pages.Boundaries, PageBoundary{ByteStart: uint64(start), ByteEnd: uint64(content.Len()), PageNumber: page})
+		result.Metadata.Pages.Pages = append(result.Metadata.Pages.Pages, PageInfo{Number: page})
+		result.Pages = append(result.Pages, PageContent{PageNumber: page, Content: text})
+		result.Chunks = append(result.Chunks, Chunk{Content: text, Metadata: ChunkMetadata{ByteStart: uint64(start), ByteEnd: uint64(content.Len()), ChunkIndex: page - 1, TotalChunks: 4}})
+		result.Images = append(result.Images, ExtractedImage{ImageIndex: page, PageNumber: &page})
+		result.Elements = append(result.Elements, Element{Text: text, Metadata: ElementMetadata{PageNumber: &page}})
+		result.Tables = append(result.Tables, Table{PageNumber: n})
+	}
+	result.Content = content.String()
+	return result
+}
+
+func TestPageRangeRequestApply(t *testing.T) {
+	result := pagedResult()
+	request := &pageRangeRequest{spec: "2-3,-1", ranges: []PageRange{{2, 3}, {-1, -1}}, keepPages: true}
+	request.apply(result)
+
+	wantSelection := &PageSelection{Ranges: "2-3,-1", TotalPages: 4, Pages: []int{2, 3, 4}}
+	if !reflect.DeepEqual(result.PageSelection, wantSelection) {
+		t.Errorf("PageSelection = %+v, want %+v", result.PageSelection, wantSelection)
+	}
+	if want := "page 2 body\n\npage 3 body\n\npage 4 body"; result.Content != want {
+		t.Errorf("Content = %q, want %q", result.Content, want)
+	}
+	if len(result.Pages) != 3 || len(result.Images) != 3 || len(result.Elements) != 3 || len(result.Tables) != 3 || len(result.Metadata.Pages.Pages) != 3 {
+		t.Errorf("pages/images/elements/tables/infos = %d/%d/%d/%d/%d, want 3 each",
+			len(result.Pages), len(result.Images), len(result.Elements), len(result.Tables), len(result.Metadata.Pages.Pages))
+	}
+	for n := 2; n <= 4; n++ {
+		text, ok := result.PageText(n)
+		if want := "page " + string(rune('0'+n)) + " body"; !ok || text != want {
+			t.Errorf("PageText(%d) = %q, %v, want %q", n, text, ok, want)
+		}
+	}
+	if len(result.Chunks) != 3 {
+		t.Fatalf("chunks = %d, want 3", len(result.Chunks))
+	}
+	for i, chunk := range result.Chunks {
+		if got := result.Content[chunk.Metadata.ByteStart:chunk.Metadata.ByteEnd]; got != chunk.Content {
+			t.Errorf("chunk %d offsets select %q, want %q", i, got, chunk.Content)
+		}
+		if chunk.Metadata.ChunkIndex != uint64(i) || chunk.Metadata.TotalChunks != 3 {
+			t.Errorf("chunk %d metadata = %+v", i, chunk.Metadata)
+		}
+	}
+}
+
+func TestPageRangeRequestApplyJoinsSeparateRuns(t *testing.T) {
+	result := pagedResult()
+	request := &pageRangeRequest{spec: "1,3", ranges: []PageRange{{1, 1}, {3, 3}}}
+	request.apply(result)
+
+	if want := "page 1 body\n\npage 3 body"; result.Content != want {
+		t.Errorf("Content = %q, want %q", result.Content, want)
+	}
+	if result.Pages != nil {
+		t.Error("Pages must be dropped when the caller did not ask for them")
+	}
+	boundaries := result.Metadata.Pages.Boundaries
+	if len(boundaries) != 2 || boundaries[1].PageNumber != 3 || result.Content[boundaries[1].ByteStart:boundaries[1].ByteEnd] != "page 3 body" {
+		t.Errorf("boundaries = %+v", boundaries)
+	}
+	if result.Metadata.Pages.TotalCount != 4 {
+		t.Errorf("TotalCount = %d, want the document's page count", result.Metadata.Pages.TotalCount)
+	}
+}
+
+func TestPageRangeRequestApplyTrimsCrossingChunks(t *testing.T) {
+	result := pagedResult()
+	// One chunk covering pages 2 and 3, with page 3 not selected.
+	tokens := uint64(6)
+	start, end := result.Metadata.Pages.Boundaries[1].ByteStart, result.Metadata.Pages.Boundaries[2].ByteEnd
+	result.Chunks = []Chunk{{
+		Content:   result.Content[start:end],
+		Embedding: []float32{1},
+		Metadata:  ChunkMetadata{ByteStart: start, ByteEnd: end, TokenCount: &tokens, FirstPage: Uint64Ptr(2), LastPage: Uint64Ptr(3), TotalChunks: 1},
+	}}
+	(&pageRangeRequest{spec: "1-2", ranges: []PageRange{{1, 2}}}).apply(result)
+
+	if len(result.Chunks) != 1 {
+		t.Fatalf("chunks = %d, want the chunk trimmed to page 2", len(result.Chunks))
+	}
+	chunk := result.Chunks[0]
+	if chunk.Content != "page 2 body" || result.Content[chunk.Metadata.ByteStart:chunk.Metadata.ByteEnd] != chunk.Content {
+		t.Errorf("chunk = %q at [%d, %d)", chunk.Content, chunk.Metadata.ByteStart, chunk.Metadata.ByteEnd)
+	}
+	if *chunk.Metadata.FirstPage != 2 || *chunk.Metadata.LastPage != 2 {
+		t.Errorf("chunk pages = %d-%d, want 2-2", *chunk.Metadata.FirstPage, *chunk.Metadata.LastPage)
+	}
+	if chunk.Embedding != nil || chunk.Metadata.TokenCount != nil {
+		t.Error("a trimmed chunk must not keep its embedding or token count")
+	}
+}
+
+func TestPageRangeRequestApplyNativeSelection(t *testing.T) {
+	result := &ExtractionResult{
+		Content: "page 2 body",
+		Metadata: Metadata{
+			Pages:      &PageStructure{TotalCount: 4, Boundaries: []PageBoundary{{ByteStart: 0, ByteEnd: 11, PageNumber: 2}}},
+			Additional: map[string]json.RawMessage{selectedPagesKey: json.RawMessage(`[2]`)},
+		},
+	}
+	(&pageRangeRequest{spec: "2", ranges: []PageRange{{2, 2}}}).apply(result)
+
+	wantSelection := &PageSelection{Ranges: "2", TotalPages: 4, Pages: []int{2}}
+	if !reflect.DeepEqual(result.PageSelection, wantSelection) {
+		t.Errorf("PageSelection = %+v, want %+v", result.PageSelection, wantSelection)
+	}
+	if result.Content != "page 2 body" || len(result.Metadata.Pages.Boundaries) != 1 {
+		t.Errorf("result = %+v, want it unchanged", result)
+	}
+}
+
+func TestPageRangeRequestApplyWithoutPages(t *testing.T) {
+	result := &ExtractionResult{Content: "plain text"}
+	(&pageRangeRequest{spec: "1", ranges: []PageRange{{1, 1}}}).apply(result)
+	if result.Content != "plain text" || result.PageSelection != nil {
+		t.Errorf("result = %+v, want it unchanged", result)
+	}
+}
+
+func TestExtractFileSyncPageRanges(t *testing.T) {
+	path := getTestFilePath("pdf/multi_page.pdf")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	result, err := ExtractFileSync(path, &ExtractionConfig{Pages: NewPageConfig(WithPageRanges("-1"))})
+	if err != nil {
+		t.Fatalf("ExtractFileSync() error = %v", err)
+	}
+	selection := result.PageSelection
+	if selection == nil || len(selection.Pages) != 1 || selection.Pages[0] != selection.TotalPages {
+		t.Fatalf("PageSelection = %+v, want the last page", selection)
+	}
+	if result.Pages != nil {
+		t.Error("Pages must not be returned unless requested")
+	}
+}
+
+func TestExtractFileSyncPageRangesPastEnd(t *testing.T) {
+	path := getTestFilePath("pdf/multi_page.pdf")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	for _, spec := range []string{"100", "100-"} {
+		result, err := ExtractFileSync(path, &ExtractionConfig{Pages: NewPageConfig(WithPageRanges(spec))})
+		if err != nil {
+			t.Fatalf("ExtractFileSync(%q) error = %v", spec, err)
+		}
+		selection := result.PageSelection
+		if selection == nil || len(selection.Pages) != 0 || selection.TotalPages < 2 {
+			t.Errorf("PageSelection(%q) = %+v, want no pages of the whole document", spec, selection)
+		}
+		if strings.TrimSpace(result.Content) != "" {
+			t.Errorf("Content(%q) = %q, want no text", spec, result.Content)
+		}
+	}
+}
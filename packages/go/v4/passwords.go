@@ -0,0 +1,244 @@
+package kreuzberg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MaxPasswordAttempts bounds the number of passwords requested from a PasswordProvider
+// for one document.
+const MaxPasswordAttempts = 100
+
+// PasswordRequest identifies the document and attempt a password is requested for.
+type PasswordRequest struct {
+	// Path is the document's file path, or empty for in-memory documents.
+	Path string
+	// SHA256 is the hex-encoded SHA-256 digest of the document's content.
+	SHA256 string
+	// Attempt numbers the request, starting at 1.
+	Attempt int
+	// LastError is the error returned by the previous attempt.
+	LastError error
+}
+
+// PasswordProvider supplies passwords for encrypted documents, one attempt at a time,
+// so that each document only sees its own secrets.
+type PasswordProvider interface {
+	// Password returns the password to try for req. ok is false when the provider has
+	// no further password for the document; a non-nil error aborts the extraction.
+	Password(req PasswordRequest) (password string, ok bool, err error)
+}
+
+// PasswordProviderFunc adapts a function to a PasswordProvider.
+type PasswordProviderFunc func(req PasswordRequest) (string, bool, error)
+
+// Password calls f(req).
+func (f PasswordProviderFunc) Password(req PasswordRequest) (string, bool, error) {
+	return f(req)
+}
+
+// MapPasswordProvider serves passwords keyed by document path or by SHA-256 digest.
+// The passwords for a key are tried in order; passwords stored under the path come
+// before those stored under the digest.
+type MapPasswordProvider map[string][]string
+
+// Password implements PasswordProvider.
+func (p MapPasswordProvider) Password(req PasswordRequest) (string, bool, error) {
+	var candidates []string
+	if req.Path != "" {
+		candidates = append(candidates, p[req.Path]...)
+	}
+	if req.SHA256 != "" {
+		candidates = append(candidates, p[req.SHA256]...)
+	}
+	if req.Attempt < 1 || req.Attempt > len(candidates) {
+		return "", false, nil
+	}
+	return candidates[req.Attempt-1], true, nil
+}
+
+// LoadPasswordFile reads a MapPasswordProvider from a JSON file mapping document
+// paths or SHA-256 digests to a password or a list of passwords:
+//
+//	{"contracts/nda.pdf": "secret", "9f86d0...": ["first", "second"]}
+func LoadPasswordFile(path string) (MapPasswordProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newIOErrorWithContext(fmt.Sprintf("failed to read password file %s", path), err, ErrorCodeIo, nil)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, newSerializationErrorWithContext(fmt.Sprintf("failed to decode password file %s", path), err, ErrorCodeValidation, nil)
+	}
+	provider := make(MapPasswordProvider, len(raw))
+	for key, value := range raw {
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			provider[key] = []string{single}
+			continue
+		}
+		var list []string
+		if err := json.Unmarshal(value, &list); err != nil {
+			return nil, newValidationErrorWithContext(fmt.Sprintf("password file %s: entry %q must be a string or a list of strings", path, key), err, ErrorCodeValidation, nil)
+		}
+		provider[key] = list
+	}
+	return provider, nil
+}
+
+// DecryptionInfo reports how a document was opened by ExtractFileWithPasswords or
+// ExtractBytesWithPasswords.
+type DecryptionInfo struct {
+	// Encrypted is set when the document could not be opened without a password.
+	Encrypted bool
+	// Decrypted is set when a password from the provider opened the document.
+	Decrypted bool
+	// Attempt is the number of the attempt whose password worked, or 0.
+	Attempt int
+	// Attempts counts the passwords tried.
+	Attempts int
+}
+
+// ExtractFileWithPasswords extracts the file at path, asking provider for passwords
+// while the file cannot be opened because it is encrypted.
+//
+// The document is first extracted without a password; any PdfConfig.Passwords in
+// config are ignored. Each further attempt uses a single password from provider. The
+// returned result's Decryption field reports the outcome. When the provider runs out
+// of passwords, the error is an *EncryptedDocumentError, for which IsEncrypted is true.
+func ExtractFileWithPasswords(path string, config *ExtractionConfig, provider PasswordProvider) (*ExtractionResult, error) {
+	if path == "" {
+		return nil, newValidationErrorWithContext("path is required", nil, ErrorCodeValidation, nil)
+	}
+	request := PasswordRequest{Path: path}
+	return extractWithPasswords(config, provider, &request,
+		func() (string, error) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return "", newIOErrorWithContext(fmt.Sprintf("failed to read %s", path), err, ErrorCodeIo, nil)
+			}
+			return contentDigest(data), nil
+		},
+		func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+			return ExtractFileSync(path, cfg)
+		})
+}
+
+// ExtractBytesWithPasswords is ExtractFileWithPasswords for in-memory documents.
+func ExtractBytesWithPasswords(data []byte, mimeType string, config *ExtractionConfig, provider PasswordProvider) (*ExtractionResult, error) {
+	var request PasswordRequest
+	return extractWithPasswords(config, provider, &request,
+		func() (string, error) { return contentDigest(data), nil },
+		func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+			return ExtractBytesSync(data, mimeType, cfg)
+		})
+}
+
+func extractWithPasswords(
+	config *ExtractionConfig,
+	provider PasswordProvider,
+	request *PasswordRequest,
+	digest func() (string, error),
+	extract func(*ExtractionConfig) (*ExtractionResult, error),
+) (*ExtractionResult, error) {
+	if provider == nil {
+		return nil, newValidationErrorWithContext("password provider cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	withPassword := func(passwords []string) *ExtractionConfig {
+		cfg := MergeConfigs(config, nil)
+		if cfg.PdfOptions == nil {
+			cfg.PdfOptions = &PdfConfig{}
+		}
+		cfg.PdfOptions.Passwords = passwords
+		return cfg
+	}
+
+	result, err := extract(withPassword(nil))
+	if err == nil {
+		result.Decryption = &DecryptionInfo{}
+		return result, nil
+	}
+	if !IsEncrypted(err) {
+		return nil, err
+	}
+
+	if request.SHA256, err = digest(); err != nil {
+		return nil, err
+	}
+	lastErr := err
+	attempts := 0
+	for attempts < MaxPasswordAttempts {
+		request.Attempt = attempts + 1
+		request.LastError = lastErr
+		password, ok, providerErr := provider.Password(*request)
+		if providerErr != nil {
+			return nil, newValidationErrorWithContext("password provider failed", providerErr, ErrorCodeValidation, nil)
+		}
+		if !ok {
+			break
+		}
+		attempts++
+		result, err = extract(withPassword([]string{password}))
+		if err == nil {
+			result.Decryption = &DecryptionInfo{Encrypted: true, Decrypted: true, Attempt: attempts, Attempts: attempts}
+			return result, nil
+		}
+		if !IsEncrypted(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, newEncryptedDocumentError(request.Path, attempts, lastErr)
+}
+
+func contentDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// EncryptedDocumentError reports a password-protected document that could not be
+// opened with any of the passwords tried.
+type EncryptedDocumentError struct {
+	baseError
+	// Path is the document's file path, or empty for in-memory documents.
+	Path string
+	// Attempts counts the passwords tried.
+	Attempts int
+}
+
+func newEncryptedDocumentError(path string, attempts int, cause error) *EncryptedDocumentError {
+	subject := "document"
+	if path != "" {
+		subject = path
+	}
+	message := fmt.Sprintf("%s is encrypted and no password was accepted (%d tried)", subject, attempts)
+	return &EncryptedDocumentError{
+		baseError: makeBaseError(ErrorKindParsing, message, cause, ErrorCodeParsing, nil),
+		Path:      path,
+		Attempts:  attempts,
+	}
+}
+
+// IsEncrypted reports whether err means that a document is password-protected and
+// could not be opened: either an *EncryptedDocumentError or a native error for a
+// missing or wrong password.
+func IsEncrypted(err error) bool {
+	if err == nil {
+		return false
+	}
+	var encrypted *EncryptedDocumentError
+	if errors.As(err, &encrypted) {
+		return true
+	}
+	var parsing *ParsingError
+	if !errors.As(err, &parsing) {
+		return false
+	}
+	message := strings.ToLower(parsing.Error())
+	return strings.Contains(message, "password-protected") || strings.Contains(message, "invalid password")
+}
@@ -0,0 +1,232 @@
+package kreuzberg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeEncryptedExtract behaves like the native library on a PDF that opens only with
+// password, recording the passwords it was given.
+func fakeEncryptedExtract(password string, seen *[][]string) func(*ExtractionConfig) (*ExtractionResult, error) {
+	return func(cfg *ExtractionConfig) (*ExtractionResult, error) {
+		var passwords []string
+		if cfg.PdfOptions != nil {
+			passwords = cfg.PdfOptions.Passwords
+		}
+		*seen = append(*seen, passwords)
+		switch {
+		case len(passwords) == 0:
+			return nil, newParsingErrorWithContext("PDF error: PDF is password-protected", nil, ErrorCodeParsing, nil)
+		case passwords[0] != password:
+			return nil, newParsingErrorWithContext("PDF error: Invalid password provided", nil, ErrorCodeParsing, nil)
+		}
+		return &ExtractionResult{Content: "secret text", MimeType: "application/pdf"}, nil
+	}
+}
+
+func TestExtractWithPasswordsTriesProviderInOrder(t *testing.T) {
+	var seen [][]string
+	var requests []PasswordRequest
+	provider := PasswordProviderFunc(func(req PasswordRequest) (string, bool, error) {
+		requests = append(requests, req)
+		return MapPasswordProvider{"doc.pdf": {"wrong", "right", "unused"}}.Password(req)
+	})
+	config := &ExtractionConfig{PdfOptions: &PdfConfig{Passwords: []string{"static"}}}
+
+	request := PasswordRequest{Path: "doc.pdf"}
+	result, err := extractWithPasswords(config, provider, &request,
+		func() (string, error) { return "abc123", nil },
+		fakeEncryptedExtract("right", &seen))
+	if err != nil {
+		t.Fatalf("extractWithPasswords() error = %v", err)
+	}
+	want := DecryptionInfo{Encrypted: true, Decrypted: true, Attempt: 2, Attempts: 2}
+	if result.Decryption == nil || *result.Decryption != want {
+		t.Errorf("Decryption = %+v, want %+v", result.Decryption, want)
+	}
+	if len(seen) != 3 || seen[0] != nil || seen[1][0] != "wrong" || seen[2][0] != "right" {
+		t.Errorf("passwords tried = %v; static passwords must not be tried", seen)
+	}
+	if len(requests) != 2 || requests[0].Attempt != 1 || requests[1].Attempt != 2 || requests[1].SHA256 != "abc123" || requests[1].Path != "doc.pdf" {
+		t.Errorf("requests = %+v", requests)
+	}
+	if !IsEncrypted(requests[1].LastError) {
+		t.Errorf("LastError = %v, want the previous password error", requests[1].LastError)
+	}
+	if config.PdfOptions.Passwords[0] != "static" {
+		t.Error("config was modified")
+	}
+}
+
+func TestExtractWithPasswordsReportsEncryptedError(t *testing.T) {
+	var seen [][]string
+	request := PasswordRequest{Path: "doc.pdf"}
+	_, err := extractWithPasswords(nil, MapPasswordProvider{"doc.pdf": {"a", "b"}}, &request,
+		func() (string, error) { return "abc123", nil },
+		fakeEncryptedExtract("right", &seen))
+	if !IsEncrypted(err) {
+		t.Fatalf("error = %v, want encrypted", err)
+	}
+	var encrypted *EncryptedDocumentError
+	if !errors.As(err, &encrypted) {
+		t.Fatalf("error = %T, want *EncryptedDocumentError", err)
+	}
+	if encrypted.Attempts != 2 || encrypted.Path != "doc.pdf" || encrypted.Kind() != ErrorKindParsing {
+		t.Errorf("error = %+v", encrypted)
+	}
+}
+
+func TestExtractWithPasswordsSkipsProviderForPlainDocuments(t *testing.T) {
+	provider := PasswordProviderFunc(func(PasswordRequest) (string, bool, error) {
+		t.Fatal("provider must not be asked for an unencrypted document")
+		return "", false, nil
+	})
+	var request PasswordRequest
+	result, err := extractWithPasswords(nil, provider, &request,
+		func() (string, error) { t.Fatal("digest computed needlessly"); return "", nil },
+		func(*ExtractionConfig) (*ExtractionResult, error) { return &ExtractionResult{Content: "plain"}, nil })
+	if err != nil {
+		t.Fatalf("extractWithPasswords() error = %v", err)
+	}
+	if result.Decryption == nil || result.Decryption.Encrypted || result.Decryption.Attempts != 0 {
+		t.Errorf("Decryption = %+v", result.Decryption)
+	}
+
+	providerErr := errors.New("vault unavailable")
+	failing := PasswordProviderFunc(func(PasswordRequest) (string, bool, error) { return "", false, providerErr })
+	var seen [][]string
+	_, err = extractWithPasswords(nil, failing, &request,
+		func() (string, error) { return "", nil },
+		fakeEncryptedExtract("right", &seen))
+	if !errors.Is(err, providerErr) {
+		t.Errorf("error = %v, want provider error", err)
+	}
+}
+
+func TestMapPasswordProvider(t *testing.T) {
+	provider := MapPasswordProvider{"a.pdf": {"p1"}, "deadbeef": {"h1", "h2"}}
+	var got []string
+	for attempt := 1; ; attempt++ {
+		password, ok, err := provider.Password(PasswordRequest{Path: "a.pdf", SHA256: "deadbeef", Attempt: attempt})
+		if err != nil {
+			t.Fatalf("Password() error = %v", err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, password)
+	}
+	if len(got) != 3 || got[0] != "p1" || got[1] != "h1" || got[2] != "h2" {
+		t.Errorf("passwords = %v", got)
+	}
+	if _, ok, _ := provider.Password(PasswordRequest{Path: "other.pdf", Attempt: 1}); ok {
+		t.Error("unexpected password for an unknown document")
+	}
+}
+
+func TestLoadPasswordFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "passwords.json")
+	if err := os.WriteFile(path, []byte(`{"nda.pdf": "secret", "deadbeef": ["one", "two"]}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	provider, err := LoadPasswordFile(path)
+	if err != nil {
+		t.Fatalf("LoadPasswordFile() error = %v", err)
+	}
+	if len(provider["nda.pdf"]) != 1 || provider["nda.pdf"][0] != "secret" || len(provider["deadbeef"]) != 2 {
+		t.Errorf("provider = %v", provider)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"nda.pdf": 42}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var validation *ValidationError
+	if _, err := LoadPasswordFile(path); !errors.As(err, &validation) {
+		t.Errorf("error = %v, want validation error", err)
+	}
+}
+
+func TestIsEncrypted(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{newParsingErrorWithContext("PDF error: PDF is password-protected", nil, ErrorCodeParsing, nil), true},
+		{newParsingErrorWithContext("Invalid password provided", nil, ErrorCodeParsing, nil), true},
+		{newParsingErrorWithContext("Invalid PDF: bad xref", nil, ErrorCodeParsing, nil), false},
+		{newValidationErrorWithContext("password is required", nil, ErrorCodeValidation, nil), false},
+		{newEncryptedDocumentError("", 0, nil), true},
+	}
+	for _, tc := range cases {
+		if got := IsEncrypted(tc.err); got != tc.want {
+			t.Errorf("IsEncrypted(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+// encryptedPDFFixture is an RC4-encrypted PDF whose user password is "kreuzberg".
+const encryptedPDFFixture = "pdf/encrypted_user_password.pdf"
+
+func TestExtractFileWithPasswordsEncryptedPDF(t *testing.T) {
+	path := getTestFilePath(encryptedPDFFixture)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	if _, err := ExtractFileSync(path, nil); !IsEncrypted(err) {
+		t.Fatalf("ExtractFileSync() without a password error = %v, want an encryption error", err)
+	}
+
+	_, err := ExtractFileWithPasswords(path, nil, MapPasswordProvider{path: {"definitely-wrong"}})
+	var encrypted *EncryptedDocumentError
+	if !errors.As(err, &encrypted) {
+		t.Fatalf("error = %v, want *EncryptedDocumentError", err)
+	}
+	if encrypted.Attempts != 1 || encrypted.Path != path {
+		t.Errorf("error = %+v", encrypted)
+	}
+}
+
+func TestExtractFileWithPasswordsDecryptsPDF(t *testing.T) {
+	path := getTestFilePath(encryptedPDFFixture)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+
+	result, err := ExtractFileWithPasswords(path, nil, MapPasswordProvider{path: {"wrong", "kreuzberg"}})
+	if err != nil {
+		t.Fatalf("ExtractFileWithPasswords() error = %v", err)
+	}
+	want := DecryptionInfo{Encrypted: true, Decrypted: true, Attempt: 2, Attempts: 2}
+	if result.Decryption == nil || *result.Decryption != want {
+		t.Errorf("Decryption = %+v, want %+v", result.Decryption, want)
+	}
+	if !strings.Contains(result.Content, "Encrypted fixture text") {
+		t.Errorf("Content = %q, want the decrypted page text", result.Content)
+	}
+}
+
+func TestExtractFileSyncUsesConfiguredPDFPasswords(t *testing.T) {
+	path := getTestFilePath(encryptedPDFFixture)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+
+	config := &ExtractionConfig{PdfOptions: &PdfConfig{Passwords: []string{"wrong", "kreuzberg"}}}
+	result, err := ExtractFileSync(path, config)
+	if err != nil {
+		t.Fatalf("ExtractFileSync() error = %v", err)
+	}
+	if !strings.Contains(result.Content, "Encrypted fixture text") {
+		t.Errorf("Content = %q, want the decrypted page text", result.Content)
+	}
+
+	config.PdfOptions.Passwords = []string{"wrong"}
+	if _, err := ExtractFileSync(path, config); !IsEncrypted(err) {
+		t.Errorf("ExtractFileSync() with a wrong password error = %v, want an encryption error", err)
+	}
+}
@@ -0,0 +1,281 @@
+package kreuzberg
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in PII types reported by the default detectors.
+const (
+	PIITypeEmail      = "email"
+	PIITypePhone      = "phone"
+	PIITypeIBAN       = "iban"
+	PIITypeCreditCard = "credit_card"
+	PIITypeNationalID = "national_id"
+)
+
+// PIIMatch is a span of sensitive data found in a text, as byte offsets [Start, End).
+type PIIMatch struct {
+	Type  string `json:"type"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// PIIDetector finds one kind of sensitive data in text.
+type PIIDetector interface {
+	// Type names the kind of data the detector finds, e.g. PIITypeEmail.
+	Type() string
+	// Detect returns the matches in text in ascending order. The Type of the returned
+	// matches is ignored; FindPII sets it from Type.
+	Detect(text string) []PIIMatch
+}
+
+type regexPIIDetector struct {
+	piiType  string
+	pattern  *regexp.Regexp
+	validate func(string) bool
+}
+
+// NewRegexPIIDetector returns a detector reporting the matches of pattern as piiType.
+//
+// Matches directly preceded or followed by an ASCII letter or digit are ignored. When
+// validate is non-nil, a match is kept only if validate accepts it; a rejected match
+// is retried with trailing space-separated groups removed, so that e.g. an IBAN
+// followed by a currency code is still found. If that fails too, the pattern is matched
+// again from each following group, so that e.g. a card number preceded by a reference
+// number ("Ref 2024 4111 1111 1111 1111") is still found.
+func NewRegexPIIDetector(piiType, pattern string, validate func(match string) bool) (PIIDetector, error) {
+	if strings.TrimSpace(piiType) == "" {
+		return nil, newValidationErrorWithContext("PII detector type must not be empty", nil, ErrorCodeValidation, nil)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("invalid PII pattern for %q", piiType), err, ErrorCodeValidation, nil)
+	}
+	return &regexPIIDetector{piiType: piiType, pattern: re, validate: validate}, nil
+}
+
+func (d *regexPIIDetector) Type() string { return d.piiType }
+
+func (d *regexPIIDetector) Detect(text string) []PIIMatch {
+	var matches []PIIMatch
+	covered := 0
+	for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+		if loc[0] < covered {
+			continue
+		}
+		if start, end, ok := d.accept(text, loc[0], loc[1]); ok {
+			matches = append(matches, PIIMatch{Type: d.piiType, Start: start, End: end})
+			covered = end
+		}
+	}
+	return matches
+}
+
+// accept returns the span of the match text[start:end] to report. A rejected match that
+// does not start inside a word is retried from the start of each following
+// space-separated group, re-matching the pattern there since the match may have
+// stopped short of the value.
+func (d *regexPIIDetector) accept(text string, start, end int) (int, int, bool) {
+	if start > 0 && isASCIIAlnum(text[start-1]) {
+		return 0, 0, false
+	}
+	for start < end {
+		if e := d.validEnd(text, start, end); e >= 0 && (e == len(text) || !isASCIIAlnum(text[e])) {
+			return start, e, true
+		}
+		if d.validate == nil {
+			return 0, 0, false
+		}
+		cut := strings.IndexAny(text[start:end], " \t")
+		if cut < 0 {
+			return 0, 0, false
+		}
+		next := start + cut
+		for next < end && (text[next] == ' ' || text[next] == '\t') {
+			next++
+		}
+		loc := d.pattern.FindStringIndex(text[next:])
+		if loc == nil || loc[0] != 0 || loc[1] == 0 {
+			return 0, 0, false
+		}
+		start, end = next, next+loc[1]
+	}
+	return 0, 0, false
+}
+
+// validEnd returns the end of the longest prefix of text[start:end] accepted by the
+// validator that ends at a group boundary, or -1.
+func (d *regexPIIDetector) validEnd(text string, start, end int) int {
+	if d.validate == nil {
+		return end
+	}
+	for end > start {
+		if d.validate(text[start:end]) {
+			return end
+		}
+		cut := strings.LastIndexAny(text[start:end], " \t")
+		if cut <= 0 {
+			return -1
+		}
+		end = start + len(strings.TrimRight(text[start:start+cut], " \t"))
+	}
+	return -1
+}
+
+func isASCIIAlnum(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+func mustRegexPIIDetector(piiType, pattern string, validate func(string) bool) PIIDetector {
+	detector, err := NewRegexPIIDetector(piiType, pattern, validate)
+	if err != nil {
+		panic(err)
+	}
+	return detector
+}
+
+var (
+	emailDetector = mustRegexPIIDetector(PIITypeEmail,
+		`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`, nil)
+	ibanDetector = mustRegexPIIDetector(PIITypeIBAN,
+		`[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}`, ValidIBAN)
+	creditCardDetector = mustRegexPIIDetector(PIITypeCreditCard,
+		`\d(?:[ -]?\d){12,18}`, func(match string) bool { return ValidLuhn(match) })
+	nationalIDDetector = mustRegexPIIDetector(PIITypeNationalID,
+		`\d{3}-\d{2}-\d{4}`, validSSN)
+	phoneDetector = mustRegexPIIDetector(PIITypePhone,
+		`(?:\+\d{1,3}[ .\-]?)?(?:\(\d{1,5}\)[ .\-]?)?\d(?:[ .\-]?\d){6,14}`, validPhone)
+)
+
+// EmailDetector returns the built-in detector for email addresses.
+func EmailDetector() PIIDetector { return emailDetector }
+
+// IBANDetector returns the built-in detector for IBANs, validated with the ISO 13616
+// mod-97 checksum. IBANs may be written compactly or in groups of four.
+func IBANDetector() PIIDetector { return ibanDetector }
+
+// CreditCardDetector returns the built-in detector for payment card numbers of 13 to 19
+// digits, optionally grouped by spaces or dashes, validated with the Luhn checksum.
+func CreditCardDetector() PIIDetector { return creditCardDetector }
+
+// NationalIDDetector returns the built-in detector for national identification
+// numbers in the US Social Security number format (AAA-GG-SSSS), rejecting numbers
+// that are never issued. Other national formats can be added with NewRegexPIIDetector.
+func NationalIDDetector() PIIDetector { return nationalIDDetector }
+
+// PhoneDetector returns the built-in detector for phone numbers of 8 to 15 digits,
+// optionally with a "+" country code, an area code in parentheses and space, dot or
+// dash separators. Dates such as 2024-01-31 are not reported.
+func PhoneDetector() PIIDetector { return phoneDetector }
+
+// DefaultPIIDetectors returns the built-in detectors in precedence order: email, IBAN,
+// credit card, national ID and phone.
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{emailDetector, ibanDetector, creditCardDetector, nationalIDDetector, phoneDetector}
+}
+
+// FindPII runs detectors over text and returns the matches sorted by offset. When
+// matches overlap, the one from the detector listed first wins. A nil detectors slice
+// uses DefaultPIIDetectors.
+func FindPII(text string, detectors []PIIDetector) []PIIMatch {
+	if detectors == nil {
+		detectors = DefaultPIIDetectors()
+	}
+	var accepted []PIIMatch
+	for _, detector := range detectors {
+		if detector == nil {
+			continue
+		}
+		piiType := detector.Type()
+		for _, m := range detector.Detect(text) {
+			if m.Start < 0 || m.End > len(text) || m.Start >= m.End {
+				continue
+			}
+			i := sort.Search(len(accepted), func(i int) bool { return accepted[i].End > m.Start })
+			if i < len(accepted) && accepted[i].Start < m.End {
+				continue
+			}
+			m.Type = piiType
+			accepted = append(accepted, PIIMatch{})
+			copy(accepted[i+1:], accepted[i:])
+			accepted[i] = m
+		}
+	}
+	return accepted
+}
+
+// ValidLuhn reports whether the digits of s, ignoring spaces and dashes, form a 13 to
+// 19 digit number with a valid Luhn check digit.
+func ValidLuhn(s string) bool {
+	digits := stripPIISeparators(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		c := digits[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// ValidIBAN reports whether s, ignoring spaces, is an IBAN with a valid mod-97 checksum.
+func ValidIBAN(s string) bool {
+	iban := strings.ToUpper(stripPIISeparators(s))
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	var numeric strings.Builder
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			numeric.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			fmt.Fprintf(&numeric, "%d", c-'A'+10)
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func validSSN(s string) bool {
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+var phoneDatePattern = regexp.MustCompile(`^\d{4}[-./]\d{1,2}[-./]\d{1,2}$|^\d{1,2}[-./]\d{1,2}[-./]\d{4}$`)
+
+func validPhone(s string) bool {
+	digits := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits++
+		}
+	}
+	return digits >= 8 && digits <= 15 && !phoneDatePattern.MatchString(s)
+}
+
+func stripPIISeparators(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '\t' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,88 @@
+package kreuzberg_test
+
+import (
+	"reflect"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func piiFound(text string, detectors []kreuzberg.PIIDetector) []string {
+	var found []string
+	for _, m := range kreuzberg.FindPII(text, detectors) {
+		found = append(found, m.Type+"="+text[m.Start:m.End])
+	}
+	return found
+}
+
+func TestFindPIIDefaults(t *testing.T) {
+	text := "Mail jane.doe@example.co.uk or call +49 30 1234 5678. " +
+		"Pay to DE89 3704 0044 0532 0130 00 EUR with card 4111 1111 1111 1111. " +
+		"SSN 123-45-6789, due 2024-01-31."
+	want := []string{
+		"email=jane.doe@example.co.uk",
+		"phone=+49 30 1234 5678",
+		"iban=DE89 3704 0044 0532 0130 00",
+		"credit_card=4111 1111 1111 1111",
+		"national_id=123-45-6789",
+	}
+	if got := piiFound(text, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindPII() = %q, want %q", got, want)
+	}
+}
+
+func TestFindPIIRejectsInvalidChecksums(t *testing.T) {
+	cases := []string{
+		"card 4111 1111 1111 1112",
+		"iban DE89 3704 0044 0532 0130 01",
+		"ssn 666-45-6789",
+		"order ABC4111111111111111",
+	}
+	detectors := []kreuzberg.PIIDetector{kreuzberg.IBANDetector(), kreuzberg.CreditCardDetector(), kreuzberg.NationalIDDetector()}
+	for _, text := range cases {
+		if got := piiFound(text, detectors); len(got) != 0 {
+			t.Errorf("FindPII(%q) = %q, want nothing", text, got)
+		}
+	}
+}
+
+func TestFindPIICardAfterLeadingDigits(t *testing.T) {
+	cases := map[string]string{
+		"Ref 2024 4111 1111 1111 1111": "credit_card=4111 1111 1111 1111",
+		"Order 12 4111-1111-1111-1111": "credit_card=4111-1111-1111-1111",
+	}
+	for text, want := range cases {
+		if got := piiFound(text, nil); !reflect.DeepEqual(got, []string{want}) {
+			t.Errorf("FindPII(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestValidLuhnAndIBAN(t *testing.T) {
+	if !kreuzberg.ValidLuhn("4111-1111-1111-1111") || kreuzberg.ValidLuhn("1234") {
+		t.Error("unexpected ValidLuhn result")
+	}
+	if !kreuzberg.ValidIBAN("GB82WEST12345698765432") || kreuzberg.ValidIBAN("GB82WEST12345698765433") {
+		t.Error("unexpected ValidIBAN result")
+	}
+}
+
+func TestFindPIIPrecedence(t *testing.T) {
+	ticket, err := kreuzberg.NewRegexPIIDetector("ticket", `TCK-\d+`, nil)
+	if err != nil {
+		t.Fatalf("NewRegexPIIDetector() error = %v", err)
+	}
+	digits, err := kreuzberg.NewRegexPIIDetector("digits", `\d+`, nil)
+	if err != nil {
+		t.Fatalf("NewRegexPIIDetector() error = %v", err)
+	}
+	got := piiFound("see TCK-42 and 7", []kreuzberg.PIIDetector{digits, ticket})
+	want := []string{"digits=42", "digits=7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindPII() = %q, want %q", got, want)
+	}
+
+	if _, err := kreuzberg.NewRegexPIIDetector("bad", `(`, nil); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
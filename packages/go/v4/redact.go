@@ -0,0 +1,396 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RedactionReportKey is the Metadata.Additional key under which the redaction
+// post-processor records its RedactionReport.
+const RedactionReportKey = "redaction_report"
+
+// DefaultRedactionReplacement is the replacement template used when
+// RedactionOptions.Replacement is empty.
+const DefaultRedactionReplacement = "[REDACTED:{type}]"
+
+// RedactionOptions controls RedactResult.
+type RedactionOptions struct {
+	// Detectors find the data to redact, in precedence order. Nil uses
+	// DefaultPIIDetectors.
+	Detectors []PIIDetector
+	// Replacement is the text substituted for each match; "{type}" is replaced with the
+	// PII type. Defaults to DefaultRedactionReplacement.
+	Replacement string
+	// Replace, when set, computes the replacement from the PII type and the matched
+	// text instead of Replacement, e.g. to keep the last four digits of a card number.
+	Replace func(piiType, match string) string
+}
+
+// Redaction records one replaced span. ByteStart and ByteEnd locate the replacement in
+// the redacted field; the original text is not retained.
+type Redaction struct {
+	Type string `json:"type"`
+	// Field is the result query path of the redacted text, e.g. "content",
+	// "tables[0].cells[2][1]" or "djot_content.blocks[3].inline_content[0].content".
+	Field          string `json:"field"`
+	ByteStart      int    `json:"byte_start"`
+	ByteEnd        int    `json:"byte_end"`
+	OriginalLength int    `json:"original_length"`
+}
+
+// RedactionReport lists the redactions applied by RedactResult.
+type RedactionReport struct {
+	Redactions []Redaction `json:"redactions"`
+	// Counts holds the number of redactions per PII type.
+	Counts map[string]int `json:"counts"`
+}
+
+// Total returns the number of redactions.
+func (r *RedactionReport) Total() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.Redactions)
+}
+
+// RedactResult replaces the sensitive data found by the configured detectors in
+// Content, Chunks, Pages, Elements, Tables, DjotContent and the OCR results of Images,
+// modifying result in place, and returns a report of the replacements.
+//
+// Page boundaries and chunk byte offsets are remapped onto the redacted Content; an
+// offset inside a redacted span moves to the start of the replacement for start
+// offsets and to its end for end offsets. Chunks that are slices of Content are
+// re-sliced from the redacted Content and not reported separately. Table Markdown is
+// regenerated from the redacted cells. Chunk embeddings are left unchanged.
+func RedactResult(result *ExtractionResult, opts *RedactionOptions) *RedactionReport {
+	r := newRedactor(opts)
+	r.result("", result)
+	return r.report
+}
+
+// RedactionReport returns the report recorded by the redaction post-processor, if any.
+func (r *ExtractionResult) RedactionReport() (*RedactionReport, bool) {
+	if r == nil {
+		return nil, false
+	}
+	raw, ok := r.Metadata.Additional[RedactionReportKey]
+	if !ok {
+		return nil, false
+	}
+	var report RedactionReport
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return nil, false
+	}
+	return &report, true
+}
+
+// textEdit replaces text[start:end] with replacement.
+type textEdit struct {
+	start, end  int
+	replacement string
+}
+
+type redactor struct {
+	detectors   []PIIDetector
+	replacement string
+	replace     func(piiType, match string) string
+	report      *RedactionReport
+}
+
+func newRedactor(opts *RedactionOptions) *redactor {
+	r := &redactor{replacement: DefaultRedactionReplacement, report: &RedactionReport{Redactions: []Redaction{}, Counts: map[string]int{}}}
+	if opts != nil {
+		r.detectors = opts.Detectors
+		r.replace = opts.Replace
+		if opts.Replacement != "" {
+			r.replacement = opts.Replacement
+		}
+	}
+	return r
+}
+
+// text redacts s, records the redactions under field and returns the edits applied.
+func (r *redactor) text(field, s string) (string, []textEdit) {
+	matches := FindPII(s, r.detectors)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	edits := make([]textEdit, len(matches))
+	var sb strings.Builder
+	last := 0
+	for i, m := range matches {
+		var replacement string
+		if r.replace != nil {
+			replacement = r.replace(m.Type, s[m.Start:m.End])
+		} else {
+			replacement = strings.ReplaceAll(r.replacement, "{type}", m.Type)
+		}
+		sb.WriteString(s[last:m.Start])
+		start := sb.Len()
+		sb.WriteString(replacement)
+		last = m.End
+		edits[i] = textEdit{start: m.Start, end: m.End, replacement: replacement}
+		r.report.Redactions = append(r.report.Redactions, Redaction{
+			Type: m.Type, Field: field, ByteStart: start, ByteEnd: sb.Len(), OriginalLength: m.End - m.Start,
+		})
+		r.report.Counts[m.Type]++
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), edits
+}
+
+func (r *redactor) field(field string, s *string) bool {
+	redacted, edits := r.text(field, *s)
+	*s = redacted
+	return len(edits) > 0
+}
+
+func (r *redactor) result(prefix string, result *ExtractionResult) {
+	if result == nil {
+		return
+	}
+	original := result.Content
+	var edits []textEdit
+	result.Content, edits = r.text(prefix+"content", original)
+
+	if len(edits) > 0 && result.Metadata.Pages != nil {
+		for i := range result.Metadata.Pages.Boundaries {
+			b := &result.Metadata.Pages.Boundaries[i]
+			b.ByteStart = uint64(mapRedactedOffset(edits, int(b.ByteStart), false))
+			b.ByteEnd = uint64(mapRedactedOffset(edits, int(b.ByteEnd), true))
+		}
+	}
+	for i := range result.Chunks {
+		chunk := &result.Chunks[i]
+		start, end := int(chunk.Metadata.ByteStart), int(chunk.Metadata.ByteEnd)
+		sliced := start <= end && end <= len(original) && original[start:end] == chunk.Content
+		if len(edits) > 0 {
+			chunk.Metadata.ByteStart = uint64(mapRedactedOffset(edits, start, false))
+			chunk.Metadata.ByteEnd = uint64(mapRedactedOffset(edits, end, true))
+		}
+		if sliced {
+			chunk.Content = result.Content[chunk.Metadata.ByteStart:chunk.Metadata.ByteEnd]
+		} else {
+			r.field(fmt.Sprintf("%schunks[%d].content", prefix, i), &chunk.Content)
+		}
+	}
+
+	for i := range result.Pages {
+		page := &result.Pages[i]
+		pagePrefix := fmt.Sprintf("%spages[%d].", prefix, i)
+		r.field(pagePrefix+"content", &page.Content)
+		r.tables(pagePrefix+"tables", page.Tables)
+		if page.Hierarchy != nil {
+			for j := range page.Hierarchy.Blocks {
+				r.field(fmt.Sprintf("%shierarchy.blocks[%d].text", pagePrefix, j), &page.Hierarchy.Blocks[j].Text)
+			}
+		}
+		for j := range page.Images {
+			r.result(fmt.Sprintf("%simages[%d].ocr_result.", pagePrefix, j), page.Images[j].OCRResult)
+		}
+	}
+	for i := range result.Elements {
+		r.field(fmt.Sprintf("%selements[%d].text", prefix, i), &result.Elements[i].Text)
+	}
+	r.tables(prefix+"tables", result.Tables)
+	r.djot(prefix+"djot_content.", result.DjotContent)
+	for i := range result.Images {
+		r.result(fmt.Sprintf("%simages[%d].ocr_result.", prefix, i), result.Images[i].OCRResult)
+	}
+}
+
+func (r *redactor) tables(prefix string, tables []Table) {
+	for i := range tables {
+		table := &tables[i]
+		changed := false
+		for row := range table.Cells {
+			for col := range table.Cells[row] {
+				if r.field(fmt.Sprintf("%s[%d].cells[%d][%d]", prefix, i, row, col), &table.Cells[row][col]) {
+					changed = true
+				}
+			}
+		}
+		if changed {
+			table.Markdown = markdownTable(table)
+		}
+	}
+}
+
+func (r *redactor) djot(prefix string, content *DjotContent) {
+	if content == nil {
+		return
+	}
+	r.field(prefix+"plain_text", &content.PlainText)
+	r.djotBlocks(prefix+"blocks", content.Blocks)
+	for i := range content.Footnotes {
+		r.djotBlocks(fmt.Sprintf("%sfootnotes[%d].content", prefix, i), content.Footnotes[i].Content)
+	}
+	r.tables(prefix+"tables", content.Tables)
+	for i := range content.Links {
+		link := &content.Links[i]
+		r.field(fmt.Sprintf("%slinks[%d].url", prefix, i), &link.URL)
+		r.field(fmt.Sprintf("%slinks[%d].text", prefix, i), &link.Text)
+	}
+	for i := range content.Images {
+		r.field(fmt.Sprintf("%simages[%d].alt", prefix, i), &content.Images[i].Alt)
+	}
+}
+
+func (r *redactor) djotBlocks(prefix string, blocks []FormattedBlock) {
+	for i := range blocks {
+		block := &blocks[i]
+		blockPrefix := fmt.Sprintf("%s[%d].", prefix, i)
+		for j := range block.InlineContent {
+			inline := &block.InlineContent[j]
+			inlinePrefix := fmt.Sprintf("%sinline_content[%d].", blockPrefix, j)
+			r.field(inlinePrefix+"content", &inline.Content)
+			if href, ok := inline.Metadata["href"]; ok {
+				if r.field(inlinePrefix+`metadata["href"]`, &href) {
+					inline.Metadata["href"] = href
+				}
+			}
+		}
+		if block.Code != nil {
+			code := *block.Code
+			if r.field(blockPrefix+"code", &code) {
+				block.Code = &code
+			}
+		}
+		r.djotBlocks(blockPrefix+"children", block.Children)
+	}
+}
+
+// mapRedactedOffset maps a byte offset in the original text onto the redacted text.
+// edits must be sorted and non-overlapping.
+func mapRedactedOffset(edits []textEdit, offset int, isEnd bool) int {
+	shift := 0
+	for _, e := range edits {
+		if offset <= e.start {
+			break
+		}
+		if offset >= e.end {
+			shift += len(e.replacement) - (e.end - e.start)
+			continue
+		}
+		if isEnd {
+			return e.start + shift + len(e.replacement)
+		}
+		return e.start + shift
+	}
+	return offset + shift
+}
+
+// redactResultJSON applies RedactResult to a JSON-encoded ExtractionResult and records
+// the report under RedactionReportKey. Only the redacted top-level fields are replaced,
+// and images keep their data: only their OCR results are rewritten.
+func redactResultJSON(data []byte, opts *RedactionOptions) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, newSerializationErrorWithContext("failed to decode extraction result", err, ErrorCodeValidation, nil)
+	}
+	var result ExtractionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, newSerializationErrorWithContext("failed to decode extraction result", err, ErrorCodeValidation, nil)
+	}
+
+	report := RedactResult(&result, opts)
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode redaction report", err, ErrorCodeValidation, nil)
+	}
+	if result.Metadata.Additional == nil {
+		result.Metadata.Additional = map[string]json.RawMessage{}
+	}
+	result.Metadata.Additional[RedactionReportKey] = reportJSON
+
+	replaced := map[string]interface{}{
+		"content":  result.Content,
+		"metadata": result.Metadata,
+	}
+	if _, ok := fields["tables"]; ok {
+		replaced["tables"] = result.Tables
+	}
+	if _, ok := fields["chunks"]; ok {
+		replaced["chunks"] = result.Chunks
+	}
+	if _, ok := fields["elements"]; ok {
+		replaced["elements"] = result.Elements
+	}
+	if _, ok := fields["djot_content"]; ok {
+		replaced["djot_content"] = result.DjotContent
+	}
+	for key, value := range replaced {
+		if fields[key], err = json.Marshal(value); err != nil {
+			return nil, newSerializationErrorWithContext(fmt.Sprintf("failed to encode %s", key), err, ErrorCodeValidation, nil)
+		}
+	}
+	if raw, ok := fields["pages"]; ok {
+		if fields["pages"], err = patchRedactedPages(raw, result.Pages); err != nil {
+			return nil, err
+		}
+	}
+	if raw, ok := fields["images"]; ok {
+		if fields["images"], err = patchOCRResults(raw, result.Images); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// patchRedactedPages re-encodes the redacted pages, keeping the original encoding of
+// their images apart from the OCR results.
+func patchRedactedPages(raw json.RawMessage, pages []PageContent) (json.RawMessage, error) {
+	var originals []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &originals); err != nil || len(originals) != len(pages) {
+		return raw, nil
+	}
+	for i, page := range pages {
+		images := page.Images
+		page.Images = nil
+		data, err := json.Marshal(page)
+		if err != nil {
+			return nil, newSerializationErrorWithContext("failed to encode page", err, ErrorCodeValidation, nil)
+		}
+		var encoded map[string]json.RawMessage
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			return nil, newSerializationErrorWithContext("failed to encode page", err, ErrorCodeValidation, nil)
+		}
+		for key, value := range encoded {
+			originals[i][key] = value
+		}
+		if original, ok := originals[i]["images"]; ok {
+			if originals[i]["images"], err = patchOCRResults(original, images); err != nil {
+				return nil, err
+			}
+		}
+	}
+	data, err := json.Marshal(originals)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode pages", err, ErrorCodeValidation, nil)
+	}
+	return data, nil
+}
+
+// patchOCRResults replaces the ocr_result of each encoded image with the redacted one.
+func patchOCRResults(raw json.RawMessage, images []ExtractedImage) (json.RawMessage, error) {
+	var originals []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &originals); err != nil || len(originals) != len(images) {
+		return raw, nil
+	}
+	for i := range originals {
+		if images[i].OCRResult == nil {
+			continue
+		}
+		data, err := json.Marshal(images[i].OCRResult)
+		if err != nil {
+			return nil, newSerializationErrorWithContext("failed to encode OCR result", err, ErrorCodeValidation, nil)
+		}
+		originals[i]["ocr_result"] = data
+	}
+	data, err := json.Marshal(originals)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode images", err, ErrorCodeValidation, nil)
+	}
+	return data, nil
+}
@@ -0,0 +1,63 @@
+package kreuzberg
+
+/*
+#include "internal/ffi/kreuzberg.h"
+#include <stdlib.h>
+
+extern char* kreuzbergGoRedactPostProcessor(char* result_json);
+*/
+import "C"
+
+import "sync"
+
+// RedactionPostProcessorName is the name under which RegisterRedactionPostProcessor
+// registers the built-in post-processor.
+const RedactionPostProcessorName = "go_pii_redaction"
+
+var (
+	redactionMu   sync.RWMutex
+	redactionOpts *RedactionOptions
+)
+
+// RegisterRedactionPostProcessor registers a post-processor in the native pipeline that
+// applies RedactResult to every extraction. The report is recorded in
+// Metadata.Additional under RedactionReportKey; read it with
+// ExtractionResult.RedactionReport.
+//
+// Register it with a low priority so it runs after processors that add text. If the
+// result cannot be redacted the processor returns NULL, which fails the whole
+// extraction with a plugin error, so unredacted text is never returned. Registering
+// again replaces the options. Remove the processor with
+// UnregisterPostProcessor(RedactionPostProcessorName).
+func RegisterRedactionPostProcessor(priority int32, opts *RedactionOptions) error {
+	var saved *RedactionOptions
+	if opts != nil {
+		copied := *opts
+		if opts.Detectors != nil {
+			copied.Detectors = append([]PIIDetector{}, opts.Detectors...)
+		}
+		saved = &copied
+	}
+	redactionMu.Lock()
+	redactionOpts = saved
+	redactionMu.Unlock()
+
+	callback := (C.PostProcessorCallback)(C.kreuzbergGoRedactPostProcessor)
+	return RegisterPostProcessor(RedactionPostProcessorName, priority, callback)
+}
+
+//export kreuzbergGoRedactPostProcessor
+func kreuzbergGoRedactPostProcessor(resultJSON *C.char) *C.char {
+	if resultJSON == nil {
+		return nil
+	}
+	redactionMu.RLock()
+	opts := redactionOpts
+	redactionMu.RUnlock()
+
+	out, err := redactResultJSON([]byte(C.GoString(resultJSON)), opts)
+	if err != nil {
+		return nil
+	}
+	return C.CString(string(out))
+}
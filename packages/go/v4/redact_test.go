@@ -0,0 +1,146 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactResultRemapsOffsets(t *testing.T) {
+	page1 := "Contact: a@b.io"
+	page2 := "Card 4111 1111 1111 1111 ok"
+	content := page1 + "\n" + page2
+	result := &ExtractionResult{
+		Content: content,
+		Metadata: Metadata{Pages: &PageStructure{TotalCount: 2, Boundaries: []PageBoundary{
+			{ByteStart: 0, ByteEnd: uint64(len(page1)), PageNumber: 1},
+			{ByteStart: uint64(len(page1) + 1), ByteEnd: uint64(len(content)), PageNumber: 2},
+		}}},
+		Chunks: []Chunk{
+			{Content: content[:23], Metadata: ChunkMetadata{ByteStart: 0, ByteEnd: 23}},
+			{Content: content[23:], Metadata: ChunkMetadata{ByteStart: 23, ByteEnd: uint64(len(content))}},
+		},
+		Pages: []PageContent{
+			{PageNumber: 1, Content: page1},
+			{PageNumber: 2, Content: page2},
+		},
+		Elements: []Element{{ElementType: ElementTypeNarrativeText, Text: page2}},
+		Tables:   []Table{{Cells: [][]string{{"Name", "Email"}, {"Ann", "ann@example.com"}}, Markdown: "stale", PageNumber: 1}},
+	}
+
+	report := RedactResult(result, nil)
+
+	wantContent := "Contact: [REDACTED:email]\nCard [REDACTED:credit_card] ok"
+	if result.Content != wantContent {
+		t.Fatalf("Content = %q", result.Content)
+	}
+	boundaries := result.Metadata.Pages.Boundaries
+	if got := result.Content[boundaries[0].ByteStart:boundaries[0].ByteEnd]; got != "Contact: [REDACTED:email]" {
+		t.Errorf("page 1 = %q", got)
+	}
+	if got := result.Content[boundaries[1].ByteStart:boundaries[1].ByteEnd]; got != "Card [REDACTED:credit_card] ok" {
+		t.Errorf("page 2 = %q", got)
+	}
+	// The second chunk started inside the card number, so both chunks now share the replacement.
+	if result.Chunks[0].Content != "Contact: [REDACTED:email]\nCard [REDACTED:credit_card]" {
+		t.Errorf("chunk 0 = %q", result.Chunks[0].Content)
+	}
+	if result.Chunks[1].Content != "[REDACTED:credit_card] ok" {
+		t.Errorf("chunk 1 = %q", result.Chunks[1].Content)
+	}
+	for i, chunk := range result.Chunks {
+		if result.Content[chunk.Metadata.ByteStart:chunk.Metadata.ByteEnd] != chunk.Content {
+			t.Errorf("chunk %d offsets do not match its content", i)
+		}
+	}
+	if result.Pages[1].Content != "Card [REDACTED:credit_card] ok" || result.Elements[0].Text != result.Pages[1].Content {
+		t.Errorf("pages/elements not redacted: %q, %q", result.Pages[1].Content, result.Elements[0].Text)
+	}
+	if result.Tables[0].Cells[1][1] != "[REDACTED:email]" || !strings.Contains(result.Tables[0].Markdown, `\[REDACTED:email\]`) {
+		t.Errorf("table not redacted: %+v", result.Tables[0])
+	}
+
+	if report.Counts[PIITypeEmail] != 3 || report.Counts[PIITypeCreditCard] != 3 || report.Total() != 6 {
+		t.Errorf("counts = %v, total %d", report.Counts, report.Total())
+	}
+	first := report.Redactions[0]
+	if first.Field != "content" || result.Content[first.ByteStart:first.ByteEnd] != "[REDACTED:email]" || first.OriginalLength != len("a@b.io") {
+		t.Errorf("first redaction = %+v", first)
+	}
+	if last := report.Redactions[len(report.Redactions)-1]; last.Field != "tables[0].cells[1][1]" {
+		t.Errorf("last redaction field = %q", last.Field)
+	}
+}
+
+func TestRedactResultDjotAndReplace(t *testing.T) {
+	code := "ssn = 123-45-6789"
+	result := &ExtractionResult{DjotContent: &DjotContent{
+		PlainText: "write to x@y.org",
+		Blocks: []FormattedBlock{{
+			BlockType: BlockTypeParagraph,
+			InlineContent: []InlineElement{
+				{ElementType: InlineTypeLink, Content: "x@y.org", Metadata: map[string]string{"href": "mailto:x@y.org"}},
+			},
+			Children: []FormattedBlock{{BlockType: BlockTypeCodeBlock, Code: &code}},
+		}},
+	}}
+
+	report := RedactResult(result, &RedactionOptions{Replace: func(piiType, match string) string {
+		return strings.Repeat("*", len(match))
+	}})
+
+	djot := result.DjotContent
+	if djot.PlainText != "write to *******" {
+		t.Errorf("plain text = %q", djot.PlainText)
+	}
+	inline := djot.Blocks[0].InlineContent[0]
+	if inline.Content != "*******" || inline.Metadata["href"] != "mailto:*******" {
+		t.Errorf("link = %+v", inline)
+	}
+	if *djot.Blocks[0].Children[0].Code != "ssn = ***********" || code != "ssn = 123-45-6789" {
+		t.Errorf("code = %q (original %q)", *djot.Blocks[0].Children[0].Code, code)
+	}
+	if report.Total() != 4 {
+		t.Errorf("Total() = %d", report.Total())
+	}
+}
+
+func TestRedactResultJSONKeepsImageData(t *testing.T) {
+	input := `{"content":"mail me@corp.com","mime_type":"text/plain","metadata":{"custom":1},"tables":[],` +
+		`"images":[{"data":[1,2,3],"format":"png","image_index":0,"is_mask":false,` +
+		`"ocr_result":{"content":"call +1 415 555 0100","mime_type":"text/plain","metadata":{},"tables":[]}}]}`
+	out, err := redactResultJSON([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("redactResultJSON() error = %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatalf("invalid output: %v", err)
+	}
+	if string(fields["content"]) != `"mail [REDACTED:email]"` {
+		t.Errorf("content = %s", fields["content"])
+	}
+	var images []map[string]json.RawMessage
+	if err := json.Unmarshal(fields["images"], &images); err != nil {
+		t.Fatalf("invalid images: %v", err)
+	}
+	if string(images[0]["data"]) != "[1,2,3]" {
+		t.Errorf("image data changed: %s", images[0]["data"])
+	}
+	if !strings.Contains(string(images[0]["ocr_result"]), "[REDACTED:phone]") {
+		t.Errorf("OCR result not redacted: %s", images[0]["ocr_result"])
+	}
+
+	var result ExtractionResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+	report, ok := result.RedactionReport()
+	if !ok || report.Counts[PIITypeEmail] != 1 || report.Counts[PIITypePhone] != 1 {
+		t.Errorf("report = %+v, %v", report, ok)
+	}
+	if string(result.Metadata.Additional["custom"]) != "1" {
+		t.Errorf("custom metadata lost: %v", result.Metadata.Additional)
+	}
+}
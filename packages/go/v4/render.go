@@ -0,0 +1,838 @@
+package kreuzberg
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RenderSource selects which part of an ExtractionResult a renderer reads from.
+type RenderSource string
+
+const (
+	// RenderSourceAuto uses the richest source available: DjotContent blocks, then
+	// Elements, then Pages, then Content. When page markers are requested, sources that
+	// carry page numbers (Elements, Pages) are preferred over DjotContent.
+	RenderSourceAuto RenderSource = ""
+	// RenderSourceDjot renders DjotContent.Blocks, with each top-level table at its
+	// position. Tables without a position follow the blocks.
+	RenderSourceDjot RenderSource = "djot"
+	// RenderSourceElements renders Elements (requires ResultFormat "element_based").
+	RenderSourceElements RenderSource = "elements"
+	// RenderSourcePages renders Pages with their tables.
+	RenderSourcePages RenderSource = "pages"
+	// RenderSourceContent renders Content split into paragraphs, followed by Tables.
+	RenderSourceContent RenderSource = "content"
+)
+
+// DefaultPageMarkerFormat matches the native PageConfig marker format.
+const DefaultPageMarkerFormat = "<!-- PAGE {page_num} -->"
+
+// RenderOptions controls RenderMarkdown, RenderHTML and RenderPlainText.
+type RenderOptions struct {
+	// Source selects the result field to render from. Defaults to RenderSourceAuto.
+	Source RenderSource
+	// PageMarkers inserts a marker before each page when the source has page numbers.
+	PageMarkers bool
+	// PageMarkerFormat is the marker text; "{page_num}" is replaced with the page number.
+	// Defaults to DefaultPageMarkerFormat for Markdown and "--- Page {page_num} ---"
+	// for plain text. HTML renders markers as <div class="page-marker"> elements with
+	// the formatted text escaped.
+	PageMarkerFormat string
+}
+
+// renderNode is one top-level block of a rendered document.
+type renderNode struct {
+	block *FormattedBlock
+	table *Table
+	page  uint64
+}
+
+// RenderMarkdown renders result as Markdown, preserving headings, lists, code blocks
+// and tables. A nil opts uses the defaults.
+func RenderMarkdown(result *ExtractionResult, opts *RenderOptions) (string, error) {
+	nodes, resolved, err := renderNodes(result, opts)
+	if err != nil {
+		return "", err
+	}
+	format := resolved.PageMarkerFormat
+	if format == "" {
+		format = DefaultPageMarkerFormat
+	}
+
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		var s string
+		switch {
+		case node.page > 0:
+			s = formatPageMarker(format, node.page)
+		case node.table != nil:
+			s = markdownTable(node.table)
+		default:
+			s = markdownBlock(node.block, "")
+		}
+		if s = strings.TrimRight(s, "\n"); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return joinRendered(parts), nil
+}
+
+// RenderHTML renders result as an HTML fragment. All text is escaped, raw blocks are
+// rendered as escaped text, and link and image URLs are restricted to http, https,
+// mailto and relative URLs, so the output is safe to embed in a page. A nil opts uses
+// the defaults.
+func RenderHTML(result *ExtractionResult, opts *RenderOptions) (string, error) {
+	nodes, resolved, err := renderNodes(result, opts)
+	if err != nil {
+		return "", err
+	}
+	format := resolved.PageMarkerFormat
+	if format == "" {
+		format = "Page {page_num}"
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.page > 0:
+			fmt.Fprintf(&b, "<div class=\"page-marker\" data-page=\"%d\">%s</div>\n", node.page, html.EscapeString(formatPageMarker(format, node.page)))
+		case node.table != nil:
+			htmlTable(&b, node.table)
+		default:
+			htmlBlock(&b, node.block)
+		}
+	}
+	return b.String(), nil
+}
+
+// RenderPlainText renders result as plain text without markup. Lists keep their
+// markers, and table cells are separated by tabs. A nil opts uses the defaults.
+func RenderPlainText(result *ExtractionResult, opts *RenderOptions) (string, error) {
+	nodes, resolved, err := renderNodes(result, opts)
+	if err != nil {
+		return "", err
+	}
+	format := resolved.PageMarkerFormat
+	if format == "" {
+		format = "--- Page {page_num} ---"
+	}
+
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		var s string
+		switch {
+		case node.page > 0:
+			s = formatPageMarker(format, node.page)
+		case node.table != nil:
+			s = plainTable(node.table)
+		default:
+			s = plainBlock(node.block, "")
+		}
+		if s = strings.TrimRight(s, "\n"); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return joinRendered(parts), nil
+}
+
+func joinRendered(parts []string) string {
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func formatPageMarker(format string, page uint64) string {
+	return strings.TrimSpace(strings.ReplaceAll(format, "{page_num}", strconv.FormatUint(page, 10)))
+}
+
+// renderNodes normalizes the chosen source into top-level render nodes.
+func renderNodes(result *ExtractionResult, opts *RenderOptions) ([]renderNode, RenderOptions, error) {
+	if result == nil {
+		return nil, RenderOptions{}, newValidationErrorWithContext("result cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	resolved := RenderOptions{}
+	if opts != nil {
+		resolved = *opts
+	}
+
+	source := resolved.Source
+	if source == RenderSourceAuto {
+		source = autoRenderSource(result, resolved.PageMarkers)
+	}
+
+	switch source {
+	case RenderSourceDjot:
+		if result.DjotContent == nil {
+			return nil, resolved, newValidationErrorWithContext("result has no djot content", nil, ErrorCodeValidation, nil)
+		}
+		return djotRenderNodes(result.DjotContent), resolved, nil
+	case RenderSourceElements:
+		return elementRenderNodes(result.Elements, resolved.PageMarkers), resolved, nil
+	case RenderSourcePages:
+		return pageRenderNodes(result.Pages, resolved.PageMarkers), resolved, nil
+	case RenderSourceContent:
+		return contentRenderNodes(result.Content, result.Tables), resolved, nil
+	default:
+		return nil, resolved, newValidationErrorWithContext(fmt.Sprintf("invalid render source: %s", source), nil, ErrorCodeValidation, nil)
+	}
+}
+
+func autoRenderSource(result *ExtractionResult, pageMarkers bool) RenderSource {
+	if pageMarkers {
+		for _, el := range result.Elements {
+			if el.Metadata.PageNumber != nil {
+				return RenderSourceElements
+			}
+		}
+		if len(result.Pages) > 0 {
+			return RenderSourcePages
+		}
+	}
+	switch {
+	case result.DjotContent != nil && len(result.DjotContent.Blocks) > 0:
+		return RenderSourceDjot
+	case len(result.Elements) > 0:
+		return RenderSourceElements
+	case len(result.Pages) > 0:
+		return RenderSourcePages
+	default:
+		return RenderSourceContent
+	}
+}
+
+// djotRenderNodes renders top-level table blocks as their tables. Tables not placed by
+// a top-level block follow the blocks.
+func djotRenderNodes(djot *DjotContent) []renderNode {
+	nodes := make([]renderNode, 0, len(djot.Blocks)+len(djot.Tables))
+	placed := make([]bool, len(djot.Tables))
+	for i := range djot.Blocks {
+		if index, ok := djotTableIndex(&djot.Blocks[i], len(djot.Tables)); ok {
+			if !placed[index] {
+				placed[index] = true
+				nodes = append(nodes, renderNode{table: &djot.Tables[index]})
+			}
+			continue
+		}
+		nodes = append(nodes, renderNode{block: &djot.Blocks[i]})
+	}
+	for i := range djot.Tables {
+		if !placed[i] {
+			nodes = append(nodes, renderNode{table: &djot.Tables[i]})
+		}
+	}
+	return nodes
+}
+
+var listMarkerPattern = regexp.MustCompile(`^\s*(?:[-*+•◦▪]|\d+[.)]|[A-Za-z][.)])\s+`)
+
+// elementRenderNodes groups consecutive list items into lists and maps element types to
+// blocks. Page headers, footers, images and page breaks carry no renderable text and
+// are skipped.
+func elementRenderNodes(elements []Element, pageMarkers bool) []renderNode {
+	nodes := []renderNode{}
+	var currentPage uint64
+	var list *FormattedBlock
+
+	flushList := func() {
+		if list != nil {
+			nodes = append(nodes, renderNode{block: list})
+			list = nil
+		}
+	}
+
+	for _, el := range elements {
+		if pageMarkers && el.Metadata.PageNumber != nil && *el.Metadata.PageNumber != currentPage {
+			flushList()
+			currentPage = *el.Metadata.PageNumber
+			nodes = append(nodes, renderNode{page: currentPage})
+		}
+
+		if el.ElementType == ElementTypeListItem {
+			listType := BlockTypeBulletList
+			switch el.Metadata.Additional["list_type"] {
+			case "Numbered", "Lettered":
+				listType = BlockTypeOrderedList
+			}
+			if list == nil || list.BlockType != listType {
+				flushList()
+				list = &FormattedBlock{BlockType: listType}
+			}
+			text := listMarkerPattern.ReplaceAllString(el.Text, "")
+			list.Children = append(list.Children, FormattedBlock{BlockType: BlockTypeListItem, InlineContent: textInline(text)})
+			continue
+		}
+		flushList()
+
+		switch el.ElementType {
+		case ElementTypeTitle, ElementTypeHeading:
+			level := uint64(1)
+			if el.ElementType == ElementTypeHeading {
+				level = 2
+			}
+			if raw := strings.TrimPrefix(el.Metadata.Additional["level"], "h"); raw != "" {
+				if n, err := strconv.ParseUint(raw, 10, 64); err == nil && n >= 1 && n <= 6 {
+					level = n
+				}
+			}
+			nodes = append(nodes, renderNode{block: &FormattedBlock{BlockType: BlockTypeHeading, Level: &level, InlineContent: textInline(el.Text)}})
+		case ElementTypeCodeBlock:
+			code := el.Text
+			nodes = append(nodes, renderNode{block: &FormattedBlock{BlockType: BlockTypeCodeBlock, Code: &code}})
+		case ElementTypeBlockQuote:
+			nodes = append(nodes, renderNode{block: &FormattedBlock{BlockType: BlockTypeBlockquote, InlineContent: textInline(el.Text)}})
+		case ElementTypeTable:
+			nodes = append(nodes, renderNode{table: tableFromText(el.Text)})
+		case ElementTypeNarrativeText:
+			nodes = append(nodes, renderNode{block: &FormattedBlock{BlockType: BlockTypeParagraph, InlineContent: textInline(el.Text)}})
+		}
+	}
+	flushList()
+	return nodes
+}
+
+func pageRenderNodes(pages []PageContent, pageMarkers bool) []renderNode {
+	nodes := []renderNode{}
+	for i := range pages {
+		page := &pages[i]
+		if pageMarkers {
+			nodes = append(nodes, renderNode{page: page.PageNumber})
+		}
+		nodes = append(nodes, contentRenderNodes(page.Content, page.Tables)...)
+	}
+	return nodes
+}
+
+func contentRenderNodes(content string, tables []Table) []renderNode {
+	nodes := []renderNode{}
+	for _, paragraph := range splitParagraphs(content) {
+		nodes = append(nodes, renderNode{block: &FormattedBlock{BlockType: BlockTypeParagraph, InlineContent: textInline(paragraph)}})
+	}
+	for i := range tables {
+		nodes = append(nodes, renderNode{table: &tables[i]})
+	}
+	return nodes
+}
+
+func splitParagraphs(content string) []string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	paragraphs := []string{}
+	for _, p := range strings.Split(content, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// tableFromText parses the tab-separated text the native library uses for table elements.
+func tableFromText(text string) *Table {
+	table := &Table{}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		table.Cells = append(table.Cells, strings.Split(line, "\t"))
+	}
+	return table
+}
+
+func textInline(text string) []InlineElement {
+	return []InlineElement{{ElementType: InlineTypeText, Content: text}}
+}
+
+func blockLevel(block *FormattedBlock) int {
+	level := 1
+	if block.Level != nil {
+		level = int(*block.Level)
+	}
+	if level < 1 {
+		return 1
+	}
+	if level > 6 {
+		return 6
+	}
+	return level
+}
+
+func blockCode(block *FormattedBlock) string {
+	if block.Code != nil {
+		return strings.TrimRight(*block.Code, "\n")
+	}
+	lines := make([]string, 0, len(block.InlineContent))
+	for _, inline := range block.InlineContent {
+		lines = append(lines, inline.Content)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func blockLanguage(block *FormattedBlock) string {
+	if block.Language == nil {
+		return ""
+	}
+	return *block.Language
+}
+
+// ---------------------------------------------------------------------------
+// Markdown
+// ---------------------------------------------------------------------------
+
+func markdownBlock(block *FormattedBlock, indent string) string {
+	var b strings.Builder
+	switch block.BlockType {
+	case BlockTypeHeading:
+		b.WriteString(indent + strings.Repeat("#", blockLevel(block)) + " " + markdownInlines(block.InlineContent))
+	case BlockTypeParagraph, BlockTypeDefinitionTerm:
+		b.WriteString(indentLines(markdownText(block.InlineContent), indent))
+	case BlockTypeDefinitionDesc:
+		b.WriteString(indent + ": " + markdownText(block.InlineContent))
+	case BlockTypeCodeBlock:
+		fence := codeFence(blockCode(block))
+		b.WriteString(indent + fence + blockLanguage(block) + "\n")
+		if code := blockCode(block); code != "" {
+			b.WriteString(indentLines(code, indent) + "\n")
+		}
+		b.WriteString(indent + fence)
+	case BlockTypeRawBlock:
+		b.WriteString(indentLines(blockCode(block), indent))
+	case BlockTypeMathDisplay:
+		b.WriteString(indent + "$$\n" + indentLines(blockCode(block), indent) + "\n" + indent + "$$")
+	case BlockTypeBlockquote:
+		inner := []string{}
+		if len(block.InlineContent) > 0 {
+			inner = append(inner, markdownText(block.InlineContent))
+		}
+		for i := range block.Children {
+			inner = append(inner, markdownBlock(&block.Children[i], ""))
+		}
+		for i, line := range strings.Split(strings.Join(inner, "\n\n"), "\n") {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(strings.TrimRight(indent+"> "+line, " "))
+		}
+	case BlockTypeBulletList, BlockTypeOrderedList, BlockTypeTaskList:
+		for i := range block.Children {
+			marker := "- "
+			switch block.BlockType {
+			case BlockTypeOrderedList:
+				marker = strconv.Itoa(i+1) + ". "
+			case BlockTypeTaskList:
+				marker = "- [ ] "
+			}
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(markdownListItem(&block.Children[i], indent, marker))
+		}
+	case BlockTypeListItem:
+		b.WriteString(markdownListItem(block, indent, "- "))
+	case BlockTypeThematicBreak:
+		b.WriteString(indent + "---")
+	default:
+		// Containers (div, section, definition list) render their children in order.
+		parts := []string{}
+		if len(block.InlineContent) > 0 {
+			parts = append(parts, indent+markdownText(block.InlineContent))
+		}
+		for i := range block.Children {
+			parts = append(parts, markdownBlock(&block.Children[i], indent))
+		}
+		b.WriteString(strings.Join(parts, "\n\n"))
+	}
+	return b.String()
+}
+
+func markdownListItem(item *FormattedBlock, indent, marker string) string {
+	var b strings.Builder
+	b.WriteString(indent + marker + markdownText(item.InlineContent))
+	childIndent := indent + strings.Repeat(" ", len(marker))
+	for i := range item.Children {
+		b.WriteString("\n" + markdownBlock(&item.Children[i], childIndent))
+	}
+	return b.String()
+}
+
+// markdownText renders block text, escaping characters that would start a block.
+func markdownText(inlines []InlineElement) string {
+	return escapeMarkdownLineStarts(markdownInlines(inlines))
+}
+
+// markdownInlines renders inline elements with their text escaped. Code, math and
+// symbols are written verbatim.
+func markdownInlines(inlines []InlineElement) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		content := inline.Content
+		switch inline.ElementType {
+		case InlineTypeCode, InlineTypeMath, InlineTypeSymbol:
+		default:
+			content = escapeMarkdownText(content)
+		}
+		switch inline.ElementType {
+		case InlineTypeStrong:
+			b.WriteString("**" + content + "**")
+		case InlineTypeEmphasis:
+			b.WriteString("*" + content + "*")
+		case InlineTypeHighlight:
+			b.WriteString("==" + content + "==")
+		case InlineTypeSubscript:
+			b.WriteString("~" + content + "~")
+		case InlineTypeSuperscript:
+			b.WriteString("^" + content + "^")
+		case InlineTypeInsert:
+			b.WriteString("<ins>" + content + "</ins>")
+		case InlineTypeDelete:
+			b.WriteString("~~" + content + "~~")
+		case InlineTypeCode:
+			fence := "`"
+			for strings.Contains(content, fence) {
+				fence += "`"
+			}
+			b.WriteString(fence + content + fence)
+		case InlineTypeLink:
+			b.WriteString("[" + content + "](" + escapeMarkdownDestination(inline.Metadata["href"]) + ")")
+		case InlineTypeImage:
+			b.WriteString("![" + content + "](" + escapeMarkdownDestination(inline.Metadata["src"]) + ")")
+		case InlineTypeMath:
+			b.WriteString("$" + content + "$")
+		case InlineTypeFootnoteRef:
+			b.WriteString("[^" + content + "]")
+		case InlineTypeSymbol:
+			b.WriteString(":" + content + ":")
+		default:
+			b.WriteString(content)
+		}
+	}
+	return b.String()
+}
+
+func markdownTable(table *Table) string {
+	if len(table.Cells) == 0 {
+		return ""
+	}
+	width := 0
+	for _, row := range table.Cells {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	if width == 0 {
+		return ""
+	}
+
+	row := func(cells []string) string {
+		out := make([]string, width)
+		for i := range out {
+			if i < len(cells) {
+				out[i] = markdownTableCell(cells[i])
+			}
+		}
+		return "| " + strings.Join(out, " | ") + " |"
+	}
+
+	lines := []string{row(table.Cells[0]), "|" + strings.Repeat(" --- |", width)}
+	for _, cells := range table.Cells[1:] {
+		lines = append(lines, row(cells))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func markdownTableCell(cell string) string {
+	cell = escapeMarkdownText(strings.TrimSpace(cell))
+	return strings.ReplaceAll(strings.ReplaceAll(cell, "\r\n", "<br>"), "\n", "<br>")
+}
+
+var markdownTextEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"<", `\<`, "|", `\|`, "~", `\~`,
+)
+
+func escapeMarkdownText(text string) string {
+	return markdownTextEscaper.Replace(text)
+}
+
+func escapeMarkdownDestination(dest string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, " ", "%20").Replace(dest)
+}
+
+var markdownBlockStartPattern = regexp.MustCompile(`^(\s*)([#>+=-]|\d+[.)])`)
+
+// escapeMarkdownLineStarts escapes characters at the start of a line that would otherwise
+// begin a heading, blockquote, list, setext underline or thematic break.
+func escapeMarkdownLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if m := markdownBlockStartPattern.FindStringSubmatchIndex(line); m != nil {
+			punct := m[5] - 1
+			lines[i] = line[:punct] + `\` + line[punct:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func codeFence(code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence
+}
+
+func indentLines(text, indent string) string {
+	if indent == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ---------------------------------------------------------------------------
+// HTML
+// ---------------------------------------------------------------------------
+
+func htmlBlock(b *strings.Builder, block *FormattedBlock) {
+	switch block.BlockType {
+	case BlockTypeHeading:
+		level := blockLevel(block)
+		fmt.Fprintf(b, "<h%d%s>%s</h%d>\n", level, htmlIDAttr(block.Attributes), htmlInlines(block.InlineContent), level)
+	case BlockTypeParagraph:
+		fmt.Fprintf(b, "<p%s>%s</p>\n", htmlIDAttr(block.Attributes), htmlInlines(block.InlineContent))
+	case BlockTypeCodeBlock:
+		class := ""
+		if lang := blockLanguage(block); lang != "" {
+			class = ` class="language-` + html.EscapeString(lang) + `"`
+		}
+		fmt.Fprintf(b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(blockCode(block)))
+	case BlockTypeRawBlock:
+		fmt.Fprintf(b, "<pre>%s</pre>\n", html.EscapeString(blockCode(block)))
+	case BlockTypeMathDisplay:
+		fmt.Fprintf(b, "<div class=\"math\">%s</div>\n", html.EscapeString(blockCode(block)))
+	case BlockTypeBlockquote:
+		b.WriteString("<blockquote>\n")
+		if len(block.InlineContent) > 0 {
+			fmt.Fprintf(b, "<p>%s</p>\n", htmlInlines(block.InlineContent))
+		}
+		htmlChildren(b, block.Children)
+		b.WriteString("</blockquote>\n")
+	case BlockTypeBulletList, BlockTypeTaskList:
+		b.WriteString("<ul>\n")
+		htmlListItems(b, block.Children)
+		b.WriteString("</ul>\n")
+	case BlockTypeOrderedList:
+		b.WriteString("<ol>\n")
+		htmlListItems(b, block.Children)
+		b.WriteString("</ol>\n")
+	case BlockTypeListItem:
+		b.WriteString("<ul>\n")
+		htmlListItems(b, []FormattedBlock{*block})
+		b.WriteString("</ul>\n")
+	case BlockTypeDefinitionList:
+		b.WriteString("<dl>\n")
+		htmlChildren(b, block.Children)
+		b.WriteString("</dl>\n")
+	case BlockTypeDefinitionTerm:
+		fmt.Fprintf(b, "<dt>%s</dt>\n", htmlInlines(block.InlineContent))
+	case BlockTypeDefinitionDesc:
+		fmt.Fprintf(b, "<dd>%s</dd>\n", htmlInlines(block.InlineContent))
+	case BlockTypeThematicBreak:
+		b.WriteString("<hr>\n")
+	case BlockTypeSection:
+		fmt.Fprintf(b, "<section%s>\n", htmlIDAttr(block.Attributes))
+		htmlChildren(b, block.Children)
+		b.WriteString("</section>\n")
+	default:
+		fmt.Fprintf(b, "<div%s>\n", htmlIDAttr(block.Attributes))
+		if len(block.InlineContent) > 0 {
+			fmt.Fprintf(b, "<p>%s</p>\n", htmlInlines(block.InlineContent))
+		}
+		htmlChildren(b, block.Children)
+		b.WriteString("</div>\n")
+	}
+}
+
+func htmlChildren(b *strings.Builder, children []FormattedBlock) {
+	for i := range children {
+		htmlBlock(b, &children[i])
+	}
+}
+
+func htmlListItems(b *strings.Builder, items []FormattedBlock) {
+	for i := range items {
+		item := &items[i]
+		b.WriteString("<li>" + htmlInlines(item.InlineContent))
+		if len(item.Children) > 0 {
+			b.WriteString("\n")
+			htmlChildren(b, item.Children)
+		}
+		b.WriteString("</li>\n")
+	}
+}
+
+func htmlInlines(inlines []InlineElement) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		content := html.EscapeString(inline.Content)
+		switch inline.ElementType {
+		case InlineTypeStrong:
+			b.WriteString("<strong>" + content + "</strong>")
+		case InlineTypeEmphasis:
+			b.WriteString("<em>" + content + "</em>")
+		case InlineTypeHighlight:
+			b.WriteString("<mark>" + content + "</mark>")
+		case InlineTypeSubscript:
+			b.WriteString("<sub>" + content + "</sub>")
+		case InlineTypeSuperscript:
+			b.WriteString("<sup>" + content + "</sup>")
+		case InlineTypeInsert:
+			b.WriteString("<ins>" + content + "</ins>")
+		case InlineTypeDelete:
+			b.WriteString("<del>" + content + "</del>")
+		case InlineTypeCode:
+			b.WriteString("<code>" + content + "</code>")
+		case InlineTypeLink:
+			if href := sanitizeRenderURL(inline.Metadata["href"]); href != "" {
+				b.WriteString(`<a href="` + html.EscapeString(href) + `">` + content + "</a>")
+			} else {
+				b.WriteString(content)
+			}
+		case InlineTypeImage:
+			if src := sanitizeRenderURL(inline.Metadata["src"]); src != "" {
+				b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + content + `">`)
+			} else {
+				b.WriteString(content)
+			}
+		case InlineTypeMath:
+			b.WriteString(`<span class="math">` + content + "</span>")
+		case InlineTypeFootnoteRef:
+			b.WriteString(`<sup class="footnote-ref">` + content + "</sup>")
+		default:
+			b.WriteString(content)
+		}
+	}
+	return b.String()
+}
+
+func htmlIDAttr(attrs *Attributes) string {
+	if attrs == nil || attrs.ID == nil || *attrs.ID == "" {
+		return ""
+	}
+	return ` id="` + html.EscapeString(*attrs.ID) + `"`
+}
+
+func htmlTable(b *strings.Builder, table *Table) {
+	if len(table.Cells) == 0 {
+		return
+	}
+	b.WriteString("<table>\n<thead>\n<tr>")
+	for _, cell := range table.Cells[0] {
+		b.WriteString("<th>" + html.EscapeString(strings.TrimSpace(cell)) + "</th>")
+	}
+	b.WriteString("</tr>\n</thead>\n")
+	if len(table.Cells) > 1 {
+		b.WriteString("<tbody>\n")
+		for _, row := range table.Cells[1:] {
+			b.WriteString("<tr>")
+			for _, cell := range row {
+				b.WriteString("<td>" + html.EscapeString(strings.TrimSpace(cell)) + "</td>")
+			}
+			b.WriteString("</tr>\n")
+		}
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// sanitizeRenderURL returns u if it is relative or uses a safe scheme, and "" otherwise.
+func sanitizeRenderURL(u string) string {
+	u = strings.TrimSpace(u)
+	if u == "" {
+		return ""
+	}
+	end := strings.IndexAny(u, "/?#")
+	if end < 0 {
+		end = len(u)
+	}
+	colon := strings.IndexByte(u[:end], ':')
+	if colon < 0 {
+		return u
+	}
+	switch strings.ToLower(u[:colon]) {
+	case "http", "https", "mailto":
+		return u
+	}
+	return ""
+}
+
+// ---------------------------------------------------------------------------
+// Plain text
+// ---------------------------------------------------------------------------
+
+func plainBlock(block *FormattedBlock, indent string) string {
+	switch block.BlockType {
+	case BlockTypeCodeBlock, BlockTypeRawBlock, BlockTypeMathDisplay:
+		return indentLines(blockCode(block), indent)
+	case BlockTypeBulletList, BlockTypeOrderedList, BlockTypeTaskList:
+		lines := make([]string, 0, len(block.Children))
+		for i := range block.Children {
+			marker := "- "
+			if block.BlockType == BlockTypeOrderedList {
+				marker = strconv.Itoa(i+1) + ". "
+			}
+			lines = append(lines, plainListItem(&block.Children[i], indent, marker))
+		}
+		return strings.Join(lines, "\n")
+	case BlockTypeListItem:
+		return plainListItem(block, indent, "- ")
+	case BlockTypeThematicBreak:
+		return ""
+	}
+
+	parts := []string{}
+	if len(block.InlineContent) > 0 {
+		parts = append(parts, indentLines(plainInlines(block.InlineContent), indent))
+	}
+	for i := range block.Children {
+		if s := plainBlock(&block.Children[i], indent); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func plainListItem(item *FormattedBlock, indent, marker string) string {
+	var b strings.Builder
+	b.WriteString(indent + marker + plainInlines(item.InlineContent))
+	for i := range item.Children {
+		b.WriteString("\n" + plainBlock(&item.Children[i], indent+strings.Repeat(" ", len(marker))))
+	}
+	return b.String()
+}
+
+func plainInlines(inlines []InlineElement) string {
+	var b strings.Builder
+	for _, inline := range inlines {
+		if inline.ElementType == InlineTypeFootnoteRef {
+			continue
+		}
+		b.WriteString(inline.Content)
+	}
+	return b.String()
+}
+
+func plainTable(table *Table) string {
+	rows := make([]string, 0, len(table.Cells))
+	for _, row := range table.Cells {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.TrimSpace(cell)
+		}
+		rows = append(rows, strings.Join(cells, "\t"))
+	}
+	return strings.Join(rows, "\n")
+}
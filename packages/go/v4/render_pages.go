@@ -0,0 +1,378 @@
+package kreuzberg
+
+/*
+#include "internal/ffi/kreuzberg.h"
+#include <stdlib.h>
+#include <stdint.h>
+
+// Page rendering API function declarations
+CRenderedPageBatch *kreuzberg_render_pdf_pages(const uint8_t *data, uintptr_t data_len, const uintptr_t *page_indices, uintptr_t page_count, const char *options_json, const char *password);
+void kreuzberg_free_rendered_page_batch(CRenderedPageBatch *batch);
+int32_t kreuzberg_pdf_page_count(const uint8_t *data, uintptr_t data_len, const char *password);
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// Defaults applied by StreamPages, matching the native ImageExtractionConfig defaults
+// where one exists.
+const (
+	DefaultRenderTargetDPI         = 300
+	DefaultRenderMaxImageDimension = 4096
+	DefaultRenderMinDPI            = 72
+	DefaultRenderMaxDPI            = 600
+	DefaultRenderJPEGQuality       = 90
+	DefaultRenderMaxBytes          = 32 << 20
+)
+
+// RenderBatchPages is the number of pages StreamPages renders per document load. It
+// bounds the RGB pixels held at once, up to DefaultRenderMaxImageDimension squared
+// times 3 bytes per page.
+const RenderBatchPages = 4
+
+// PageImageFormat selects the encoding of rendered pages.
+type PageImageFormat string
+
+const (
+	PageImagePNG  PageImageFormat = "png"
+	PageImageJPEG PageImageFormat = "jpeg"
+)
+
+// PageImageOptions controls StreamPages.
+type PageImageOptions struct {
+	// DPI sets the resolution with the semantics of image extraction: pages are
+	// rendered at TargetDPI; with AutoAdjustDPI the DPI is lowered so that neither side
+	// exceeds MaxImageDimension pixels, staying within MinDPI and MaxDPI. ExtractImages
+	// is ignored. Nil uses the defaults.
+	DPI *ImageExtractionConfig
+	// Format defaults to PageImagePNG.
+	Format PageImageFormat
+	// JPEGQuality is the JPEG quality from 1 to 100. Defaults to DefaultRenderJPEGQuality.
+	JPEGQuality int
+	// MaxBytes caps the encoded size of each page; a larger page fails the rendering.
+	// Defaults to DefaultRenderMaxBytes.
+	MaxBytes int
+	// Password opens encrypted documents.
+	Password string
+}
+
+// RenderedPage is one page rendered by RenderPages or StreamPages.
+type RenderedPage struct {
+	// PageNumber is the 1-indexed page number.
+	PageNumber int
+	// PageCount is the number of pages in the document.
+	PageCount int
+	Format    PageImageFormat
+	// Width and Height are the image size in pixels.
+	Width  int
+	Height int
+	// Data is the encoded image.
+	Data []byte
+}
+
+// RenderPages renders pages of the PDF at path to images, e.g. for thumbnails or
+// custom OCR. pages lists 1-indexed page numbers, with negative numbers counting from
+// the end (-1 is the last page); nil renders every page. dpi follows the
+// ImageExtractionConfig DPI semantics and may be nil.
+//
+// RenderPages keeps every image in memory; use StreamPages for large documents.
+func RenderPages(path string, pages []int, dpi *ImageExtractionConfig, format PageImageFormat) ([]RenderedPage, error) {
+	var rendered []RenderedPage
+	err := StreamPages(path, pages, &PageImageOptions{DPI: dpi, Format: format}, func(page *RenderedPage) error {
+		rendered = append(rendered, *page)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// StreamPages renders pages of the PDF at path like RenderPages, passing each page to
+// fn as soon as it is rendered. An error returned by fn stops the rendering and is
+// returned.
+//
+// Pages are rendered in batches of up to RenderBatchPages, each loading the document
+// once. Like extraction, rendering holds the binding's FFI lock, but only while a batch
+// is being rendered: other extractions may run between batches, and fn may call any
+// function of this package.
+func StreamPages(path string, pages []int, opts *PageImageOptions, fn func(*RenderedPage) error) error {
+	if path == "" {
+		return newValidationErrorWithContext("path is required", nil, ErrorCodeValidation, nil)
+	}
+	if fn == nil {
+		return newValidationErrorWithContext("page callback cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	settings, err := newRenderSettings(opts)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newIOErrorWithContext(fmt.Sprintf("failed to read %s", path), err, ErrorCodeIo, nil)
+	}
+	if len(data) == 0 {
+		return newValidationErrorWithContext(fmt.Sprintf("%s is empty", path), nil, ErrorCodeValidation, nil)
+	}
+
+	buf := C.CBytes(data)
+	defer C.free(buf)
+	var cPassword *C.char
+	if settings.password != "" {
+		cPassword = C.CString(settings.password)
+		defer C.free(unsafe.Pointer(cPassword))
+	}
+	cOptions := C.CString(settings.nativeOptions)
+	defer C.free(unsafe.Pointer(cOptions))
+
+	total, err := pdfPageCount(buf, len(data), cPassword)
+	if err != nil {
+		return err
+	}
+	numbers, err := resolveRenderPages(pages, total)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range renderBatches(numbers) {
+		rendered, err := renderPDFPages(buf, len(data), batch, cOptions, cPassword)
+		if err != nil {
+			return err
+		}
+		for i, number := range batch {
+			encoded, err := settings.encode(rendered[i].rgb, rendered[i].width, rendered[i].height)
+			if err != nil {
+				return newValidationErrorWithContext(fmt.Sprintf("page %d", number), err, ErrorCodeValidation, nil)
+			}
+			page := &RenderedPage{
+				PageNumber: number,
+				PageCount:  total,
+				Format:     settings.format,
+				Width:      rendered[i].width,
+				Height:     rendered[i].height,
+				Data:       encoded,
+			}
+			// Drop the pixels once encoded so they can be collected while fn runs.
+			rendered[i].rgb = nil
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderBatches splits 1-indexed page numbers into batches of at most RenderBatchPages.
+func renderBatches(numbers []int) [][]int {
+	var batches [][]int
+	for start := 0; start < len(numbers); start += RenderBatchPages {
+		end := min(start+RenderBatchPages, len(numbers))
+		batches = append(batches, numbers[start:end])
+	}
+	return batches
+}
+
+// countPDFPages returns the page count of the PDF in data without extracting it.
+func countPDFPages(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, newValidationErrorWithContext("data cannot be empty", nil, ErrorCodeValidation, nil)
+	}
+	buf := C.CBytes(data)
+	defer C.free(buf)
+	return pdfPageCount(buf, len(data), nil)
+}
+
+func pdfPageCount(buf unsafe.Pointer, size int, password *C.char) (int, error) {
+	ffiMutex.Lock()
+	defer ffiMutex.Unlock()
+
+	count := C.kreuzberg_pdf_page_count((*C.uint8_t)(buf), C.uintptr_t(size), password)
+	if count < 0 {
+		return 0, lastError()
+	}
+	return int(count), nil
+}
+
+// renderedPixels holds the RGB pixels of a rendered page, copied to Go memory.
+type renderedPixels struct {
+	rgb           []byte
+	width, height int
+}
+
+// renderPDFPages renders the 1-indexed pages with one document load and copies their
+// RGB pixels to Go memory, so that the FFI lock is held only while PDFium renders.
+func renderPDFPages(buf unsafe.Pointer, size int, numbers []int, options, password *C.char) ([]renderedPixels, error) {
+	indices := C.malloc(C.size_t(len(numbers)) * C.size_t(unsafe.Sizeof(C.uintptr_t(0))))
+	defer C.free(indices)
+	cIndices := unsafe.Slice((*C.uintptr_t)(indices), len(numbers))
+	for i, number := range numbers {
+		cIndices[i] = C.uintptr_t(number - 1)
+	}
+
+	ffiMutex.Lock()
+	defer ffiMutex.Unlock()
+
+	batch := C.kreuzberg_render_pdf_pages((*C.uint8_t)(buf), C.uintptr_t(size), (*C.uintptr_t)(indices), C.uintptr_t(len(numbers)), options, password)
+	if batch == nil {
+		return nil, lastError()
+	}
+	defer C.kreuzberg_free_rendered_page_batch(batch)
+
+	if int(batch.count) != len(numbers) {
+		return nil, newRuntimeErrorWithContext(fmt.Sprintf("rendered %d pages, want %d", batch.count, len(numbers)), nil, ErrorCodeInternal, nil)
+	}
+	pages := unsafe.Slice(batch.pages, len(numbers))
+	rendered := make([]renderedPixels, len(numbers))
+	for i, page := range pages {
+		width, height := int(page.width), int(page.height)
+		if int(page.pixels_len) != width*height*3 {
+			return nil, newRuntimeErrorWithContext(fmt.Sprintf("rendered page %d has %d bytes of pixels for %dx%d", numbers[i], page.pixels_len, width, height), nil, ErrorCodeInternal, nil)
+		}
+		rendered[i] = renderedPixels{
+			rgb:    C.GoBytes(unsafe.Pointer(page.pixels), C.int(page.pixels_len)),
+			width:  width,
+			height: height,
+		}
+	}
+	return rendered, nil
+}
+
+// resolveRenderPages turns the requested page numbers into 1-indexed pages of a
+// document of total pages.
+func resolveRenderPages(pages []int, total int) ([]int, error) {
+	if len(pages) == 0 {
+		numbers := make([]int, total)
+		for i := range numbers {
+			numbers[i] = i + 1
+		}
+		return numbers, nil
+	}
+	numbers := make([]int, len(pages))
+	for i, page := range pages {
+		number := page
+		if page < 0 {
+			number = total + 1 + page
+		}
+		if page == 0 || number < 1 || number > total {
+			return nil, newValidationErrorWithContext(fmt.Sprintf("page %d is out of range for a document of %d pages", page, total), nil, ErrorCodeValidation, nil)
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// nativeRenderOptions mirrors the native PageRenderOptions.
+type nativeRenderOptions struct {
+	TargetDPI         int  `json:"target_dpi"`
+	MaxImageDimension int  `json:"max_image_dimension"`
+	AutoAdjustDPI     bool `json:"auto_adjust_dpi"`
+	MinDPI            int  `json:"min_dpi"`
+	MaxDPI            int  `json:"max_dpi"`
+}
+
+type renderSettings struct {
+	nativeOptions string
+	format        PageImageFormat
+	jpegQuality   int
+	maxBytes      int
+	password      string
+}
+
+func newRenderSettings(opts *PageImageOptions) (*renderSettings, error) {
+	var resolved PageImageOptions
+	if opts != nil {
+		resolved = *opts
+	}
+
+	native := nativeRenderOptions{
+		TargetDPI:         DefaultRenderTargetDPI,
+		MaxImageDimension: DefaultRenderMaxImageDimension,
+		AutoAdjustDPI:     true,
+		MinDPI:            DefaultRenderMinDPI,
+		MaxDPI:            DefaultRenderMaxDPI,
+	}
+	if dpi := resolved.DPI; dpi != nil {
+		setInt := func(dst *int, src *int) {
+			if src != nil {
+				*dst = *src
+			}
+		}
+		setInt(&native.TargetDPI, dpi.TargetDPI)
+		setInt(&native.MaxImageDimension, dpi.MaxImageDimension)
+		setInt(&native.MinDPI, dpi.MinDPI)
+		setInt(&native.MaxDPI, dpi.MaxDPI)
+		if dpi.AutoAdjustDPI != nil {
+			native.AutoAdjustDPI = *dpi.AutoAdjustDPI
+		}
+	}
+	if native.TargetDPI <= 0 || native.MaxImageDimension <= 0 || native.MinDPI <= 0 || native.MaxDPI < native.MinDPI {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("invalid DPI settings: target %d, max dimension %d, range %d-%d", native.TargetDPI, native.MaxImageDimension, native.MinDPI, native.MaxDPI), nil, ErrorCodeValidation, nil)
+	}
+	encoded, err := json.Marshal(native)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode render options", err, ErrorCodeValidation, nil)
+	}
+
+	settings := &renderSettings{
+		nativeOptions: string(encoded),
+		format:        PageImageFormat(strings.ToLower(string(resolved.Format))),
+		jpegQuality:   resolved.JPEGQuality,
+		maxBytes:      resolved.MaxBytes,
+		password:      resolved.Password,
+	}
+	switch settings.format {
+	case "":
+		settings.format = PageImagePNG
+	case "jpg":
+		settings.format = PageImageJPEG
+	case PageImagePNG, PageImageJPEG:
+	default:
+		return nil, newValidationErrorWithContext(fmt.Sprintf("unsupported page image format %q", resolved.Format), nil, ErrorCodeValidation, nil)
+	}
+	if settings.jpegQuality == 0 {
+		settings.jpegQuality = DefaultRenderJPEGQuality
+	}
+	if settings.jpegQuality < 1 || settings.jpegQuality > 100 {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("JPEG quality %d is outside 1-100", settings.jpegQuality), nil, ErrorCodeValidation, nil)
+	}
+	if settings.maxBytes <= 0 {
+		settings.maxBytes = DefaultRenderMaxBytes
+	}
+	return settings, nil
+}
+
+// encode encodes RGB pixels in the configured format.
+func (s *renderSettings) encode(rgb []byte, width, height int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for src, dst := 0, 0; src+2 < len(rgb); src, dst = src+3, dst+4 {
+		img.Pix[dst] = rgb[src]
+		img.Pix[dst+1] = rgb[src+1]
+		img.Pix[dst+2] = rgb[src+2]
+		img.Pix[dst+3] = 0xff
+	}
+
+	var out bytes.Buffer
+	var err error
+	if s.format == PageImageJPEG {
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: s.jpegQuality})
+	} else {
+		err = png.Encode(&out, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if out.Len() > s.maxBytes {
+		return nil, fmt.Errorf("encoded image is %d bytes, exceeding the limit of %d", out.Len(), s.maxBytes)
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,184 @@
+package kreuzberg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestResolveRenderPages(t *testing.T) {
+	if got, err := resolveRenderPages(nil, 3); err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("resolveRenderPages(nil, 3) = %v, %v", got, err)
+	}
+	if got, err := resolveRenderPages([]int{2, -1, 1}, 5); err != nil || !reflect.DeepEqual(got, []int{2, 5, 1}) {
+		t.Errorf("resolveRenderPages([2 -1 1], 5) = %v, %v", got, err)
+	}
+	for _, page := range []int{0, 6, -6} {
+		_, err := resolveRenderPages([]int{page}, 5)
+		var validation *ValidationError
+		if !errors.As(err, &validation) {
+			t.Errorf("resolveRenderPages([%d], 5) error = %v, want validation error", page, err)
+		}
+	}
+}
+
+func TestNewRenderSettings(t *testing.T) {
+	settings, err := newRenderSettings(nil)
+	if err != nil {
+		t.Fatalf("newRenderSettings(nil) error = %v", err)
+	}
+	if settings.format != PageImagePNG || settings.jpegQuality != DefaultRenderJPEGQuality || settings.maxBytes != DefaultRenderMaxBytes {
+		t.Errorf("settings = %+v", settings)
+	}
+	var native map[string]any
+	if err := json.Unmarshal([]byte(settings.nativeOptions), &native); err != nil {
+		t.Fatalf("native options %q: %v", settings.nativeOptions, err)
+	}
+	want := map[string]any{"target_dpi": 300.0, "max_image_dimension": 4096.0, "auto_adjust_dpi": true, "min_dpi": 72.0, "max_dpi": 600.0}
+	if !reflect.DeepEqual(native, want) {
+		t.Errorf("native options = %v, want %v", native, want)
+	}
+
+	settings, err = newRenderSettings(&PageImageOptions{
+		DPI:    &ImageExtractionConfig{TargetDPI: IntPtr(150), AutoAdjustDPI: BoolPtr(false)},
+		Format: "JPG",
+	})
+	if err != nil {
+		t.Fatalf("newRenderSettings() error = %v", err)
+	}
+	if settings.format != PageImageJPEG {
+		t.Errorf("format = %q, want jpeg", settings.format)
+	}
+	if want := `{"target_dpi":150,"max_image_dimension":4096,"auto_adjust_dpi":false,"min_dpi":72,"max_dpi":600}`; settings.nativeOptions != want {
+		t.Errorf("native options = %s, want %s", settings.nativeOptions, want)
+	}
+
+	for _, opts := range []*PageImageOptions{
+		{Format: "gif"},
+		{JPEGQuality: 101},
+		{DPI: &ImageExtractionConfig{TargetDPI: IntPtr(0)}},
+		{DPI: &ImageExtractionConfig{MinDPI: IntPtr(300), MaxDPI: IntPtr(100)}},
+	} {
+		if _, err := newRenderSettings(opts); err == nil {
+			t.Errorf("newRenderSettings(%+v) succeeded, want an error", opts)
+		}
+	}
+}
+
+func TestRenderSettingsEncode(t *testing.T) {
+	rgb := make([]byte, 4*2*3)
+	for i := range rgb {
+		rgb[i] = byte(i * 10)
+	}
+
+	settings, _ := newRenderSettings(nil)
+	data, err := settings.encode(rgb, 4, 2)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if r, g, b, a := img.At(1, 1).RGBA(); r>>8 != 150 || g>>8 != 160 || b>>8 != 170 || a>>8 != 255 {
+		t.Errorf("pixel (1, 1) = %d %d %d %d", r>>8, g>>8, b>>8, a>>8)
+	}
+
+	settings, _ = newRenderSettings(&PageImageOptions{Format: PageImageJPEG})
+	data, err = settings.encode(rgb, 4, 2)
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(data)); err != nil || cfg.Width != 4 || cfg.Height != 2 {
+		t.Errorf("jpeg.DecodeConfig() = %+v, %v", cfg, err)
+	}
+
+	settings, _ = newRenderSettings(&PageImageOptions{MaxBytes: 10})
+	if _, err := settings.encode(rgb, 4, 2); err == nil {
+		t.Error("expected an error for images over MaxBytes")
+	}
+}
+
+func TestRenderBatches(t *testing.T) {
+	got := renderBatches([]int{1, 2, 3, 4, 5, 6, 9})
+	want := [][]int{{1, 2, 3, 4}, {5, 6, 9}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("renderBatches() = %v, want %v", got, want)
+	}
+	if got := renderBatches(nil); got != nil {
+		t.Errorf("renderBatches(nil) = %v, want no batches", got)
+	}
+}
+
+func TestStreamPagesValidation(t *testing.T) {
+	noop := func(*RenderedPage) error { return nil }
+	if err := StreamPages("", nil, nil, noop); err == nil {
+		t.Error("expected an error for an empty path")
+	}
+	if err := StreamPages("doc.pdf", nil, nil, nil); err == nil {
+		t.Error("expected an error for a nil callback")
+	}
+	var ioErr *IOError
+	if err := StreamPages("does-not-exist.pdf", nil, nil, noop); !errors.As(err, &ioErr) {
+		t.Errorf("StreamPages() error = %v, want IO error", err)
+	}
+}
+
+func TestRenderPages(t *testing.T) {
+	path := getTestFilePath("pdf/tiny.pdf")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	pages, err := RenderPages(path, []int{1}, &ImageExtractionConfig{TargetDPI: IntPtr(72)}, PageImagePNG)
+	if err != nil {
+		t.Fatalf("RenderPages() error = %v", err)
+	}
+	if len(pages) != 1 || pages[0].PageNumber != 1 || pages[0].Width == 0 || pages[0].Height == 0 {
+		t.Fatalf("pages = %+v", pages)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(pages[0].Data))
+	if err != nil || cfg.Width != pages[0].Width || cfg.Height != pages[0].Height {
+		t.Errorf("png.DecodeConfig() = %+v, %v", cfg, err)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = StreamPages(path, nil, nil, func(*RenderedPage) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("StreamPages() = %v after %d calls, want the callback error after 1", err, calls)
+	}
+}
+
+func TestStreamPagesAcrossBatches(t *testing.T) {
+	path := getTestFilePath("pdf/multi_page.pdf")
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("test file not found: %s", path)
+	}
+	var numbers []int
+	err := StreamPages(path, nil, &PageImageOptions{DPI: &ImageExtractionConfig{TargetDPI: IntPtr(36)}}, func(page *RenderedPage) error {
+		if page.Width == 0 || page.Height == 0 || len(page.Data) == 0 {
+			t.Errorf("page %d = %dx%d with %d bytes", page.PageNumber, page.Width, page.Height, len(page.Data))
+		}
+		numbers = append(numbers, page.PageNumber)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamPages() error = %v", err)
+	}
+	for i, number := range numbers {
+		if number != i+1 {
+			t.Fatalf("pages = %v, want every page in order", numbers)
+		}
+	}
+	if len(numbers) < 2 {
+		t.Errorf("pages = %v, want several pages", numbers)
+	}
+}
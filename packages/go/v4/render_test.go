@@ -0,0 +1,228 @@
+package kreuzberg_test
+
+import (
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func newRenderDjotResult() *kreuzberg.ExtractionResult {
+	level := uint64(2)
+	lang := "go"
+	code := "fmt.Println(\"<hi>\")\n"
+	text := func(s string) []kreuzberg.InlineElement {
+		return []kreuzberg.InlineElement{{ElementType: kreuzberg.InlineTypeText, Content: s}}
+	}
+	return &kreuzberg.ExtractionResult{
+		DjotContent: &kreuzberg.DjotContent{
+			Blocks: []kreuzberg.FormattedBlock{
+				{BlockType: kreuzberg.BlockTypeHeading, Level: &level, InlineContent: text("Intro")},
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: []kreuzberg.InlineElement{
+					{ElementType: kreuzberg.InlineTypeText, Content: "See "},
+					{ElementType: kreuzberg.InlineTypeLink, Content: "docs", Metadata: map[string]string{"href": "https://example.com"}},
+					{ElementType: kreuzberg.InlineTypeText, Content: " and "},
+					{ElementType: kreuzberg.InlineTypeLink, Content: "this", Metadata: map[string]string{"href": "javascript:alert(1)"}},
+				}},
+				{BlockType: kreuzberg.BlockTypeOrderedList, Children: []kreuzberg.FormattedBlock{
+					{BlockType: kreuzberg.BlockTypeListItem, InlineContent: text("first")},
+					{BlockType: kreuzberg.BlockTypeListItem, InlineContent: text("second"), Children: []kreuzberg.FormattedBlock{
+						{BlockType: kreuzberg.BlockTypeBulletList, Children: []kreuzberg.FormattedBlock{
+							{BlockType: kreuzberg.BlockTypeListItem, InlineContent: text("nested")},
+						}},
+					}},
+				}},
+				{BlockType: kreuzberg.BlockTypeCodeBlock, Language: &lang, Code: &code},
+				{BlockType: kreuzberg.BlockTypeRawBlock, Code: kreuzberg.StringPtr("<script>x()</script>")},
+			},
+			Tables: []kreuzberg.Table{{Cells: [][]string{{"A", "B|C"}, {"1", "2"}}}},
+		},
+	}
+}
+
+func TestRenderMarkdownDjot(t *testing.T) {
+	got, err := kreuzberg.RenderMarkdown(newRenderDjotResult(), nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"## Intro\n",
+		"See [docs](https://example.com) and [this](javascript:alert\\(1\\))",
+		"1. first\n2. second\n   - nested",
+		"```go\nfmt.Println(\"<hi>\")\n```",
+		"| A | B\\|C |\n| --- | --- |\n| 1 | 2 |",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderMarkdownEscapesText(t *testing.T) {
+	text := func(s string) []kreuzberg.InlineElement {
+		return []kreuzberg.InlineElement{{ElementType: kreuzberg.InlineTypeText, Content: s}}
+	}
+	result := &kreuzberg.ExtractionResult{
+		DjotContent: &kreuzberg.DjotContent{
+			Blocks: []kreuzberg.FormattedBlock{
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: text("# not a heading")},
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: text("1. not a list\n- nor this")},
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: []kreuzberg.InlineElement{
+					{ElementType: kreuzberg.InlineTypeText, Content: "a *b* [c] d|e "},
+					{ElementType: kreuzberg.InlineTypeCode, Content: "*raw*"},
+				}},
+			},
+		},
+	}
+	got, err := kreuzberg.RenderMarkdown(result, nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"\\# not a heading\n",
+		"1\\. not a list\n\\- nor this\n",
+		"a \\*b\\* \\[c\\] d\\|e `*raw*`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("markdown missing %q in:\n%s", want, got)
+		}
+	}
+
+	got, err = kreuzberg.RenderMarkdown(&kreuzberg.ExtractionResult{Content: "2024. A year"}, &kreuzberg.RenderOptions{Source: kreuzberg.RenderSourceContent})
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	if want := "2024\\. A year\n"; got != want {
+		t.Errorf("RenderMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderMarkdownPlacesDjotTables(t *testing.T) {
+	text := func(s string) []kreuzberg.InlineElement {
+		return []kreuzberg.InlineElement{{ElementType: kreuzberg.InlineTypeText, Content: s}}
+	}
+	result := &kreuzberg.ExtractionResult{
+		DjotContent: &kreuzberg.DjotContent{
+			Blocks: []kreuzberg.FormattedBlock{
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: text("Before")},
+				{BlockType: kreuzberg.BlockTypeTable, TableIndex: kreuzberg.Uint64Ptr(0)},
+				{BlockType: kreuzberg.BlockTypeParagraph, InlineContent: text("After")},
+			},
+			Tables: []kreuzberg.Table{{Cells: [][]string{{"A"}, {"1"}}}},
+		},
+	}
+	got, err := kreuzberg.RenderMarkdown(result, nil)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	if want := "Before\n\n| A |\n| --- |\n| 1 |\n\nAfter\n"; got != want {
+		t.Errorf("RenderMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderHTMLSanitizes(t *testing.T) {
+	got, err := kreuzberg.RenderHTML(newRenderDjotResult(), nil)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		"<h2>Intro</h2>",
+		`<a href="https://example.com">docs</a> and this</p>`,
+		"<ol>\n<li>first</li>\n<li>second\n<ul>\n<li>nested</li>\n</ul>\n</li>\n</ol>",
+		`<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>`,
+		"<pre>&lt;script&gt;x()&lt;/script&gt;</pre>",
+		"<th>B|C</th>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("html missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "javascript:") || strings.Contains(got, "<script>") {
+		t.Errorf("html contains unsafe content:\n%s", got)
+	}
+}
+
+func TestRenderElementsWithPageMarkers(t *testing.T) {
+	page := func(n uint64) kreuzberg.ElementMetadata {
+		return kreuzberg.ElementMetadata{PageNumber: &n}
+	}
+	withAdditional := func(m kreuzberg.ElementMetadata, kv map[string]string) kreuzberg.ElementMetadata {
+		m.Additional = kv
+		return m
+	}
+	result := &kreuzberg.ExtractionResult{
+		Elements: []kreuzberg.Element{
+			{ElementType: kreuzberg.ElementTypeTitle, Text: "Report", Metadata: withAdditional(page(1), map[string]string{"level": "h1"})},
+			{ElementType: kreuzberg.ElementTypeListItem, Text: "- alpha", Metadata: page(1)},
+			{ElementType: kreuzberg.ElementTypeListItem, Text: "- beta", Metadata: page(1)},
+			{ElementType: kreuzberg.ElementTypeHeader, Text: "ACME Corp", Metadata: page(2)},
+			{ElementType: kreuzberg.ElementTypeNarrativeText, Text: "Body text.", Metadata: page(2)},
+			{ElementType: kreuzberg.ElementTypeTable, Text: "x\ty\n1\t2", Metadata: page(2)},
+		},
+	}
+	opts := &kreuzberg.RenderOptions{PageMarkers: true}
+
+	md, err := kreuzberg.RenderMarkdown(result, opts)
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	want := "<!-- PAGE 1 -->\n\n# Report\n\n- alpha\n- beta\n\n<!-- PAGE 2 -->\n\nBody text.\n\n| x | y |\n| --- | --- |\n| 1 | 2 |\n"
+	if md != want {
+		t.Errorf("RenderMarkdown() =\n%q\nwant\n%q", md, want)
+	}
+
+	text, err := kreuzberg.RenderPlainText(result, opts)
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	want = "--- Page 1 ---\n\nReport\n\n- alpha\n- beta\n\n--- Page 2 ---\n\nBody text.\n\nx\ty\n1\t2\n"
+	if text != want {
+		t.Errorf("RenderPlainText() =\n%q\nwant\n%q", text, want)
+	}
+
+	htmlOut, err := kreuzberg.RenderHTML(result, opts)
+	if err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if !strings.Contains(htmlOut, `<div class="page-marker" data-page="2">Page 2</div>`) {
+		t.Errorf("html missing page marker:\n%s", htmlOut)
+	}
+}
+
+func TestRenderPagesAndContent(t *testing.T) {
+	result := &kreuzberg.ExtractionResult{
+		Content: "First paragraph.\n\nSecond paragraph.",
+		Pages: []kreuzberg.PageContent{
+			{PageNumber: 1, Content: "One."},
+			{PageNumber: 2, Content: "Two.", Tables: []kreuzberg.Table{{Cells: [][]string{{"h"}, {"v"}}}}},
+		},
+	}
+
+	got, err := kreuzberg.RenderPlainText(result, &kreuzberg.RenderOptions{PageMarkers: true, PageMarkerFormat: "[{page_num}]"})
+	if err != nil {
+		t.Fatalf("RenderPlainText() error = %v", err)
+	}
+	if want := "[1]\n\nOne.\n\n[2]\n\nTwo.\n\nh\nv\n"; got != want {
+		t.Errorf("RenderPlainText() = %q, want %q", got, want)
+	}
+
+	got, err = kreuzberg.RenderMarkdown(result, &kreuzberg.RenderOptions{Source: kreuzberg.RenderSourceContent})
+	if err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+	if want := "First paragraph.\n\nSecond paragraph.\n"; got != want {
+		t.Errorf("RenderMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := kreuzberg.RenderMarkdown(nil, nil); err == nil {
+		t.Error("nil result should fail")
+	}
+	if _, err := kreuzberg.RenderHTML(&kreuzberg.ExtractionResult{}, &kreuzberg.RenderOptions{Source: kreuzberg.RenderSourceDjot}); err == nil {
+		t.Error("djot source without djot content should fail")
+	}
+	if _, err := kreuzberg.RenderPlainText(&kreuzberg.ExtractionResult{}, &kreuzberg.RenderOptions{Source: "bogus"}); err == nil {
+		t.Error("unknown source should fail")
+	}
+}
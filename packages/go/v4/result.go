@@ -3,6 +3,7 @@ package kreuzberg
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 /*
@@ -55,9 +56,13 @@ type MetadataField struct {
 }
 
 // GetMetadataField retrieves a metadata field from the extraction result.
-// Field paths use dot notation for nested fields (e.g., "language", "pdf.page_count").
+// A field name is first looked up as a literal key, so custom keys containing dots
+// (e.g., "dc.title") are found as-is. Otherwise dot notation addresses nested fields
+// (e.g., "pages.total_count", "pdf.page_count"); format-specific fields can also be
+// addressed directly (e.g., "page_count").
 // Returns the field value parsed as a Go interface{}, or an error if retrieval fails.
 // If the field doesn't exist, IsNull will be true in the returned MetadataField.
+// Use Query for typed values and paths outside metadata.
 func (r *ExtractionResult) GetMetadataField(fieldName string) (*MetadataField, error) {
 	if fieldName == "" {
 		return nil, newValidationErrorWithContext("field name cannot be empty", nil, ErrorCodeValidation, nil)
@@ -74,29 +79,48 @@ func (r *ExtractionResult) GetMetadataField(fieldName string) (*MetadataField, e
 	}
 
 	value, exists := metadataMap[fieldName]
-	if !exists {
-		return &MetadataField{
-			Name:   fieldName,
-			Value:  nil,
-			IsNull: true,
-		}, nil
-	}
-
-	if value == nil {
-		return &MetadataField{
-			Name:   fieldName,
-			Value:  nil,
-			IsNull: true,
-		}, nil
+	if !exists || value == nil {
+		value, err = r.metadataPathValue(fieldName)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return &MetadataField{
 		Name:   fieldName,
 		Value:  value,
-		IsNull: false,
+		IsNull: value == nil,
 	}, nil
 }
 
+// metadataPathValue resolves a dotted metadata path with Query, returning the value in
+// the JSON shape GetMetadataField has always returned. Paths that do not resolve yield
+// nil.
+func (r *ExtractionResult) metadataPathValue(fieldName string) (interface{}, error) {
+	var path strings.Builder
+	path.WriteString("metadata")
+	for _, part := range strings.Split(fieldName, ".") {
+		path.WriteString(`["`)
+		path.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(part))
+		path.WriteString(`"]`)
+	}
+
+	value, err := r.Query(path.String())
+	if err != nil || value == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode metadata", err, ErrorCodeValidation, nil)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, newSerializationErrorWithContext("failed to parse metadata", err, ErrorCodeValidation, nil)
+	}
+	return decoded, nil
+}
+
 // ResultToJSON serializes an ExtractionResult to a JSON string.
 // This is useful for passing results through FFI or storing them.
 func ResultToJSON(result *ExtractionResult) (string, error) {
@@ -123,6 +147,7 @@ func ResultFromJSON(jsonStr string) (*ExtractionResult, error) {
 	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
 		return nil, newSerializationErrorWithContext("failed to decode result JSON", err, ErrorCodeValidation, nil)
 	}
+	result.Metadata.inferFormat(result.MimeType)
 
 	return &result, nil
 }
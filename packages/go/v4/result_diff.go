@@ -0,0 +1,936 @@
+package kreuzberg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultDiffContextLines is the number of unchanged lines shown around each change in
+// the unified content diff.
+const DefaultDiffContextLines = 3
+
+// DiffOptions controls DiffResults.
+type DiffOptions struct {
+	// IgnoreWhitespace compares text with runs of whitespace collapsed and leading and
+	// trailing whitespace removed. Blank lines are skipped in the content diff and chunk
+	// boundaries are compared by their position among non-whitespace characters.
+	IgnoreWhitespace bool
+	// FloatTolerance is the largest absolute difference at which two bounding box
+	// coordinates or numeric metadata values are still considered equal.
+	FloatTolerance float64
+	// ContextLines is the number of unchanged lines shown around each content change.
+	// Zero uses DefaultDiffContextLines; a negative value shows no context.
+	ContextLines int
+}
+
+// ChangeKind classifies an entry of a ResultDiff.
+type ChangeKind string
+
+const (
+	// ChangeAdded marks an item present only in the second result.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved marks an item present only in the first result.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified marks an item present in both results with different values.
+	ChangeModified ChangeKind = "modified"
+)
+
+// DiffOp is the operation of a line in a DiffHunk.
+type DiffOp string
+
+const (
+	// DiffEqual marks a context line present in both contents.
+	DiffEqual DiffOp = "equal"
+	// DiffDelete marks a line present only in the first content.
+	DiffDelete DiffOp = "delete"
+	// DiffInsert marks a line present only in the second content.
+	DiffInsert DiffOp = "insert"
+)
+
+// DiffLine is a single line of a DiffHunk.
+type DiffLine struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffHunk is a group of nearby content changes with their context, as in a unified
+// diff. Line numbers are 1-indexed; a start of 0 means the hunk begins before the
+// first line.
+type DiffHunk struct {
+	OldStart int        `json:"old_start"`
+	OldLines int        `json:"old_lines"`
+	NewStart int        `json:"new_start"`
+	NewLines int        `json:"new_lines"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+// CellChange is a table cell whose text differs. A cell missing on one side has an
+// empty Old or New value.
+type CellChange struct {
+	Row int    `json:"row"`
+	Col int    `json:"col"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// TableChange describes a table that was added, removed or modified. OldIndex and
+// NewIndex point into the Tables of each result and are -1 on the side where the
+// table does not exist; rows and columns describe the table shape on each side.
+type TableChange struct {
+	Kind     ChangeKind   `json:"kind"`
+	OldIndex int          `json:"old_index"`
+	NewIndex int          `json:"new_index"`
+	OldPage  int          `json:"old_page"`
+	NewPage  int          `json:"new_page"`
+	OldRows  int          `json:"old_rows"`
+	OldCols  int          `json:"old_cols"`
+	NewRows  int          `json:"new_rows"`
+	NewCols  int          `json:"new_cols"`
+	Cells    []CellChange `json:"cells,omitempty"`
+}
+
+// MetadataChange describes a metadata field that was added, removed or modified. Path
+// uses the JSON field names of Metadata with format-specific fields flattened, e.g.
+// "title", "page_count" or "pages.boundaries[0].byte_end".
+type MetadataChange struct {
+	Kind ChangeKind  `json:"kind"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// ChunkChange describes a chunk that was added, removed, changed its text, or kept its
+// text but moved its byte boundaries. Indexes are -1 on the side where the chunk does
+// not exist.
+type ChunkChange struct {
+	Kind           ChangeKind `json:"kind"`
+	OldIndex       int        `json:"old_index"`
+	NewIndex       int        `json:"new_index"`
+	OldByteStart   uint64     `json:"old_byte_start"`
+	OldByteEnd     uint64     `json:"old_byte_end"`
+	NewByteStart   uint64     `json:"new_byte_start"`
+	NewByteEnd     uint64     `json:"new_byte_end"`
+	ContentChanged bool       `json:"content_changed"`
+}
+
+// ElementChange describes an element that was added, removed or modified. Fields lists
+// what changed on a modified element: "type", "text", "page" and "coordinates".
+type ElementChange struct {
+	Kind           ChangeKind   `json:"kind"`
+	OldIndex       int          `json:"old_index"`
+	NewIndex       int          `json:"new_index"`
+	Fields         []string     `json:"fields,omitempty"`
+	OldType        ElementType  `json:"old_type,omitempty"`
+	NewType        ElementType  `json:"new_type,omitempty"`
+	OldText        string       `json:"old_text,omitempty"`
+	NewText        string       `json:"new_text,omitempty"`
+	OldPage        *uint64      `json:"old_page,omitempty"`
+	NewPage        *uint64      `json:"new_page,omitempty"`
+	OldCoordinates *BoundingBox `json:"old_coordinates,omitempty"`
+	NewCoordinates *BoundingBox `json:"new_coordinates,omitempty"`
+}
+
+// ResultDiff is the structural difference between two extraction results. It encodes
+// to JSON for machine consumption; String renders a human-readable report.
+type ResultDiff struct {
+	Content  []DiffHunk       `json:"content,omitempty"`
+	Tables   []TableChange    `json:"tables,omitempty"`
+	Metadata []MetadataChange `json:"metadata,omitempty"`
+	Chunks   []ChunkChange    `json:"chunks,omitempty"`
+	Elements []ElementChange  `json:"elements,omitempty"`
+}
+
+// DiffResults compares two extraction results, e.g. a golden output against the output
+// of a new library version or configuration. A nil result is treated as empty.
+func DiffResults(a, b *ExtractionResult, opts *DiffOptions) (*ResultDiff, error) {
+	var resolved DiffOptions
+	if opts != nil {
+		resolved = *opts
+	}
+	if resolved.ContextLines == 0 {
+		resolved.ContextLines = DefaultDiffContextLines
+	} else if resolved.ContextLines < 0 {
+		resolved.ContextLines = 0
+	}
+	if a == nil {
+		a = &ExtractionResult{}
+	}
+	if b == nil {
+		b = &ExtractionResult{}
+	}
+
+	metadata, err := diffMetadata(&a.Metadata, &b.Metadata, &resolved)
+	if err != nil {
+		return nil, err
+	}
+	return &ResultDiff{
+		Content:  diffContent(a.Content, b.Content, &resolved),
+		Tables:   diffTables(a.Tables, b.Tables, &resolved),
+		Metadata: metadata,
+		Chunks:   diffChunks(a, b, &resolved),
+		Elements: diffElements(a.Elements, b.Elements, &resolved),
+	}, nil
+}
+
+// Equal reports whether the diff holds no changes.
+func (d *ResultDiff) Equal() bool {
+	return d == nil || len(d.Content)+len(d.Tables)+len(d.Metadata)+len(d.Chunks)+len(d.Elements) == 0
+}
+
+// UnifiedContent renders the content changes as a unified diff, or returns an empty
+// string when the content is unchanged.
+func (d *ResultDiff) UnifiedContent() string {
+	if d == nil || len(d.Content) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("--- a\n+++ b\n")
+	for _, hunk := range d.Content {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunk.OldStart, hunk.OldLines), hunkRange(hunk.NewStart, hunk.NewLines))
+		for _, line := range hunk.Lines {
+			switch line.Op {
+			case DiffDelete:
+				sb.WriteByte('-')
+			case DiffInsert:
+				sb.WriteByte('+')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
+
+// String renders a human-readable report of the diff.
+func (d *ResultDiff) String() string {
+	if d.Equal() {
+		return "no differences\n"
+	}
+	var sb strings.Builder
+	if len(d.Content) > 0 {
+		sb.WriteString("content:\n")
+		sb.WriteString(d.UnifiedContent())
+	}
+	if len(d.Tables) > 0 {
+		sb.WriteString("tables:\n")
+		for _, c := range d.Tables {
+			switch c.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&sb, "  + table %d (page %d, %dx%d)\n", c.NewIndex, c.NewPage, c.NewRows, c.NewCols)
+			case ChangeRemoved:
+				fmt.Fprintf(&sb, "  - table %d (page %d, %dx%d)\n", c.OldIndex, c.OldPage, c.OldRows, c.OldCols)
+			default:
+				fmt.Fprintf(&sb, "  ~ table %d -> %d", c.OldIndex, c.NewIndex)
+				if c.OldPage != c.NewPage {
+					fmt.Fprintf(&sb, " (page %d -> %d)", c.OldPage, c.NewPage)
+				}
+				if c.OldRows != c.NewRows || c.OldCols != c.NewCols {
+					fmt.Fprintf(&sb, " (%dx%d -> %dx%d)", c.OldRows, c.OldCols, c.NewRows, c.NewCols)
+				}
+				sb.WriteByte('\n')
+				for _, cell := range c.Cells {
+					fmt.Fprintf(&sb, "      [%d,%d] %q -> %q\n", cell.Row, cell.Col, cell.Old, cell.New)
+				}
+			}
+		}
+	}
+	if len(d.Metadata) > 0 {
+		sb.WriteString("metadata:\n")
+		for _, c := range d.Metadata {
+			switch c.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&sb, "  + %s: %s\n", c.Path, diffValueString(c.New))
+			case ChangeRemoved:
+				fmt.Fprintf(&sb, "  - %s: %s\n", c.Path, diffValueString(c.Old))
+			default:
+				fmt.Fprintf(&sb, "  ~ %s: %s -> %s\n", c.Path, diffValueString(c.Old), diffValueString(c.New))
+			}
+		}
+	}
+	if len(d.Chunks) > 0 {
+		sb.WriteString("chunks:\n")
+		for _, c := range d.Chunks {
+			switch c.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&sb, "  + chunk %d (bytes %d-%d)\n", c.NewIndex, c.NewByteStart, c.NewByteEnd)
+			case ChangeRemoved:
+				fmt.Fprintf(&sb, "  - chunk %d (bytes %d-%d)\n", c.OldIndex, c.OldByteStart, c.OldByteEnd)
+			default:
+				fmt.Fprintf(&sb, "  ~ chunk %d -> %d: bytes %d-%d -> %d-%d", c.OldIndex, c.NewIndex,
+					c.OldByteStart, c.OldByteEnd, c.NewByteStart, c.NewByteEnd)
+				if c.ContentChanged {
+					sb.WriteString(", text changed")
+				}
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	if len(d.Elements) > 0 {
+		sb.WriteString("elements:\n")
+		for _, c := range d.Elements {
+			switch c.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&sb, "  + element %d %s %q\n", c.NewIndex, c.NewType, diffExcerpt(c.NewText))
+			case ChangeRemoved:
+				fmt.Fprintf(&sb, "  - element %d %s %q\n", c.OldIndex, c.OldType, diffExcerpt(c.OldText))
+			default:
+				fmt.Fprintf(&sb, "  ~ element %d -> %d %q:", c.OldIndex, c.NewIndex, diffExcerpt(c.NewText))
+				for _, field := range c.Fields {
+					switch field {
+					case "type":
+						fmt.Fprintf(&sb, " type %s -> %s;", c.OldType, c.NewType)
+					case "text":
+						fmt.Fprintf(&sb, " text was %q;", diffExcerpt(c.OldText))
+					case "page":
+						fmt.Fprintf(&sb, " page %s -> %s;", diffPageString(c.OldPage), diffPageString(c.NewPage))
+					case "coordinates":
+						fmt.Fprintf(&sb, " coordinates %s -> %s;", diffBoxString(c.OldCoordinates), diffBoxString(c.NewCoordinates))
+					}
+				}
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	return sb.String()
+}
+
+type diffTextLine struct {
+	text string
+	key  string
+	line int
+}
+
+func diffContent(a, b string, opts *DiffOptions) []DiffHunk {
+	if a == b {
+		return nil
+	}
+	oldLines := splitDiffLines(a, opts)
+	newLines := splitDiffLines(b, opts)
+	edits := diffSequence(len(oldLines), len(newLines), func(i, j int) bool {
+		return oldLines[i].key == newLines[j].key
+	})
+
+	var changes []int
+	for i, e := range edits {
+		if e.kind != editEqual {
+			changes = append(changes, i)
+		}
+	}
+	context := opts.ContextLines
+	var hunks []DiffHunk
+	for start := 0; start < len(changes); {
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end] <= 2*context+1 {
+			end++
+		}
+		from := changes[start] - context
+		if from < 0 {
+			from = 0
+		}
+		to := changes[end] + context + 1
+		if to > len(edits) {
+			to = len(edits)
+		}
+		hunks = append(hunks, buildDiffHunk(edits, from, to, oldLines, newLines))
+		start = end + 1
+	}
+	return hunks
+}
+
+func buildDiffHunk(edits []edit, from, to int, oldLines, newLines []diffTextLine) DiffHunk {
+	var hunk DiffHunk
+	for _, e := range edits[:from] {
+		if e.kind != editInsert {
+			hunk.OldStart = oldLines[e.a].line
+		}
+		if e.kind != editDelete {
+			hunk.NewStart = newLines[e.b].line
+		}
+	}
+	oldSeen, newSeen := false, false
+	for _, e := range edits[from:to] {
+		if e.kind != editInsert {
+			if !oldSeen {
+				hunk.OldStart, oldSeen = oldLines[e.a].line, true
+			}
+			hunk.OldLines++
+		}
+		if e.kind != editDelete {
+			if !newSeen {
+				hunk.NewStart, newSeen = newLines[e.b].line, true
+			}
+			hunk.NewLines++
+		}
+		switch e.kind {
+		case editEqual:
+			hunk.Lines = append(hunk.Lines, DiffLine{Op: DiffEqual, Text: oldLines[e.a].text})
+		case editDelete:
+			hunk.Lines = append(hunk.Lines, DiffLine{Op: DiffDelete, Text: oldLines[e.a].text})
+		case editInsert:
+			hunk.Lines = append(hunk.Lines, DiffLine{Op: DiffInsert, Text: newLines[e.b].text})
+		}
+	}
+	return hunk
+}
+
+func splitDiffLines(s string, opts *DiffOptions) []diffTextLine {
+	if s == "" {
+		return nil
+	}
+	raw := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	lines := make([]diffTextLine, 0, len(raw))
+	for i, text := range raw {
+		key := normalizeDiffText(text, opts)
+		if opts.IgnoreWhitespace && key == "" {
+			continue
+		}
+		lines = append(lines, diffTextLine{text: text, key: key, line: i + 1})
+	}
+	return lines
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return strconv.Itoa(start) + "," + strconv.Itoa(count)
+}
+
+func diffTables(a, b []Table, opts *DiffOptions) []TableChange {
+	equalTables := func(x, y *Table) bool {
+		if x.PageNumber != y.PageNumber || len(x.Cells) != len(y.Cells) {
+			return false
+		}
+		for r := range x.Cells {
+			if len(x.Cells[r]) != len(y.Cells[r]) {
+				return false
+			}
+			for c := range x.Cells[r] {
+				if normalizeDiffText(x.Cells[r][c], opts) != normalizeDiffText(y.Cells[r][c], opts) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var changes []TableChange
+	for _, pair := range alignSequences(len(a), len(b), func(i, j int) bool { return equalTables(&a[i], &b[j]) }) {
+		change := TableChange{OldIndex: pair.a, NewIndex: pair.b}
+		if pair.a >= 0 {
+			change.OldPage, change.OldRows, change.OldCols = a[pair.a].PageNumber, len(a[pair.a].Cells), tableWidth(a[pair.a].Cells)
+		}
+		if pair.b >= 0 {
+			change.NewPage, change.NewRows, change.NewCols = b[pair.b].PageNumber, len(b[pair.b].Cells), tableWidth(b[pair.b].Cells)
+		}
+		switch {
+		case pair.a < 0:
+			change.Kind = ChangeAdded
+		case pair.b < 0:
+			change.Kind = ChangeRemoved
+		case pair.equal:
+			continue
+		default:
+			change.Kind = ChangeModified
+			change.Cells = diffCells(a[pair.a].Cells, b[pair.b].Cells, opts)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func diffCells(a, b [][]string, opts *DiffOptions) []CellChange {
+	cell := func(rows [][]string, r, c int) string {
+		if r < len(rows) && c < len(rows[r]) {
+			return rows[r][c]
+		}
+		return ""
+	}
+	rows := len(a)
+	if len(b) > rows {
+		rows = len(b)
+	}
+	var changes []CellChange
+	for r := 0; r < rows; r++ {
+		cols := 0
+		if r < len(a) {
+			cols = len(a[r])
+		}
+		if r < len(b) && len(b[r]) > cols {
+			cols = len(b[r])
+		}
+		for c := 0; c < cols; c++ {
+			old, cur := cell(a, r, c), cell(b, r, c)
+			if normalizeDiffText(old, opts) != normalizeDiffText(cur, opts) {
+				changes = append(changes, CellChange{Row: r, Col: c, Old: old, New: cur})
+			}
+		}
+	}
+	return changes
+}
+
+func diffMetadata(a, b *Metadata, opts *DiffOptions) ([]MetadataChange, error) {
+	oldFields, err := flattenDiffMetadata(a)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := flattenDiffMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(oldFields)+len(newFields))
+	for path := range oldFields {
+		paths = append(paths, path)
+	}
+	for path := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []MetadataChange
+	for _, path := range paths {
+		old, inOld := oldFields[path]
+		cur, inNew := newFields[path]
+		switch {
+		case !inOld:
+			changes = append(changes, MetadataChange{Kind: ChangeAdded, Path: path, New: cur})
+		case !inNew:
+			changes = append(changes, MetadataChange{Kind: ChangeRemoved, Path: path, Old: old})
+		case !diffValuesEqual(old, cur, opts):
+			changes = append(changes, MetadataChange{Kind: ChangeModified, Path: path, Old: old, New: cur})
+		}
+	}
+	return changes, nil
+}
+
+// flattenDiffMetadata encodes m to JSON and flattens it into leaf values keyed by path.
+func flattenDiffMetadata(m *Metadata) (map[string]interface{}, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode metadata for diff", err, ErrorCodeValidation, nil)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var root interface{}
+	if err := decoder.Decode(&root); err != nil {
+		return nil, newSerializationErrorWithContext("failed to decode metadata for diff", err, ErrorCodeValidation, nil)
+	}
+	fields := map[string]interface{}{}
+	flattenDiffValue("", root, fields)
+	return fields, nil
+}
+
+func flattenDiffValue(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && path != "" {
+			fields[path] = v
+		}
+		for key, child := range v {
+			if path != "" {
+				key = path + "." + key
+			}
+			flattenDiffValue(key, child, fields)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = v
+		}
+		for i, child := range v {
+			flattenDiffValue(fmt.Sprintf("%s[%d]", path, i), child, fields)
+		}
+	default:
+		fields[path] = v
+	}
+}
+
+func diffValuesEqual(a, b interface{}, opts *DiffOptions) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		if errX != nil || errY != nil {
+			return x == y
+		}
+		return floatsWithin(fx, fy, opts.FloatTolerance)
+	case string:
+		y, ok := b.(string)
+		return ok && normalizeDiffText(x, opts) == normalizeDiffText(y, opts)
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+func diffChunks(a, b *ExtractionResult, opts *DiffOptions) []ChunkChange {
+	oldPos := chunkBoundaryPositions(a, opts)
+	newPos := chunkBoundaryPositions(b, opts)
+	pairs := alignSequences(len(a.Chunks), len(b.Chunks), func(i, j int) bool {
+		return normalizeDiffText(a.Chunks[i].Content, opts) == normalizeDiffText(b.Chunks[j].Content, opts)
+	})
+
+	var changes []ChunkChange
+	for _, pair := range pairs {
+		change := ChunkChange{OldIndex: pair.a, NewIndex: pair.b}
+		if pair.a >= 0 {
+			meta := a.Chunks[pair.a].Metadata
+			change.OldByteStart, change.OldByteEnd = meta.ByteStart, meta.ByteEnd
+		}
+		if pair.b >= 0 {
+			meta := b.Chunks[pair.b].Metadata
+			change.NewByteStart, change.NewByteEnd = meta.ByteStart, meta.ByteEnd
+		}
+		switch {
+		case pair.a < 0:
+			change.Kind = ChangeAdded
+		case pair.b < 0:
+			change.Kind = ChangeRemoved
+		default:
+			change.ContentChanged = !pair.equal
+			shifted := oldPos[change.OldByteStart] != newPos[change.NewByteStart] ||
+				oldPos[change.OldByteEnd] != newPos[change.NewByteEnd]
+			if !change.ContentChanged && !shifted {
+				continue
+			}
+			change.Kind = ChangeModified
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// chunkBoundaryPositions maps every chunk byte offset of r to the position compared
+// by diffChunks: the offset itself, or with IgnoreWhitespace the number of
+// non-whitespace bytes of Content before it.
+func chunkBoundaryPositions(r *ExtractionResult, opts *DiffOptions) map[uint64]uint64 {
+	positions := make(map[uint64]uint64, 2*len(r.Chunks))
+	offsets := make([]uint64, 0, 2*len(r.Chunks))
+	for _, chunk := range r.Chunks {
+		offsets = append(offsets, chunk.Metadata.ByteStart, chunk.Metadata.ByteEnd)
+		positions[chunk.Metadata.ByteStart] = chunk.Metadata.ByteStart
+		positions[chunk.Metadata.ByteEnd] = chunk.Metadata.ByteEnd
+	}
+	if !opts.IgnoreWhitespace {
+		return positions
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	var count uint64
+	i := 0
+	for pos := 0; pos <= len(r.Content); {
+		for i < len(offsets) && offsets[i] <= uint64(pos) {
+			positions[offsets[i]] = count
+			i++
+		}
+		if pos == len(r.Content) {
+			break
+		}
+		ch, size := utf8.DecodeRuneInString(r.Content[pos:])
+		if !unicode.IsSpace(ch) {
+			count += uint64(size)
+		}
+		pos += size
+	}
+	for ; i < len(offsets); i++ {
+		positions[offsets[i]] = count
+	}
+	return positions
+}
+
+func diffElements(a, b []Element, opts *DiffOptions) []ElementChange {
+	pairs := alignSequences(len(a), len(b), func(i, j int) bool {
+		return normalizeDiffText(a[i].Text, opts) == normalizeDiffText(b[j].Text, opts)
+	})
+
+	var changes []ElementChange
+	for _, pair := range pairs {
+		change := ElementChange{OldIndex: pair.a, NewIndex: pair.b}
+		if pair.a >= 0 {
+			el := &a[pair.a]
+			change.OldType, change.OldText = el.ElementType, el.Text
+			change.OldPage, change.OldCoordinates = el.Metadata.PageNumber, el.Metadata.Coordinates
+		}
+		if pair.b >= 0 {
+			el := &b[pair.b]
+			change.NewType, change.NewText = el.ElementType, el.Text
+			change.NewPage, change.NewCoordinates = el.Metadata.PageNumber, el.Metadata.Coordinates
+		}
+		switch {
+		case pair.a < 0:
+			change.Kind = ChangeAdded
+		case pair.b < 0:
+			change.Kind = ChangeRemoved
+		default:
+			if change.OldType != change.NewType {
+				change.Fields = append(change.Fields, "type")
+			}
+			if !pair.equal {
+				change.Fields = append(change.Fields, "text")
+			}
+			if !reflect.DeepEqual(change.OldPage, change.NewPage) {
+				change.Fields = append(change.Fields, "page")
+			}
+			if !boxesWithin(change.OldCoordinates, change.NewCoordinates, opts.FloatTolerance) {
+				change.Fields = append(change.Fields, "coordinates")
+			}
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Kind = ChangeModified
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func boxesWithin(a, b *BoundingBox, tolerance float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return floatsWithin(a.X0, b.X0, tolerance) && floatsWithin(a.Y0, b.Y0, tolerance) &&
+		floatsWithin(a.X1, b.X1, tolerance) && floatsWithin(a.Y1, b.Y1, tolerance)
+}
+
+func floatsWithin(a, b, tolerance float64) bool {
+	return a == b || math.Abs(a-b) <= tolerance
+}
+
+func normalizeDiffText(s string, opts *DiffOptions) string {
+	if !opts.IgnoreWhitespace {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func diffValueString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func diffExcerpt(s string) string {
+	const limit = 60
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
+
+func diffPageString(page *uint64) string {
+	if page == nil {
+		return "none"
+	}
+	return strconv.FormatUint(*page, 10)
+}
+
+func diffBoxString(box *BoundingBox) string {
+	if box == nil {
+		return "none"
+	}
+	return fmt.Sprintf("(%g,%g,%g,%g)", box.X0, box.Y0, box.X1, box.Y1)
+}
+
+type editKind int8
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+// edit is one step of an edit script; a indexes the old and b the new sequence.
+type edit struct {
+	kind editKind
+	a, b int
+}
+
+// diffSequence returns a shortest edit script turning a sequence of length n into one
+// of length m, using Myers' algorithm after trimming the common prefix and suffix.
+func diffSequence(n, m int, eq func(i, j int) bool) []edit {
+	prefix := 0
+	for prefix < n && prefix < m && eq(prefix, prefix) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && eq(n-1-suffix, m-1-suffix) {
+		suffix++
+	}
+
+	edits := make([]edit, 0, n+m-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, edit{editEqual, i, i})
+	}
+	for _, e := range myersDiff(n-prefix-suffix, m-prefix-suffix, func(i, j int) bool { return eq(i+prefix, j+prefix) }) {
+		e.a += prefix
+		e.b += prefix
+		edits = append(edits, e)
+	}
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, edit{editEqual, n - i, m - i})
+	}
+	return edits
+}
+
+// myersDiff uses the linear-space variant of Myers' algorithm: the middle snake of an
+// optimal path splits the problem in two, which are solved recursively. Memory stays
+// O(N+M) while time stays O((N+M)·D).
+func myersDiff(n, m int, eq func(i, j int) bool) []edit {
+	d := &myersDiffer{eq: eq}
+	size := (n+m+1)/2 + 1
+	d.offset = size
+	d.forward = make([]int, 2*size+1)
+	d.backward = make([]int, 2*size+1)
+	d.edits = make([]edit, 0, n+m)
+	d.compare(0, n, 0, m)
+	return d.edits
+}
+
+type myersDiffer struct {
+	eq                func(i, j int) bool
+	offset            int
+	forward, backward []int
+	edits             []edit
+}
+
+// compare appends the edits turning a[a0:a1] into b[b0:b1].
+func (d *myersDiffer) compare(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.eq(a0, b0) {
+		d.edits = append(d.edits, edit{editEqual, a0, b0})
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a1 > a0 && b1 > b0 && d.eq(a1-1, b1-1) {
+		a1--
+		b1--
+		suffix++
+	}
+
+	switch {
+	case a0 == a1:
+		for j := b0; j < b1; j++ {
+			d.edits = append(d.edits, edit{editInsert, a0, j})
+		}
+	case b0 == b1:
+		for i := a0; i < a1; i++ {
+			d.edits = append(d.edits, edit{editDelete, i, b0})
+		}
+	default:
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.compare(a0, x, b0, y)
+		for ; x < u; x, y = x+1, y+1 {
+			d.edits = append(d.edits, edit{editEqual, x, y})
+		}
+		d.compare(u, a1, v, b1)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, edit{editEqual, a1 + i, b1 + i})
+	}
+}
+
+// middleSnake runs the forward and backward searches of a[a0:a1] against b[b0:b1]
+// until they overlap and returns the start (x, y) and end (u, v) of the snake where
+// they meet, which lies on a shortest edit path.
+func (d *myersDiffer) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	off := d.offset
+	fwd, bwd := d.forward, d.backward
+	fwd[off+1], bwd[off+1] = 0, 0
+
+	for step := 0; step <= (n+m+1)/2; step++ {
+		for k := -step; k <= step; k += 2 {
+			fx := fwd[off+k-1] + 1
+			if k == -step || (k != step && fwd[off+k-1] < fwd[off+k+1]) {
+				fx = fwd[off+k+1]
+			}
+			fy := fx - k
+			sx, sy := fx, fy
+			for fx < n && fy < m && d.eq(a0+fx, b0+fy) {
+				fx++
+				fy++
+			}
+			fwd[off+k] = fx
+			if back := delta - k; odd && back >= -(step-1) && back <= step-1 && fx+bwd[off+back] >= n {
+				return a0 + sx, b0 + sy, a0 + fx, b0 + fy
+			}
+		}
+		for k := -step; k <= step; k += 2 {
+			rx := bwd[off+k-1] + 1
+			if k == -step || (k != step && bwd[off+k-1] < bwd[off+k+1]) {
+				rx = bwd[off+k+1]
+			}
+			ry := rx - k
+			sx, sy := rx, ry
+			for rx < n && ry < m && d.eq(a1-1-rx, b1-1-ry) {
+				rx++
+				ry++
+			}
+			bwd[off+k] = rx
+			if fk := delta - k; !odd && fk >= -step && fk <= step && fwd[off+fk]+rx >= n {
+				return a1 - rx, b1 - ry, a1 - sx, b1 - sy
+			}
+		}
+	}
+	panic("kreuzberg: middle snake not found")
+}
+
+// alignedPair pairs an item of the old sequence with one of the new sequence; an index
+// is -1 when the item has no counterpart. equal reports whether eq matched the pair.
+type alignedPair struct {
+	a, b  int
+	equal bool
+}
+
+// alignSequences matches equal items with diffSequence and pairs the remaining items
+// between two matches by position, leaving the surplus unpaired.
+func alignSequences(n, m int, eq func(i, j int) bool) []alignedPair {
+	var pairs []alignedPair
+	var deleted, inserted []int
+	flush := func() {
+		for i := 0; i < len(deleted) || i < len(inserted); i++ {
+			pair := alignedPair{a: -1, b: -1}
+			if i < len(deleted) {
+				pair.a = deleted[i]
+			}
+			if i < len(inserted) {
+				pair.b = inserted[i]
+			}
+			pairs = append(pairs, pair)
+		}
+		deleted, inserted = deleted[:0], inserted[:0]
+	}
+	for _, e := range diffSequence(n, m, eq) {
+		switch e.kind {
+		case editEqual:
+			flush()
+			pairs = append(pairs, alignedPair{a: e.a, b: e.b, equal: true})
+		case editDelete:
+			deleted = append(deleted, e.a)
+		case editInsert:
+			inserted = append(inserted, e.b)
+		}
+	}
+	flush()
+	return pairs
+}
@@ -0,0 +1,227 @@
+package kreuzberg_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestDiffResultsIdentical(t *testing.T) {
+	result := &kreuzberg.ExtractionResult{
+		Content:  "one\ntwo\n",
+		Tables:   []kreuzberg.Table{{Cells: [][]string{{"a"}}, PageNumber: 1}},
+		Metadata: kreuzberg.Metadata{Title: strPtr("Report")},
+	}
+	diff, err := kreuzberg.DiffResults(result, result, nil)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if !diff.Equal() || diff.String() != "no differences\n" {
+		t.Errorf("expected no differences, got %s", diff)
+	}
+}
+
+func TestDiffResultsContentUnified(t *testing.T) {
+	a := &kreuzberg.ExtractionResult{Content: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"}
+	b := &kreuzberg.ExtractionResult{Content: "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n"}
+	diff, err := kreuzberg.DiffResults(a, b, &kreuzberg.DiffOptions{ContextLines: 1})
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	want := "--- a\n+++ b\n" +
+		"@@ -2,3 +2,3 @@\n 2\n-3\n+three\n 4\n" +
+		"@@ -10 +10,2 @@\n 10\n+11\n"
+	if got := diff.UnifiedContent(); got != want {
+		t.Errorf("UnifiedContent() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiffResultsLargeContentUsesLinearMemory(t *testing.T) {
+	// 4000 lines that all differ need D = 8000 edits; a trace of every D-path would
+	// allocate about a gigabyte.
+	var oldLines, newLines strings.Builder
+	for i := 0; i < 4000; i++ {
+		fmt.Fprintf(&oldLines, "old line %d\n", i)
+		fmt.Fprintf(&newLines, "new line %d\n", i)
+	}
+	a := &kreuzberg.ExtractionResult{Content: oldLines.String()}
+	b := &kreuzberg.ExtractionResult{Content: newLines.String()}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diff, err := kreuzberg.DiffResults(a, b, nil)
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if got := strings.Count(diff.UnifiedContent(), "\n-old line"); got != 4000 {
+		t.Errorf("diff has %d deleted lines, want 4000", got)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("DiffResults() allocated %d bytes", allocated)
+	}
+}
+
+func TestDiffResultsIgnoreWhitespace(t *testing.T) {
+	a := &kreuzberg.ExtractionResult{
+		Content: "Hello  world\n\nNext line",
+		Chunks: []kreuzberg.Chunk{
+			{Content: "Hello  world", Metadata: kreuzberg.ChunkMetadata{ByteStart: 0, ByteEnd: 12}},
+			{Content: "Next line", Metadata: kreuzberg.ChunkMetadata{ByteStart: 14, ByteEnd: 23}},
+		},
+	}
+	b := &kreuzberg.ExtractionResult{
+		Content: "Hello world\nNext line ",
+		Chunks: []kreuzberg.Chunk{
+			{Content: "Hello world", Metadata: kreuzberg.ChunkMetadata{ByteStart: 0, ByteEnd: 11}},
+			{Content: "Next line", Metadata: kreuzberg.ChunkMetadata{ByteStart: 12, ByteEnd: 21}},
+		},
+	}
+
+	strict, err := kreuzberg.DiffResults(a, b, nil)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if len(strict.Content) == 0 || len(strict.Chunks) != 2 {
+		t.Errorf("strict diff should report content and chunk changes: %s", strict)
+	}
+	if c := strict.Chunks[1]; c.Kind != kreuzberg.ChangeModified || c.ContentChanged || c.OldByteStart != 14 || c.NewByteStart != 12 {
+		t.Errorf("expected a boundary shift of the second chunk, got %+v", c)
+	}
+
+	loose, err := kreuzberg.DiffResults(a, b, &kreuzberg.DiffOptions{IgnoreWhitespace: true})
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if !loose.Equal() {
+		t.Errorf("expected no differences when ignoring whitespace, got %s", loose)
+	}
+}
+
+func TestDiffResultsTables(t *testing.T) {
+	a := &kreuzberg.ExtractionResult{Tables: []kreuzberg.Table{
+		{Cells: [][]string{{"Item", "Qty"}, {"Apple", "1"}}, PageNumber: 1},
+		{Cells: [][]string{{"Old"}}, PageNumber: 2},
+	}}
+	b := &kreuzberg.ExtractionResult{Tables: []kreuzberg.Table{
+		{Cells: [][]string{{"Extra"}}, PageNumber: 1},
+		{Cells: [][]string{{"Item", "Qty"}, {"Apple", "2"}, {"Pear", "3"}}, PageNumber: 1},
+	}}
+	diff, err := kreuzberg.DiffResults(a, b, nil)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if len(diff.Tables) != 2 {
+		t.Fatalf("expected 2 table changes, got %+v", diff.Tables)
+	}
+	modified := diff.Tables[0]
+	if modified.Kind != kreuzberg.ChangeModified || modified.OldIndex != 0 || modified.NewIndex != 0 {
+		t.Fatalf("first change = %+v", modified)
+	}
+	cells := []kreuzberg.CellChange{
+		{Row: 0, Col: 0, Old: "Item", New: "Extra"}, {Row: 0, Col: 1, Old: "Qty", New: ""},
+		{Row: 1, Col: 0, Old: "Apple", New: ""}, {Row: 1, Col: 1, Old: "1", New: ""},
+	}
+	if !reflect.DeepEqual(modified.Cells, cells) {
+		t.Errorf("cells = %+v", modified.Cells)
+	}
+	if modified.OldPage != 1 || modified.NewPage != 1 || modified.OldRows != 2 || modified.NewCols != 1 {
+		t.Errorf("shape = %+v", modified)
+	}
+	if diff.Tables[1].Kind != kreuzberg.ChangeModified || diff.Tables[1].NewRows != 3 {
+		t.Errorf("second change = %+v", diff.Tables[1])
+	}
+
+	// An inserted table leaves the surrounding tables unchanged.
+	inserted := &kreuzberg.ExtractionResult{Tables: append([]kreuzberg.Table{{Cells: [][]string{{"New"}}, PageNumber: 1}}, a.Tables...)}
+	diff, err = kreuzberg.DiffResults(a, inserted, nil)
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if len(diff.Tables) != 1 || diff.Tables[0].Kind != kreuzberg.ChangeAdded || diff.Tables[0].NewIndex != 0 || diff.Tables[0].OldIndex != -1 {
+		t.Errorf("expected a single added table, got %+v", diff.Tables)
+	}
+}
+
+func TestDiffResultsMetadata(t *testing.T) {
+	a := &kreuzberg.ExtractionResult{Metadata: kreuzberg.Metadata{
+		Title:   strPtr("Report"),
+		Authors: []string{"Ann"},
+		Additional: map[string]json.RawMessage{
+			"score": json.RawMessage(`0.5000001`),
+		},
+	}}
+	b := &kreuzberg.ExtractionResult{Metadata: kreuzberg.Metadata{
+		Title:    strPtr("Annual Report"),
+		Authors:  []string{"Ann", "Bob"},
+		Language: strPtr("en"),
+		Additional: map[string]json.RawMessage{
+			"score": json.RawMessage(`0.5`),
+		},
+	}}
+	diff, err := kreuzberg.DiffResults(a, b, &kreuzberg.DiffOptions{FloatTolerance: 1e-3})
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	var paths []string
+	for _, c := range diff.Metadata {
+		paths = append(paths, string(c.Kind)+" "+c.Path)
+	}
+	want := []string{"added authors[1]", "added language", "modified title"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("metadata changes = %v, want %v", paths, want)
+	}
+	if !strings.Contains(diff.String(), `~ title: "Report" -> "Annual Report"`) {
+		t.Errorf("String() = %s", diff)
+	}
+}
+
+func TestDiffResultsElements(t *testing.T) {
+	a := &kreuzberg.ExtractionResult{Elements: []kreuzberg.Element{
+		{ElementType: kreuzberg.ElementTypeNarrativeText, Text: "Intro", Metadata: kreuzberg.ElementMetadata{
+			Coordinates: &kreuzberg.BoundingBox{X0: 10, Y0: 20, X1: 30, Y1: 40},
+		}},
+		{ElementType: kreuzberg.ElementTypeNarrativeText, Text: "Body"},
+		{ElementType: kreuzberg.ElementTypeFooter, Text: "Page 1"},
+	}}
+	b := &kreuzberg.ExtractionResult{Elements: []kreuzberg.Element{
+		{ElementType: kreuzberg.ElementTypeHeading, Text: "Intro", Metadata: kreuzberg.ElementMetadata{
+			Coordinates: &kreuzberg.BoundingBox{X0: 10.0004, Y0: 20, X1: 30, Y1: 40},
+		}},
+		{ElementType: kreuzberg.ElementTypeNarrativeText, Text: "Body", Metadata: kreuzberg.ElementMetadata{
+			Coordinates: &kreuzberg.BoundingBox{X0: 1, Y0: 2, X1: 3, Y1: 4},
+		}},
+	}}
+	diff, err := kreuzberg.DiffResults(a, b, &kreuzberg.DiffOptions{FloatTolerance: 0.01})
+	if err != nil {
+		t.Fatalf("DiffResults() error = %v", err)
+	}
+	if len(diff.Elements) != 3 {
+		t.Fatalf("expected 3 element changes, got %+v", diff.Elements)
+	}
+	if c := diff.Elements[0]; !reflect.DeepEqual(c.Fields, []string{"type"}) || c.OldType != kreuzberg.ElementTypeNarrativeText || c.NewType != kreuzberg.ElementTypeHeading {
+		t.Errorf("first change = %+v", c)
+	}
+	if c := diff.Elements[1]; !reflect.DeepEqual(c.Fields, []string{"coordinates"}) {
+		t.Errorf("second change = %+v", c)
+	}
+	if c := diff.Elements[2]; c.Kind != kreuzberg.ChangeRemoved || c.OldIndex != 2 || c.NewIndex != -1 {
+		t.Errorf("third change = %+v", c)
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded kreuzberg.ResultDiff
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Elements) != 3 || decoded.Elements[2].Kind != kreuzberg.ChangeRemoved {
+		t.Errorf("JSON round trip failed: %v %s", err, data)
+	}
+}
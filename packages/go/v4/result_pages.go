@@ -0,0 +1,185 @@
+package kreuzberg
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// PageByteRange returns the byte range [start, end) of page n within Content. Ranges
+// come from Metadata.Pages.Boundaries; when the result has no boundaries they are
+// derived by locating each PageContent in Content. Ranges are widened as needed so
+// they never split a multi-byte UTF-8 character.
+func (r *ExtractionResult) PageByteRange(n int) (start, end int, ok bool) {
+	for _, b := range r.pageBoundaries() {
+		if b.page == n {
+			return b.start, b.end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// PageText returns the text of page n (1-indexed). It slices Content by the page's byte
+// range, falling back to PageContent.Content when the page cannot be located in
+// Content. ok is false when the result carries no page information for n.
+func (r *ExtractionResult) PageText(n int) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	if start, end, ok := r.PageByteRange(n); ok {
+		return r.Content[start:end], true
+	}
+	for _, page := range r.Pages {
+		if int(page.PageNumber) == n {
+			return page.Content, true
+		}
+	}
+	return "", false
+}
+
+// PageForOffset returns the page containing the given byte offset into Content. Offsets
+// that fall between two pages (e.g., on a page separator) belong to the preceding
+// page. ok is false when the result has no page information or the offset is out of
+// range.
+func (r *ExtractionResult) PageForOffset(byteOffset int) (int, bool) {
+	if r == nil || byteOffset < 0 || byteOffset >= len(r.Content) {
+		return 0, false
+	}
+	boundaries := r.pageBoundaries()
+	i := sort.Search(len(boundaries), func(i int) bool { return boundaries[i].start > byteOffset })
+	if i == 0 {
+		return 0, false
+	}
+	return boundaries[i-1].page, true
+}
+
+// ChunksOnPage returns the chunks that overlap page n. Chunks are matched by
+// ChunkMetadata.FirstPage and LastPage, or by their byte range when those are unset.
+func (r *ExtractionResult) ChunksOnPage(n int) []Chunk {
+	if r == nil {
+		return nil
+	}
+	var boundaries []pageBoundary
+	chunks := []Chunk{}
+	for _, chunk := range r.Chunks {
+		meta := chunk.Metadata
+		if meta.FirstPage != nil {
+			last := *meta.FirstPage
+			if meta.LastPage != nil {
+				last = *meta.LastPage
+			}
+			if uint64(n) >= *meta.FirstPage && uint64(n) <= last {
+				chunks = append(chunks, chunk)
+			}
+			continue
+		}
+		if boundaries == nil {
+			boundaries = r.pageBoundaries()
+		}
+		for _, b := range boundaries {
+			if b.page == n && int(meta.ByteStart) < b.end && int(meta.ByteEnd) > b.start {
+				chunks = append(chunks, chunk)
+				break
+			}
+		}
+	}
+	return chunks
+}
+
+// ElementsOnPage returns the elements whose metadata places them on page n.
+func (r *ExtractionResult) ElementsOnPage(n int) []Element {
+	if r == nil {
+		return nil
+	}
+	elements := []Element{}
+	for _, el := range r.Elements {
+		if el.Metadata.PageNumber != nil && *el.Metadata.PageNumber == uint64(n) {
+			elements = append(elements, el)
+		}
+	}
+	return elements
+}
+
+// TablesOnPage returns the tables found on page n, including stitched tables with a
+// fragment on that page (see TableSourcePages). Results without top-level tables fall
+// back to PageContent.Tables.
+func (r *ExtractionResult) TablesOnPage(n int) []Table {
+	if r == nil {
+		return nil
+	}
+	tables := []Table{}
+	for i := range r.Tables {
+		for _, page := range r.TableSourcePages(i) {
+			if page == n {
+				tables = append(tables, r.Tables[i])
+				break
+			}
+		}
+	}
+	if len(r.Tables) == 0 {
+		for _, page := range r.Pages {
+			if int(page.PageNumber) == n {
+				tables = append(tables, page.Tables...)
+			}
+		}
+	}
+	return tables
+}
+
+type pageBoundary struct {
+	page       int
+	start, end int
+}
+
+// pageBoundaries returns the validated page ranges of Content sorted by start offset.
+func (r *ExtractionResult) pageBoundaries() []pageBoundary {
+	if r == nil || r.Content == "" {
+		return nil
+	}
+	var boundaries []pageBoundary
+	if r.Metadata.Pages != nil && len(r.Metadata.Pages.Boundaries) > 0 {
+		for _, b := range r.Metadata.Pages.Boundaries {
+			start, end := clampToRunes(r.Content, int(b.ByteStart), int(b.ByteEnd))
+			if start < end {
+				boundaries = append(boundaries, pageBoundary{page: int(b.PageNumber), start: start, end: end})
+			}
+		}
+	} else {
+		cursor := 0
+		for _, page := range r.Pages {
+			text := strings.TrimSpace(page.Content)
+			if text == "" {
+				continue
+			}
+			idx := strings.Index(r.Content[cursor:], text)
+			if idx < 0 {
+				continue
+			}
+			start := cursor + idx
+			cursor = start + len(text)
+			boundaries = append(boundaries, pageBoundary{page: int(page.PageNumber), start: start, end: cursor})
+		}
+	}
+	sort.SliceStable(boundaries, func(i, j int) bool { return boundaries[i].start < boundaries[j].start })
+	return boundaries
+}
+
+// clampToRunes limits [start, end) to s and widens it to UTF-8 character boundaries.
+func clampToRunes(s string, start, end int) (int, int) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(s) {
+		end = len(s)
+	}
+	if start >= end {
+		return start, start
+	}
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start--
+	}
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+	return start, end
+}
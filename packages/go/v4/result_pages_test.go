@@ -0,0 +1,116 @@
+package kreuzberg_test
+
+import (
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func u64(v uint64) *uint64 { return &v }
+
+func newPagedResult() *kreuzberg.ExtractionResult {
+	page1 := "Größe: 10 €"
+	page2 := "日本語のページ"
+	content := page1 + "\n\n" + page2
+	return &kreuzberg.ExtractionResult{
+		Content: content,
+		Metadata: kreuzberg.Metadata{Pages: &kreuzberg.PageStructure{
+			TotalCount: 2,
+			Boundaries: []kreuzberg.PageBoundary{
+				{ByteStart: 0, ByteEnd: uint64(len(page1)), PageNumber: 1},
+				// Deliberately starts inside a multi-byte character.
+				{ByteStart: uint64(len(page1) + 3), ByteEnd: uint64(len(content)), PageNumber: 2},
+			},
+		}},
+		Chunks: []kreuzberg.Chunk{
+			{Content: "a", Metadata: kreuzberg.ChunkMetadata{FirstPage: u64(1), LastPage: u64(2)}},
+			{Content: "b", Metadata: kreuzberg.ChunkMetadata{FirstPage: u64(2), LastPage: u64(2)}},
+			{Content: "c", Metadata: kreuzberg.ChunkMetadata{ByteStart: 0, ByteEnd: 5}},
+		},
+		Elements: []kreuzberg.Element{
+			{Text: "one", Metadata: kreuzberg.ElementMetadata{PageNumber: u64(1)}},
+			{Text: "two", Metadata: kreuzberg.ElementMetadata{PageNumber: u64(2)}},
+		},
+		Tables: []kreuzberg.Table{
+			{Cells: [][]string{{"x"}}, PageNumber: 1, SourcePages: []int{1, 2}},
+			{Cells: [][]string{{"y"}}, PageNumber: 2},
+		},
+	}
+}
+
+func TestPageText(t *testing.T) {
+	result := newPagedResult()
+	if got, ok := result.PageText(1); !ok || got != "Größe: 10 €" {
+		t.Errorf("PageText(1) = %q, %v", got, ok)
+	}
+	if got, ok := result.PageText(2); !ok || got != "日本語のページ" {
+		t.Errorf("PageText(2) = %q, %v", got, ok)
+	}
+	if _, ok := result.PageText(3); ok {
+		t.Error("PageText(3) should not exist")
+	}
+}
+
+func TestPageForOffset(t *testing.T) {
+	result := newPagedResult()
+	hit := strings.Index(result.Content, "ページ")
+	if page, ok := result.PageForOffset(hit); !ok || page != 2 {
+		t.Errorf("PageForOffset(%d) = %d, %v", hit, page, ok)
+	}
+	if page, ok := result.PageForOffset(strings.Index(result.Content, "€")); !ok || page != 1 {
+		t.Errorf("PageForOffset(€) = %d, %v", page, ok)
+	}
+	if page, ok := result.PageForOffset(strings.Index(result.Content, "\n")); !ok || page != 1 {
+		t.Errorf("separator offset should belong to the preceding page, got %d, %v", page, ok)
+	}
+	if _, ok := result.PageForOffset(len(result.Content)); ok {
+		t.Error("offset past the end should not resolve")
+	}
+}
+
+func TestPageItems(t *testing.T) {
+	result := newPagedResult()
+	if chunks := result.ChunksOnPage(1); len(chunks) != 2 || chunks[0].Content != "a" || chunks[1].Content != "c" {
+		t.Errorf("ChunksOnPage(1) = %+v", chunks)
+	}
+	if chunks := result.ChunksOnPage(2); len(chunks) != 2 {
+		t.Errorf("ChunksOnPage(2) = %+v", chunks)
+	}
+	if elements := result.ElementsOnPage(2); len(elements) != 1 || elements[0].Text != "two" {
+		t.Errorf("ElementsOnPage(2) = %+v", elements)
+	}
+	if tables := result.TablesOnPage(2); len(tables) != 2 {
+		t.Errorf("TablesOnPage(2) = %+v", tables)
+	}
+	if tables := result.TablesOnPage(1); len(tables) != 1 {
+		t.Errorf("TablesOnPage(1) = %+v", tables)
+	}
+}
+
+func TestPageHelpersWithoutBoundaries(t *testing.T) {
+	result := &kreuzberg.ExtractionResult{
+		Content: "Erste Seite\n\nZweite Seite",
+		Pages: []kreuzberg.PageContent{
+			{PageNumber: 1, Content: "Erste Seite"},
+			{PageNumber: 2, Content: "Zweite Seite", Tables: []kreuzberg.Table{{Cells: [][]string{{"t"}}}}},
+		},
+	}
+	if page, ok := result.PageForOffset(strings.Index(result.Content, "Zweite")); !ok || page != 2 {
+		t.Errorf("PageForOffset() = %d, %v", page, ok)
+	}
+	if text, ok := result.PageText(2); !ok || text != "Zweite Seite" {
+		t.Errorf("PageText(2) = %q, %v", text, ok)
+	}
+	if tables := result.TablesOnPage(2); len(tables) != 1 {
+		t.Errorf("TablesOnPage(2) = %+v", tables)
+	}
+
+	bare := &kreuzberg.ExtractionResult{Content: "no pages"}
+	if _, ok := bare.PageForOffset(0); ok {
+		t.Error("result without page info should not resolve offsets")
+	}
+	if _, ok := bare.PageText(1); ok {
+		t.Error("result without page info should not return page text")
+	}
+}
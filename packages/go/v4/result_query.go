@@ -0,0 +1,564 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ResultQuery is a compiled path expression over an ExtractionResult.
+//
+// Paths start at the result and use JSON field names:
+//
+//	content                                   the extracted text
+//	metadata.pages.total_count                nested struct fields
+//	metadata.page_count                       format-specific fields flattened into metadata
+//	metadata.pdf.page_count                   the same field through its format section
+//	metadata["custom key"]                    quoted keys, including Metadata.Additional
+//	tables[0].cells[1][2]                     list indexes; negative indexes count from the end
+//	chunks[*].metadata.chunk_index            wildcard over a list
+//	elements[?element_type=="heading"].text   filter a list by a field (== or !=)
+//
+// Wildcards and filters project: the query then yields a []interface{} holding one
+// entry per matching item, skipping items where the rest of the path is missing.
+// Values keep their Go types (string, int, uint64, bool, Table, ...) with pointers
+// dereferenced.
+//
+// ResultQuery implements encoding.TextMarshaler and encoding.TextUnmarshaler so
+// queries can be embedded directly in JSON, YAML or TOML rule files.
+type ResultQuery struct {
+	source string
+	steps  []queryStep
+}
+
+type queryStepKind int
+
+const (
+	queryField queryStepKind = iota
+	queryIndex
+	queryWildcard
+	queryFilter
+)
+
+type queryStep struct {
+	kind    queryStepKind
+	name    string
+	index   int
+	filter  *ResultQuery
+	negate  bool
+	literal interface{}
+}
+
+// CompileResultQuery parses a query path. Syntax errors are returned as validation errors.
+func CompileResultQuery(path string) (*ResultQuery, error) {
+	p := &queryParser{src: path}
+	steps, err := p.parsePath(false)
+	if err != nil {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("invalid result query %q: %v", path, err), nil, ErrorCodeValidation, nil)
+	}
+	return &ResultQuery{source: path, steps: steps}, nil
+}
+
+// String returns the query source.
+func (q *ResultQuery) String() string {
+	if q == nil {
+		return ""
+	}
+	return q.source
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (q ResultQuery) MarshalText() ([]byte, error) {
+	return []byte(q.source), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (q *ResultQuery) UnmarshalText(text []byte) error {
+	compiled, err := CompileResultQuery(string(text))
+	if err != nil {
+		return err
+	}
+	*q = *compiled
+	return nil
+}
+
+// Eval runs the query against result. It returns nil without error when the path does
+// not resolve to a value, and a validation error when the path does not fit the shape
+// of the result (for example indexing into a string or naming an unknown struct field).
+func (q *ResultQuery) Eval(result *ExtractionResult) (interface{}, error) {
+	if q == nil {
+		return nil, newValidationErrorWithContext("query cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	if result == nil {
+		return nil, newValidationErrorWithContext("result cannot be nil", nil, ErrorCodeValidation, nil)
+	}
+	values, projected, err := evalQuerySteps(reflect.ValueOf(result), q.steps)
+	if err != nil {
+		return nil, newValidationErrorWithContext(fmt.Sprintf("result query %q: %v", q.source, err), nil, ErrorCodeValidation, nil)
+	}
+	if projected {
+		out := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			out = append(out, queryInterface(v))
+		}
+		return out, nil
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return queryInterface(values[0]), nil
+}
+
+// Query compiles path and evaluates it against the result. See ResultQuery for the syntax.
+func (r *ExtractionResult) Query(path string) (interface{}, error) {
+	q, err := CompileResultQuery(path)
+	if err != nil {
+		return nil, err
+	}
+	return q.Eval(r)
+}
+
+// QueryResultAs evaluates path and converts the value to T. Numeric values convert
+// between Go numeric types; other values must be assignable to T. A path that
+// resolves to nothing returns a validation error.
+func QueryResultAs[T any](result *ExtractionResult, path string) (T, error) {
+	var zero T
+	value, err := result.Query(path)
+	if err != nil {
+		return zero, err
+	}
+	if value == nil {
+		return zero, newValidationErrorWithContext(fmt.Sprintf("result query %q matched no value", path), nil, ErrorCodeValidation, nil)
+	}
+	if typed, ok := value.(T); ok {
+		return typed, nil
+	}
+
+	src := reflect.ValueOf(value)
+	dstType := reflect.TypeOf((*T)(nil)).Elem()
+	if isQueryNumber(src.Kind()) && isQueryNumber(dstType.Kind()) {
+		return src.Convert(dstType).Interface().(T), nil
+	}
+	if src.Type().ConvertibleTo(dstType) && src.Kind() == dstType.Kind() {
+		return src.Convert(dstType).Interface().(T), nil
+	}
+	return zero, newValidationErrorWithContext(fmt.Sprintf("result query %q: cannot convert %T to %s", path, value, dstType), nil, ErrorCodeValidation, nil)
+}
+
+func evalQuerySteps(root reflect.Value, steps []queryStep) ([]reflect.Value, bool, error) {
+	values := []reflect.Value{root}
+	projected := false
+	for _, step := range steps {
+		next := make([]reflect.Value, 0, len(values))
+		for _, v := range values {
+			v = queryDeref(v)
+			if !v.IsValid() {
+				continue
+			}
+			switch step.kind {
+			case queryField:
+				child, err := selectQueryField(v, step.name)
+				if err != nil {
+					return nil, false, err
+				}
+				if child.IsValid() {
+					next = append(next, child)
+				}
+			case queryIndex:
+				if !isQueryList(v) {
+					return nil, false, fmt.Errorf("cannot index %s", v.Type())
+				}
+				idx := step.index
+				if idx < 0 {
+					idx += v.Len()
+				}
+				if idx >= 0 && idx < v.Len() {
+					next = append(next, v.Index(idx))
+				}
+			case queryWildcard, queryFilter:
+				if !isQueryList(v) {
+					return nil, false, fmt.Errorf("cannot iterate %s", v.Type())
+				}
+				for i := 0; i < v.Len(); i++ {
+					item := v.Index(i)
+					if step.kind == queryFilter {
+						ok, err := queryFilterMatches(item, step)
+						if err != nil {
+							return nil, false, err
+						}
+						if !ok {
+							continue
+						}
+					}
+					next = append(next, item)
+				}
+			}
+		}
+		if step.kind == queryWildcard || step.kind == queryFilter {
+			projected = true
+		}
+		values = next
+	}
+	return values, projected, nil
+}
+
+func queryFilterMatches(item reflect.Value, step queryStep) (bool, error) {
+	values, _, err := evalQuerySteps(item, step.filter.steps)
+	if err != nil {
+		return false, err
+	}
+	var actual interface{}
+	if len(values) > 0 {
+		actual = queryInterface(values[0])
+	}
+	equal := queryValuesEqual(actual, step.literal)
+	if step.negate {
+		return !equal, nil
+	}
+	return equal, nil
+}
+
+func queryValuesEqual(actual, literal interface{}) bool {
+	if actual == nil || literal == nil {
+		return actual == nil && literal == nil
+	}
+	av := reflect.ValueOf(actual)
+	switch lit := literal.(type) {
+	case string:
+		return av.Kind() == reflect.String && av.String() == lit
+	case bool:
+		return av.Kind() == reflect.Bool && av.Bool() == lit
+	case float64:
+		n, ok := queryFloat(av)
+		return ok && n == lit
+	}
+	return false
+}
+
+// selectQueryField selects name from a struct, map or Metadata value.
+func selectQueryField(v reflect.Value, name string) (reflect.Value, error) {
+	if m, ok := v.Interface().(Metadata); ok {
+		return queryMetadataField(&m, name), nil
+	}
+	if raw, ok := v.Interface().(json.RawMessage); ok {
+		v = decodeQueryRaw(raw)
+		if !v.IsValid() {
+			return reflect.Value{}, nil
+		}
+		v = queryDeref(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		if field, ok := queryStructField(v, name); ok {
+			return field, nil
+		}
+		return reflect.Value{}, fmt.Errorf("unknown field %q on %s", name, v.Type().Name())
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("cannot select %q on %s", name, v.Type())
+		}
+		entry := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		return entry, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot select %q on %s", name, v.Type())
+	}
+}
+
+func queryStructField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if fieldName, _ := configFieldName(t.Field(i)); fieldName == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// queryMetadataField resolves a metadata key the same way Metadata.MarshalJSON lays it
+// out: typed fields first, then the format discriminator and format sections, then
+// format fields flattened into metadata, and finally Additional.
+func queryMetadataField(m *Metadata, name string) reflect.Value {
+	v := reflect.ValueOf(m).Elem()
+	if field, ok := queryStructField(v, name); ok {
+		return field
+	}
+
+	if name == "format_type" {
+		if m.Format.Type == FormatUnknown {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(m.Format.Type)
+	}
+
+	format := reflect.ValueOf(m.Format)
+	for i := 0; i < format.NumField(); i++ {
+		if format.Type().Field(i).Type.Kind() == reflect.Ptr && strings.EqualFold(format.Type().Field(i).Name, name) {
+			return format.Field(i)
+		}
+	}
+	for i := 0; i < format.NumField(); i++ {
+		section := format.Field(i)
+		if section.Kind() != reflect.Ptr || section.IsNil() {
+			continue
+		}
+		if field, ok := queryStructField(section.Elem(), name); ok {
+			return field
+		}
+	}
+
+	if raw, ok := m.Additional[name]; ok {
+		return decodeQueryRaw(raw)
+	}
+	return reflect.Value{}
+}
+
+func decodeQueryRaw(raw json.RawMessage) reflect.Value {
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded == nil {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(decoded)
+}
+
+// queryDeref follows pointers and interfaces, returning an invalid value for nil.
+func queryDeref(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func queryInterface(v reflect.Value) interface{} {
+	v = queryDeref(v)
+	if !v.IsValid() {
+		return nil
+	}
+	if raw, ok := v.Interface().(json.RawMessage); ok {
+		decoded := decodeQueryRaw(raw)
+		if !decoded.IsValid() {
+			return nil
+		}
+		return decoded.Interface()
+	}
+	return v.Interface()
+}
+
+func isQueryList(v reflect.Value) bool {
+	if _, ok := v.Interface().(json.RawMessage); ok {
+		return false
+	}
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func isQueryNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func queryFloat(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// queryParser is a small recursive-descent parser for ResultQuery paths.
+type queryParser struct {
+	src string
+	pos int
+}
+
+func (p *queryParser) parsePath(inFilter bool) ([]queryStep, error) {
+	steps := []queryStep{}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, queryStep{kind: queryField, name: name})
+
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '.':
+			p.pos++
+			name, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, queryStep{kind: queryField, name: name})
+		case '[':
+			p.pos++
+			step, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		default:
+			if inFilter {
+				return steps, nil
+			}
+			return nil, fmt.Errorf("unexpected %q at offset %d", p.src[p.pos], p.pos)
+		}
+	}
+	return steps, nil
+}
+
+func (p *queryParser) parseIdent() (string, error) {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	if start == p.pos {
+		return "", fmt.Errorf("expected field name at offset %d", start)
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *queryParser) parseBracket() (queryStep, error) {
+	if p.pos >= len(p.src) {
+		return queryStep{}, fmt.Errorf("unterminated [")
+	}
+
+	var step queryStep
+	switch c := p.src[p.pos]; {
+	case c == '*':
+		p.pos++
+		step = queryStep{kind: queryWildcard}
+	case c == '?':
+		p.pos++
+		filter, err := p.parseFilter()
+		if err != nil {
+			return queryStep{}, err
+		}
+		step = filter
+	case c == '"' || c == '\'':
+		key, err := p.parseString()
+		if err != nil {
+			return queryStep{}, err
+		}
+		step = queryStep{kind: queryField, name: key}
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != ']' {
+			p.pos++
+		}
+		idx, err := strconv.Atoi(strings.TrimSpace(p.src[start:p.pos]))
+		if err != nil {
+			return queryStep{}, fmt.Errorf("invalid index %q", p.src[start:p.pos])
+		}
+		step = queryStep{kind: queryIndex, index: idx}
+	}
+
+	if p.pos >= len(p.src) || p.src[p.pos] != ']' {
+		return queryStep{}, fmt.Errorf("expected ] at offset %d", p.pos)
+	}
+	p.pos++
+	return step, nil
+}
+
+func (p *queryParser) parseFilter() (queryStep, error) {
+	start := p.pos
+	fieldSteps, err := p.parsePath(true)
+	if err != nil {
+		return queryStep{}, err
+	}
+	for _, s := range fieldSteps {
+		if s.kind == queryWildcard || s.kind == queryFilter {
+			return queryStep{}, fmt.Errorf("filters cannot contain projections")
+		}
+	}
+	filter := &ResultQuery{source: p.src[start:p.pos], steps: fieldSteps}
+
+	p.skipSpaces()
+	negate := false
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "=="):
+	case strings.HasPrefix(p.src[p.pos:], "!="):
+		negate = true
+	default:
+		return queryStep{}, fmt.Errorf("expected == or != at offset %d", p.pos)
+	}
+	p.pos += 2
+	p.skipSpaces()
+
+	literal, err := p.parseLiteral()
+	if err != nil {
+		return queryStep{}, err
+	}
+	p.skipSpaces()
+	return queryStep{kind: queryFilter, filter: filter, negate: negate, literal: literal}, nil
+}
+
+func (p *queryParser) parseLiteral() (interface{}, error) {
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("expected value at offset %d", p.pos)
+	}
+	if c := p.src[p.pos]; c == '"' || c == '\'' {
+		return p.parseString()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ']' && p.src[p.pos] != ' ' {
+		p.pos++
+	}
+	word := p.src[start:p.pos]
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(word, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", word)
+	}
+	return n, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+}
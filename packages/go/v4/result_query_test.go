@@ -0,0 +1,170 @@
+package kreuzberg_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func newQueryTestResult() *kreuzberg.ExtractionResult {
+	pageCount := 12
+	return &kreuzberg.ExtractionResult{
+		Content:  "Report body",
+		MimeType: "application/pdf",
+		Metadata: kreuzberg.Metadata{
+			Title: kreuzberg.StringPtr("Annual Report"),
+			Pages: &kreuzberg.PageStructure{TotalCount: 12},
+			Format: kreuzberg.FormatMetadata{
+				Type: kreuzberg.FormatPDF,
+				Pdf:  &kreuzberg.PdfMetadata{PageCount: &pageCount},
+			},
+			Additional: map[string]json.RawMessage{
+				"review status": json.RawMessage(`{"approved": true}`),
+			},
+		},
+		Tables: []kreuzberg.Table{
+			{Cells: [][]string{{"Year", "Revenue"}, {"2024", "10"}, {"2025", "12"}}, PageNumber: 3},
+		},
+		Chunks: []kreuzberg.Chunk{
+			{Content: "a", Metadata: kreuzberg.ChunkMetadata{ChunkIndex: 0}},
+			{Content: "b", Metadata: kreuzberg.ChunkMetadata{ChunkIndex: 1}},
+		},
+		Elements: []kreuzberg.Element{
+			{ElementType: kreuzberg.ElementTypeTitle, Text: "Annual Report"},
+			{ElementType: kreuzberg.ElementTypeHeading, Text: "Summary"},
+			{ElementType: kreuzberg.ElementTypeNarrativeText, Text: "Revenue grew."},
+			{ElementType: kreuzberg.ElementTypeHeading, Text: "Outlook"},
+		},
+	}
+}
+
+func TestResultQuery(t *testing.T) {
+	result := newQueryTestResult()
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"content", "Report body"},
+		{"metadata.title", "Annual Report"},
+		{"metadata.pages.total_count", uint64(12)},
+		{"metadata.page_count", 12},
+		{"metadata.pdf.page_count", 12},
+		{"metadata.format_type", kreuzberg.FormatPDF},
+		{`metadata["review status"].approved`, true},
+		{"tables[0].cells[1][1]", "10"},
+		{"tables[-1].cells[-1][0]", "2025"},
+		{"tables[0].page_number", 3},
+		{`elements[?element_type=="heading"].text`, []interface{}{"Summary", "Outlook"}},
+		{`elements[?element_type != 'heading'].text`, []interface{}{"Annual Report", "Revenue grew."}},
+		{"chunks[*].metadata.chunk_index", []interface{}{uint64(0), uint64(1)}},
+		{"chunks[?metadata.chunk_index==1].content", []interface{}{"b"}},
+		{"tables[5].cells", nil},
+		{"metadata.excel", nil},
+		{"metadata.missing_key", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := result.Query(tt.path)
+			if err != nil {
+				t.Fatalf("Query(%q) error = %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query(%q) = %#v, want %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultQueryErrors(t *testing.T) {
+	result := newQueryTestResult()
+
+	for _, path := range []string{
+		"",
+		"tables[",
+		"tables[x]",
+		`elements[?element_type="heading"]`,
+		"content.length",
+		"content[0]",
+		"no_such_field",
+	} {
+		t.Run(path, func(t *testing.T) {
+			if _, err := result.Query(path); err == nil {
+				t.Errorf("Query(%q) should fail", path)
+			}
+		})
+	}
+}
+
+func TestQueryResultAs(t *testing.T) {
+	result := newQueryTestResult()
+
+	total, err := kreuzberg.QueryResultAs[int](result, "metadata.pages.total_count")
+	if err != nil || total != 12 {
+		t.Errorf("QueryResultAs[int]() = %d, %v", total, err)
+	}
+	elementType, err := kreuzberg.QueryResultAs[string](result, "elements[0].element_type")
+	if err != nil || elementType != "title" {
+		t.Errorf("QueryResultAs[string]() = %q, %v", elementType, err)
+	}
+	if _, err := kreuzberg.QueryResultAs[int](result, "content"); err == nil {
+		t.Error("expected conversion error")
+	}
+	if _, err := kreuzberg.QueryResultAs[string](result, "metadata.subject"); err == nil {
+		t.Error("expected error for missing value")
+	}
+}
+
+func TestResultQueryUnmarshalText(t *testing.T) {
+	var rule struct {
+		Select kreuzberg.ResultQuery `json:"select"`
+	}
+	if err := json.Unmarshal([]byte(`{"select": "tables[0].cells[0][1]"}`), &rule); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	got, err := rule.Select.Eval(newQueryTestResult())
+	if err != nil || got != "Revenue" {
+		t.Errorf("Eval() = %v, %v", got, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"select": "tables[0"}`), &rule); err == nil {
+		t.Error("invalid query in config should fail to unmarshal")
+	}
+}
+
+func TestGetMetadataFieldDotNotation(t *testing.T) {
+	result := newQueryTestResult()
+
+	field, err := result.GetMetadataField("pdf.page_count")
+	if err != nil {
+		t.Fatalf("GetMetadataField() error = %v", err)
+	}
+	if field.IsNull || field.Value != float64(12) {
+		t.Errorf("pdf.page_count = %#v, want 12", field.Value)
+	}
+
+	field, err = result.GetMetadataField("pages.total_count")
+	if err != nil || field.IsNull {
+		t.Errorf("pages.total_count should resolve, got %+v, %v", field, err)
+	}
+}
+
+func TestGetMetadataFieldLiteralAndMissingKeys(t *testing.T) {
+	result := newQueryTestResult()
+	result.Metadata.Additional["dc.title"] = json.RawMessage(`"Dublin Core title"`)
+
+	field, err := result.GetMetadataField("dc.title")
+	if err != nil || field.IsNull || field.Value != "Dublin Core title" {
+		t.Errorf("dc.title = %+v, %v, want the literal key", field, err)
+	}
+
+	for _, name := range []string{"pdf.missing", "pages.total_count.nested", "nope.nope"} {
+		field, err := result.GetMetadataField(name)
+		if err != nil || !field.IsNull {
+			t.Errorf("GetMetadataField(%q) = %+v, %v, want IsNull without error", name, field, err)
+		}
+	}
+}
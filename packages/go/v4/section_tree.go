@@ -0,0 +1,241 @@
+package kreuzberg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Section is a node of the document outline built by BuildSectionTree.
+//
+// The root section has Level -1 and no Heading; it holds the content that precedes the
+// first heading. A document title without a heading level has Level 0, and headings
+// h1 to h6 have Level 1 to 6.
+type Section struct {
+	// Title is the heading text; it is empty for the root.
+	Title string
+	// Level is the heading level.
+	Level int
+	// Breadcrumb holds the titles from the outermost section down to this one.
+	Breadcrumb []string
+	// Heading is the element that opened the section, or nil for the root.
+	Heading *Element
+	// Content holds the non-heading elements directly inside the section, before its
+	// first subsection.
+	Content []Element
+	// Children are the nested subsections in document order.
+	Children []*Section
+	// FirstPage and LastPage span the pages of the heading, content and subsections.
+	// Both are 0 when no page numbers are known.
+	FirstPage, LastPage int
+	// ByteStart and ByteEnd give the section's range [start, end) in
+	// ExtractionResult.Content, from its heading up to the next heading of the same or a
+	// higher level. Both are -1 when the heading could not be located in Content.
+	ByteStart, ByteEnd int
+}
+
+// Path joins the breadcrumb with " > ", e.g. "Report > Results > Revenue".
+func (s *Section) Path() string {
+	return strings.Join(s.Breadcrumb, " > ")
+}
+
+// Walk calls fn for s and its descendants in document order. Returning false from fn
+// skips the children of that section.
+func (s *Section) Walk(fn func(*Section) bool) {
+	if s == nil || !fn(s) {
+		return
+	}
+	for _, child := range s.Children {
+		child.Walk(fn)
+	}
+}
+
+// Flatten returns s and its descendants in document order, e.g. for a table of contents.
+func (s *Section) Flatten() []*Section {
+	sections := []*Section{}
+	s.Walk(func(section *Section) bool {
+		sections = append(sections, section)
+		return true
+	})
+	return sections
+}
+
+// SectionForOffset returns the innermost section whose byte span contains byteOffset,
+// e.g. to attach a breadcrumb to a chunk via Chunk.Metadata.ByteStart. It returns nil
+// when no section contains the offset.
+func (s *Section) SectionForOffset(byteOffset int) *Section {
+	if s == nil || s.ByteStart < 0 || byteOffset < s.ByteStart || byteOffset >= s.ByteEnd {
+		return nil
+	}
+	for _, child := range s.Children {
+		if found := child.SectionForOffset(byteOffset); found != nil {
+			return found
+		}
+	}
+	return s
+}
+
+// BuildSectionTree builds the section tree of result from Elements, or from the
+// PageHierarchy of its Pages when no elements were extracted.
+func BuildSectionTree(result *ExtractionResult) *Section {
+	if result == nil {
+		return SectionTreeFromElements(nil, "")
+	}
+	if len(result.Elements) > 0 {
+		return SectionTreeFromElements(result.Elements, result.Content)
+	}
+	return SectionTreeFromHierarchy(result.Pages, result.Content)
+}
+
+// SectionTreeFromHierarchy builds a section tree from the PageHierarchy blocks of pages.
+// Heading blocks (h1 to h6) open sections and body blocks become narrative text content.
+func SectionTreeFromHierarchy(pages []PageContent, content string) *Section {
+	var elements []Element
+	for _, page := range pages {
+		if page.Hierarchy == nil {
+			continue
+		}
+		pageNumber := page.PageNumber
+		for _, block := range page.Hierarchy.Blocks {
+			el := Element{
+				ElementType: ElementTypeNarrativeText,
+				Text:        block.Text,
+				Metadata: ElementMetadata{
+					PageNumber: &pageNumber,
+					Additional: map[string]string{
+						"level":     block.Level,
+						"font_size": strconv.FormatFloat(float64(block.FontSize), 'f', -1, 32),
+					},
+				},
+			}
+			if block.Bbox != nil {
+				el.Metadata.Coordinates = &BoundingBox{
+					X0: float64(block.Bbox[0]), Y0: float64(block.Bbox[1]),
+					X1: float64(block.Bbox[2]), Y1: float64(block.Bbox[3]),
+				}
+			}
+			if _, ok := headingLevel(block.Level); ok {
+				el.ElementType = ElementTypeTitle
+			}
+			elements = append(elements, el)
+		}
+	}
+	return SectionTreeFromElements(elements, content)
+}
+
+// SectionTreeFromElements builds a section tree from a flat element list. Title and
+// heading elements open sections; their level comes from the "level" entry ("h1" to
+// "h6") in ElementMetadata.Additional, defaulting to 0 for titles and 1 for headings.
+// content is used to compute byte spans and may be empty.
+func SectionTreeFromElements(elements []Element, content string) *Section {
+	root := &Section{Level: -1, Breadcrumb: []string{}, ByteStart: -1, ByteEnd: -1}
+	if content != "" {
+		root.ByteStart, root.ByteEnd = 0, len(content)
+	}
+
+	stack := []*Section{root}
+	order := []*Section{}
+	cursor := 0
+	for i := range elements {
+		el := elements[i]
+		offset := -1
+		if content != "" && strings.TrimSpace(el.Text) != "" {
+			text := strings.TrimSpace(el.Text)
+			if idx := strings.Index(content[cursor:], text); idx >= 0 {
+				offset = cursor + idx
+				cursor = offset + len(text)
+			}
+		}
+
+		level, isHeading := elementSectionLevel(&el)
+		if !isHeading {
+			top := stack[len(stack)-1]
+			top.Content = append(top.Content, el)
+			extendSectionPages(top, el.Metadata.PageNumber)
+			continue
+		}
+
+		for len(stack) > 1 && stack[len(stack)-1].Level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		title := strings.TrimSpace(el.Text)
+		section := &Section{
+			Title:      title,
+			Level:      level,
+			Breadcrumb: append(append([]string{}, parent.Breadcrumb...), title),
+			Heading:    &el,
+			ByteStart:  offset,
+			ByteEnd:    -1,
+		}
+		extendSectionPages(section, el.Metadata.PageNumber)
+		parent.Children = append(parent.Children, section)
+		stack = append(stack, section)
+		order = append(order, section)
+	}
+
+	for i, section := range order {
+		if section.ByteStart < 0 {
+			continue
+		}
+		section.ByteEnd = len(content)
+		for _, next := range order[i+1:] {
+			if next.Level <= section.Level && next.ByteStart >= 0 {
+				section.ByteEnd = next.ByteStart
+				break
+			}
+		}
+	}
+	propagateSectionPages(root)
+	return root
+}
+
+func elementSectionLevel(el *Element) (int, bool) {
+	switch el.ElementType {
+	case ElementTypeTitle:
+		if level, ok := headingLevel(el.Metadata.Additional["level"]); ok {
+			return level, true
+		}
+		return 0, true
+	case ElementTypeHeading:
+		if level, ok := headingLevel(el.Metadata.Additional["level"]); ok {
+			return level, true
+		}
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// headingLevel parses "h1" to "h6" (or "1" to "6").
+func headingLevel(raw string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(raw)), "h"))
+	if err != nil || n < 1 || n > 6 {
+		return 0, false
+	}
+	return n, true
+}
+
+func extendSectionPages(s *Section, page *uint64) {
+	if page == nil || *page == 0 {
+		return
+	}
+	p := int(*page)
+	if s.FirstPage == 0 || p < s.FirstPage {
+		s.FirstPage = p
+	}
+	if p > s.LastPage {
+		s.LastPage = p
+	}
+}
+
+func propagateSectionPages(s *Section) {
+	for _, child := range s.Children {
+		propagateSectionPages(child)
+		if child.FirstPage == 0 {
+			continue
+		}
+		first, last := uint64(child.FirstPage), uint64(child.LastPage)
+		extendSectionPages(s, &first)
+		extendSectionPages(s, &last)
+	}
+}
@@ -0,0 +1,91 @@
+package kreuzberg_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func sectionElement(typ kreuzberg.ElementType, text string, page uint64, level string) kreuzberg.Element {
+	el := kreuzberg.Element{ElementType: typ, Text: text, Metadata: kreuzberg.ElementMetadata{PageNumber: &page}}
+	if level != "" {
+		el.Metadata.Additional = map[string]string{"level": level}
+	}
+	return el
+}
+
+func TestSectionTreeFromElements(t *testing.T) {
+	content := "Preface\n\nAnnual Report\n\nIntro\n\nHello.\n\nRevenue\n\nUp 5%.\n\nOutlook\n\nStable."
+	elements := []kreuzberg.Element{
+		sectionElement(kreuzberg.ElementTypeNarrativeText, "Preface", 1, ""),
+		sectionElement(kreuzberg.ElementTypeTitle, "Annual Report", 1, ""),
+		sectionElement(kreuzberg.ElementTypeTitle, "Intro", 1, "h1"),
+		sectionElement(kreuzberg.ElementTypeNarrativeText, "Hello.", 2, ""),
+		sectionElement(kreuzberg.ElementTypeHeading, "Revenue", 3, "h2"),
+		sectionElement(kreuzberg.ElementTypeNarrativeText, "Up 5%.", 4, ""),
+		sectionElement(kreuzberg.ElementTypeHeading, "Outlook", 5, "h1"),
+		sectionElement(kreuzberg.ElementTypeNarrativeText, "Stable.", 5, ""),
+	}
+	root := kreuzberg.BuildSectionTree(&kreuzberg.ExtractionResult{Content: content, Elements: elements})
+
+	if len(root.Content) != 1 || root.Content[0].Text != "Preface" {
+		t.Errorf("root content = %+v", root.Content)
+	}
+	if len(root.Children) != 1 || root.Children[0].Title != "Annual Report" || root.Children[0].Level != 0 {
+		t.Fatalf("expected a single document title section, got %+v", root.Children)
+	}
+
+	var paths []string
+	for _, s := range root.Flatten()[1:] {
+		paths = append(paths, s.Path())
+	}
+	want := []string{"Annual Report", "Annual Report > Intro", "Annual Report > Intro > Revenue", "Annual Report > Outlook"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+
+	intro := root.Children[0].Children[0]
+	if intro.FirstPage != 1 || intro.LastPage != 4 {
+		t.Errorf("intro pages = %d-%d, want 1-4", intro.FirstPage, intro.LastPage)
+	}
+	if got := content[intro.ByteStart:intro.ByteEnd]; got != "Intro\n\nHello.\n\nRevenue\n\nUp 5%.\n\n" {
+		t.Errorf("intro span = %q", got)
+	}
+
+	revenue := intro.Children[0]
+	if found := root.SectionForOffset(strings.Index(content, "5%")); found != revenue {
+		t.Errorf("SectionForOffset() = %v, want Revenue", found)
+	}
+	if found := root.SectionForOffset(0); found != root {
+		t.Errorf("offset before the first heading should map to the root, got %v", found.Title)
+	}
+}
+
+func TestSectionTreeFromHierarchy(t *testing.T) {
+	pages := []kreuzberg.PageContent{
+		{PageNumber: 1, Hierarchy: &kreuzberg.PageHierarchy{Blocks: []kreuzberg.HierarchicalBlock{
+			{Text: "Methods", Level: "h1"},
+			{Text: "Sampling", Level: "h2"},
+			{Text: "We sampled.", Level: "body"},
+		}}},
+		{PageNumber: 2, Hierarchy: &kreuzberg.PageHierarchy{Blocks: []kreuzberg.HierarchicalBlock{
+			{Text: "Results", Level: "h1"},
+		}}},
+	}
+	root := kreuzberg.SectionTreeFromHierarchy(pages, "")
+	if len(root.Children) != 2 {
+		t.Fatalf("expected 2 top-level sections, got %d", len(root.Children))
+	}
+	sampling := root.Children[0].Children[0]
+	if sampling.Path() != "Methods > Sampling" || len(sampling.Content) != 1 {
+		t.Errorf("sampling = %q with %d elements", sampling.Path(), len(sampling.Content))
+	}
+	if sampling.ByteStart != -1 {
+		t.Errorf("byte span should be unknown without content, got %d", sampling.ByteStart)
+	}
+	if root.FirstPage != 1 || root.LastPage != 2 {
+		t.Errorf("root pages = %d-%d", root.FirstPage, root.LastPage)
+	}
+}
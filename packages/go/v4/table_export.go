@@ -0,0 +1,572 @@
+package kreuzberg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ColumnType is the value type inferred for a table column.
+type ColumnType string
+
+// String returns the column type name.
+func (c ColumnType) String() string { return string(c) }
+
+const (
+	// ColumnTypeEmpty marks a column without any non-empty body cell.
+	ColumnTypeEmpty ColumnType = "empty"
+	// ColumnTypeText marks a column with free-form text.
+	ColumnTypeText ColumnType = "text"
+	// ColumnTypeNumber marks a column where every non-empty cell is a number.
+	ColumnTypeNumber ColumnType = "number"
+	// ColumnTypeCurrency marks a column of numbers where at least one cell carries a
+	// currency symbol or ISO 4217 code.
+	ColumnTypeCurrency ColumnType = "currency"
+	// ColumnTypeDate marks a column where every non-empty cell is a date. Numeric
+	// dates must all read the same way: a column such as 03/04/2024 where day and
+	// month could be either way round is text.
+	ColumnTypeDate ColumnType = "date"
+)
+
+// TableHeaderMode controls how the header row of a table is chosen.
+type TableHeaderMode int
+
+const (
+	// TableHeaderAuto treats the first row as a header when it looks like one: its
+	// non-empty cells are distinct and none is a number, amount or date.
+	TableHeaderAuto TableHeaderMode = iota
+	// TableHeaderFirstRow always treats the first row as the header.
+	TableHeaderFirstRow
+	// TableHeaderNone treats every row as data and generates column_N names.
+	TableHeaderNone
+)
+
+// TableExportOptions controls Table.Records, Table.ToJSON and Table.ToHTML.
+type TableExportOptions struct {
+	// Header selects how the header row is chosen. Defaults to TableHeaderAuto.
+	Header TableHeaderMode
+	// RawValues keeps every cell as a string instead of converting numbers, amounts and
+	// dates according to the inferred column type.
+	RawValues bool
+}
+
+// Normalize returns a rectangular copy of the table cells: whitespace is trimmed and
+// collapsed, rows that are entirely empty are dropped, ragged rows are padded with
+// empty cells, and trailing columns that are empty in every row are removed.
+func (t *Table) Normalize() [][]string {
+	if t == nil {
+		return [][]string{}
+	}
+	rows := make([][]string, 0, len(t.Cells))
+	width := 0
+	for _, row := range t.Cells {
+		cells := make([]string, len(row))
+		empty := true
+		for i, cell := range row {
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+			if cells[i] != "" {
+				empty = false
+				if i+1 > width {
+					width = i + 1
+				}
+			}
+		}
+		if !empty {
+			rows = append(rows, cells)
+		}
+	}
+	for i, row := range rows {
+		if len(row) > width {
+			rows[i] = row[:width]
+		} else if len(row) < width {
+			rows[i] = append(row, make([]string, width-len(row))...)
+		}
+	}
+	return rows
+}
+
+// HasHeader reports whether TableHeaderAuto would treat the first row as a header.
+func (t *Table) HasHeader() bool {
+	return detectTableHeader(t.Normalize())
+}
+
+// Header returns the column names used for export along with the data rows. Missing
+// names become column_N and duplicates get a numeric suffix.
+func (t *Table) Header(opts *TableExportOptions) ([]string, [][]string) {
+	rows := t.Normalize()
+	mode := TableHeaderAuto
+	if opts != nil {
+		mode = opts.Header
+	}
+	useFirst := mode == TableHeaderFirstRow || (mode == TableHeaderAuto && detectTableHeader(rows))
+
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+	var names []string
+	if useFirst && len(rows) > 0 {
+		names, rows = rows[0], rows[1:]
+	} else {
+		names = make([]string, width)
+	}
+	return uniqueColumnNames(names), rows
+}
+
+// ColumnTypes infers the value type of each column from its data rows. The header row,
+// if any, is excluded.
+func (t *Table) ColumnTypes(opts *TableExportOptions) []ColumnType {
+	names, rows := t.Header(opts)
+	return inferColumnTypes(len(names), rows)
+}
+
+// ToCSV renders the normalized cells as RFC 4180 CSV, including the header row.
+func (t *Table) ToCSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(t.Normalize()); err != nil {
+		return "", newSerializationErrorWithContext("failed to write table as CSV", err, ErrorCodeValidation, nil)
+	}
+	return buf.String(), nil
+}
+
+// ToTSV renders the normalized cells as tab-separated values, including the header
+// row. Tabs inside cells are replaced by spaces.
+func (t *Table) ToTSV() string {
+	var b strings.Builder
+	for _, row := range t.Normalize() {
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteByte('\t')
+			}
+			b.WriteString(strings.ReplaceAll(cell, "\t", " "))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Records returns one map per data row keyed by column name. Unless opts.RawValues is
+// set, number and currency cells become float64, date cells become "2006-01-02"
+// strings, and empty cells become nil.
+func (t *Table) Records(opts *TableExportOptions) []map[string]interface{} {
+	names, rows := t.Header(opts)
+	values := tableValues(names, rows, opts)
+	records := make([]map[string]interface{}, len(values))
+	for i, row := range values {
+		record := make(map[string]interface{}, len(names))
+		for j, name := range names {
+			record[name] = row[j]
+		}
+		records[i] = record
+	}
+	return records
+}
+
+// ToJSON renders the data rows as a JSON array of objects, with keys in column order.
+// Values are converted as described for Records.
+func (t *Table) ToJSON(opts *TableExportOptions) ([]byte, error) {
+	names, rows := t.Header(opts)
+	values := tableValues(names, rows, opts)
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, row := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, name := range names {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(name)
+			if err != nil {
+				return nil, newSerializationErrorWithContext("failed to encode table column name", err, ErrorCodeValidation, nil)
+			}
+			value, err := json.Marshal(row[j])
+			if err != nil {
+				return nil, newSerializationErrorWithContext("failed to encode table value", err, ErrorCodeValidation, nil)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// ToHTML renders the table as an escaped HTML <table>. When a header row is used it is
+// emitted in <thead>; generated column names are not rendered.
+func (t *Table) ToHTML(opts *TableExportOptions) string {
+	rows := t.Normalize()
+	mode := TableHeaderAuto
+	if opts != nil {
+		mode = opts.Header
+	}
+	useHeader := len(rows) > 0 && (mode == TableHeaderFirstRow || (mode == TableHeaderAuto && detectTableHeader(rows)))
+
+	var b strings.Builder
+	b.WriteString("<table>\n")
+	if useHeader {
+		b.WriteString("<thead>\n<tr>")
+		for _, cell := range rows[0] {
+			b.WriteString("<th>" + html.EscapeString(cell) + "</th>")
+		}
+		b.WriteString("</tr>\n</thead>\n")
+		rows = rows[1:]
+	}
+	b.WriteString("<tbody>\n")
+	for _, row := range rows {
+		b.WriteString("<tr>")
+		for _, cell := range row {
+			b.WriteString("<td>" + html.EscapeString(cell) + "</td>")
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+func detectTableHeader(rows [][]string) bool {
+	if len(rows) < 2 {
+		return false
+	}
+	seen := make(map[string]bool, len(rows[0]))
+	for _, cell := range rows[0] {
+		if cell == "" {
+			continue
+		}
+		if seen[cell] || classifyTableCell(cell) != ColumnTypeText {
+			return false
+		}
+		seen[cell] = true
+	}
+	return len(seen) > 0
+}
+
+func uniqueColumnNames(names []string) []string {
+	out := make([]string, len(names))
+	seen := make(map[string]int, len(names))
+	for i, name := range names {
+		if name == "" {
+			name = "column_" + strconv.Itoa(i+1)
+		}
+		base := name
+		for seen[name] > 0 {
+			seen[base]++
+			name = base + "_" + strconv.Itoa(seen[base])
+		}
+		seen[name]++
+		out[i] = name
+	}
+	return out
+}
+
+func inferColumnTypes(width int, rows [][]string) []ColumnType {
+	types := make([]ColumnType, width)
+	for col := range types {
+		colType := ColumnTypeEmpty
+		for _, row := range rows {
+			if col >= len(row) || row[col] == "" {
+				continue
+			}
+			colType = mergeColumnTypes(colType, classifyTableCell(row[col]))
+			if colType == ColumnTypeText {
+				break
+			}
+		}
+		if colType == ColumnTypeDate {
+			if _, ok := tableDateColumnOrder(rows, col); !ok {
+				colType = ColumnTypeText
+			}
+		}
+		types[col] = colType
+	}
+	return types
+}
+
+func mergeColumnTypes(current, next ColumnType) ColumnType {
+	switch {
+	case current == ColumnTypeEmpty || current == next:
+		return next
+	case (current == ColumnTypeNumber && next == ColumnTypeCurrency) || (current == ColumnTypeCurrency && next == ColumnTypeNumber):
+		return ColumnTypeCurrency
+	default:
+		return ColumnTypeText
+	}
+}
+
+func classifyTableCell(cell string) ColumnType {
+	switch {
+	case cell == "":
+		return ColumnTypeEmpty
+	case isTableNumber(cell):
+		return ColumnTypeNumber
+	case isTableCurrency(cell):
+		return ColumnTypeCurrency
+	case isTableDate(cell):
+		return ColumnTypeDate
+	default:
+		return ColumnTypeText
+	}
+}
+
+func isTableNumber(cell string) bool {
+	_, ok := ParseTableNumber(cell)
+	return ok
+}
+
+func isTableCurrency(cell string) bool {
+	_, currency, ok := ParseTableCurrency(cell)
+	return ok && currency != ""
+}
+
+func isTableDate(cell string) bool {
+	_, ok := ParseTableDate(cell)
+	return ok
+}
+
+func tableValues(names []string, rows [][]string, opts *TableExportOptions) [][]interface{} {
+	raw := opts != nil && opts.RawValues
+	types := inferColumnTypes(len(names), rows)
+	orders := make([]tableDateOrder, len(names))
+	for j, colType := range types {
+		if colType == ColumnTypeDate {
+			orders[j], _ = tableDateColumnOrder(rows, j)
+		}
+	}
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		values[i] = make([]interface{}, len(names))
+		for j := range names {
+			cell := row[j]
+			switch {
+			case raw:
+				values[i][j] = cell
+			case cell == "":
+				values[i][j] = nil
+			case types[j] == ColumnTypeNumber:
+				n, _ := ParseTableNumber(cell)
+				values[i][j] = n
+			case types[j] == ColumnTypeCurrency:
+				n, _, _ := ParseTableCurrency(cell)
+				values[i][j] = n
+			case types[j] == ColumnTypeDate:
+				d, _ := parseTableDateOrder(cell, orders[j])
+				values[i][j] = d.Format("2006-01-02")
+			default:
+				values[i][j] = cell
+			}
+		}
+	}
+	return values
+}
+
+var (
+	tableThousandsComma = regexp.MustCompile(`^\d{1,3}(,\d{3})+(\.\d+)?$`)
+	tableThousandsDot   = regexp.MustCompile(`^\d{1,3}((\.\d{3}){2,}(,\d+)?|\.\d{3},\d+)$`)
+	tableDecimalComma   = regexp.MustCompile(`^\d+,\d{1,2}$`)
+	tablePlainNumber    = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+	tableCurrencyCode   = regexp.MustCompile(`^(?:([A-Z]{3})\s+(.+)|(.+?)\s+([A-Z]{3}))$`)
+)
+
+// ParseTableNumber parses a numeric cell. It accepts a leading sign, accounting-style
+// negatives such as "(1,200)", and thousands separators in either the "1,234.5" or
+// the "1.234,5" convention. A dot is read as a thousands separator only when it
+// separates two or more groups ("1.234.567") or is followed by a comma decimal part
+// ("1.234,5"), so "3.141" and "12.500" are decimals. Spaces are ignored.
+func ParseTableNumber(cell string) (float64, bool) {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' {
+			return -1
+		}
+		return r
+	}, cell)
+	if s == "" {
+		return 0, false
+	}
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = s[1 : len(s)-1]
+	}
+	switch {
+	case strings.HasPrefix(s, "-"), strings.HasPrefix(s, "−"):
+		negative = !negative
+		s = strings.TrimLeft(strings.TrimPrefix(s, "-"), "−")
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	switch {
+	case tableThousandsComma.MatchString(s):
+		s = strings.ReplaceAll(s, ",", "")
+	case tableThousandsDot.MatchString(s):
+		s = strings.ReplaceAll(strings.ReplaceAll(s, ".", ""), ",", ".")
+	case tableDecimalComma.MatchString(s):
+		s = strings.ReplaceAll(s, ",", ".")
+	case !tablePlainNumber.MatchString(s):
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	if negative {
+		n = -n
+	}
+	return n, true
+}
+
+// tableCurrencySymbols maps currency symbols to ISO 4217 codes. Longer symbols come
+// first so "US$" wins over "$".
+var tableCurrencySymbols = []struct{ symbol, code string }{
+	{"US$", "USD"}, {"R$", "BRL"}, {"CHF", "CHF"}, {"kr", "SEK"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"},
+	{"₽", "RUB"}, {"₩", "KRW"}, {"₺", "TRY"},
+}
+
+// ParseTableCurrency parses a monetary cell such as "$1,200.50", "-€3.000,00",
+// "(£12)" or "1200 USD". It returns the amount and the ISO 4217 code of the currency,
+// or "" when the cell is a plain number.
+func ParseTableCurrency(cell string) (float64, string, bool) {
+	s := strings.TrimSpace(cell)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = strings.TrimSpace(s[1:])
+	}
+
+	currency := ""
+	for _, c := range tableCurrencySymbols {
+		if strings.HasPrefix(s, c.symbol) {
+			currency, s = c.code, strings.TrimSpace(strings.TrimPrefix(s, c.symbol))
+			break
+		}
+		if strings.HasSuffix(s, c.symbol) {
+			currency, s = c.code, strings.TrimSpace(strings.TrimSuffix(s, c.symbol))
+			break
+		}
+	}
+	if currency == "" {
+		if m := tableCurrencyCode.FindStringSubmatch(s); m != nil {
+			currency, s = m[1]+m[4], m[2]+m[3]
+		}
+	}
+
+	n, ok := ParseTableNumber(s)
+	if !ok {
+		return 0, "", false
+	}
+	if negative {
+		n = -n
+	}
+	return n, currency, true
+}
+
+var tableDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"2-Jan-2006",
+	"02-Jan-2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+	"Jan 2006",
+	"January 2006",
+}
+
+// tableDateOrder is the order of day and month in numeric dates such as 03/04/2024.
+type tableDateOrder int
+
+const (
+	tableDateMonthFirst tableDateOrder = iota
+	tableDateDayFirst
+)
+
+// tableDateOrderLayouts holds the numeric layouts of each order. Dotted dates are
+// always day first.
+var tableDateOrderLayouts = [...][]string{
+	tableDateMonthFirst: {"1/2/2006"},
+	tableDateDayFirst:   {"2/1/2006", "2.1.2006"},
+}
+
+// ParseTableDate parses a date cell in one of the common ISO, US, European or
+// month-name layouts. Ambiguous numeric dates such as 03/04/2024 are read as
+// month/day first. Table.Records reads all dates of a column in the same order.
+func ParseTableDate(cell string) (time.Time, bool) {
+	for _, order := range []tableDateOrder{tableDateMonthFirst, tableDateDayFirst} {
+		if d, ok := parseTableDateOrder(cell, order); ok {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseTableDateOrder(cell string, order tableDateOrder) (time.Time, bool) {
+	s := strings.TrimSpace(cell)
+	if len(s) < 6 {
+		return time.Time{}, false
+	}
+	for _, layouts := range [][]string{tableDateLayouts, tableDateOrderLayouts[order]} {
+		for _, layout := range layouts {
+			if d, err := time.Parse(layout, s); err == nil {
+				return d, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// tableDateColumnOrder picks the order that parses every non-empty cell of column col.
+// It fails when neither order does, or when both do but read some cell differently,
+// since the column's dates are then ambiguous.
+func tableDateColumnOrder(rows [][]string, col int) (tableDateOrder, bool) {
+	fits := func(order tableDateOrder) bool {
+		for _, row := range rows {
+			if col < len(row) && row[col] != "" {
+				if _, ok := parseTableDateOrder(row[col], order); !ok {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	monthFirst, dayFirst := fits(tableDateMonthFirst), fits(tableDateDayFirst)
+	switch {
+	case monthFirst && dayFirst:
+		for _, row := range rows {
+			if col < len(row) && row[col] != "" {
+				md, _ := parseTableDateOrder(row[col], tableDateMonthFirst)
+				dm, _ := parseTableDateOrder(row[col], tableDateDayFirst)
+				if !md.Equal(dm) {
+					return 0, false
+				}
+			}
+		}
+		return tableDateMonthFirst, true
+	case monthFirst:
+		return tableDateMonthFirst, true
+	case dayFirst:
+		return tableDateDayFirst, true
+	}
+	return 0, false
+}
@@ -0,0 +1,163 @@
+package kreuzberg_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	kreuzberg "github.com/kreuzberg-dev/kreuzberg/packages/go/v4"
+)
+
+func newFinanceTable() *kreuzberg.Table {
+	return &kreuzberg.Table{Cells: [][]string{
+		{" Date ", "Account", "Amount", "Units"},
+		{"2024-01-31", "Revenue", "$1,200.50", "12"},
+		{"", "", "", ""},
+		{"2024-02-29", "Cost  of\nsales", "(300.00)"},
+		{"2024-03-31", "Fees", "-€3.000,00", "1.5", ""},
+	}}
+}
+
+func TestTableNormalize(t *testing.T) {
+	want := [][]string{
+		{"Date", "Account", "Amount", "Units"},
+		{"2024-01-31", "Revenue", "$1,200.50", "12"},
+		{"2024-02-29", "Cost of sales", "(300.00)", ""},
+		{"2024-03-31", "Fees", "-€3.000,00", "1.5"},
+	}
+	if got := newFinanceTable().Normalize(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Normalize() = %q, want %q", got, want)
+	}
+}
+
+func TestTableColumnTypes(t *testing.T) {
+	table := newFinanceTable()
+	if !table.HasHeader() {
+		t.Fatal("first row should be detected as header")
+	}
+	want := []kreuzberg.ColumnType{kreuzberg.ColumnTypeDate, kreuzberg.ColumnTypeText, kreuzberg.ColumnTypeCurrency, kreuzberg.ColumnTypeNumber}
+	if got := table.ColumnTypes(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnTypes() = %v, want %v", got, want)
+	}
+
+	numeric := &kreuzberg.Table{Cells: [][]string{{"1", "2"}, {"3", "4"}}}
+	if numeric.HasHeader() {
+		t.Error("numeric first row should not be a header")
+	}
+}
+
+func TestTableToJSON(t *testing.T) {
+	data, err := newFinanceTable().ToJSON(nil)
+	if err != nil {
+		t.Fatalf("ToJSON() error = %v", err)
+	}
+	want := `[{"Date":"2024-01-31","Account":"Revenue","Amount":1200.5,"Units":12},` +
+		`{"Date":"2024-02-29","Account":"Cost of sales","Amount":-300,"Units":null},` +
+		`{"Date":"2024-03-31","Account":"Fees","Amount":-3000,"Units":1.5}]`
+	if string(data) != want {
+		t.Errorf("ToJSON() =\n%s\nwant\n%s", data, want)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("ToJSON() produced invalid JSON: %v", err)
+	}
+
+	raw := newFinanceTable().Records(&kreuzberg.TableExportOptions{Header: kreuzberg.TableHeaderNone, RawValues: true})
+	if len(raw) != 4 || raw[0]["column_1"] != "Date" || raw[1]["column_3"] != "$1,200.50" {
+		t.Errorf("Records(raw, no header) = %v", raw)
+	}
+}
+
+func TestTableDateColumnOrder(t *testing.T) {
+	table := &kreuzberg.Table{Cells: [][]string{
+		{"Day first", "Month first", "Ambiguous", "Mixed"},
+		{"03/04/2024", "03/04/2024", "03/04/2024", "25.12.2024"},
+		{"25/12/2024", "12/25/2024", "05/06/2024", "12/25/2024"},
+	}}
+	want := []kreuzberg.ColumnType{kreuzberg.ColumnTypeDate, kreuzberg.ColumnTypeDate, kreuzberg.ColumnTypeText, kreuzberg.ColumnTypeText}
+	if got := table.ColumnTypes(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnTypes() = %v, want %v", got, want)
+	}
+
+	records := table.Records(nil)
+	if got := []interface{}{records[0]["Day first"], records[1]["Day first"]}; !reflect.DeepEqual(got, []interface{}{"2024-04-03", "2024-12-25"}) {
+		t.Errorf("day-first column = %v", got)
+	}
+	if got := []interface{}{records[0]["Month first"], records[1]["Month first"]}; !reflect.DeepEqual(got, []interface{}{"2024-03-04", "2024-12-25"}) {
+		t.Errorf("month-first column = %v", got)
+	}
+	if records[0]["Ambiguous"] != "03/04/2024" {
+		t.Errorf("ambiguous column = %v, want the cell text", records[0]["Ambiguous"])
+	}
+}
+
+func TestTableCSVTSVAndHTML(t *testing.T) {
+	table := &kreuzberg.Table{Cells: [][]string{{"Name", "Note"}, {"A", "x, \"y\""}, {"<b>", "tab\there"}}}
+
+	csvOut, err := table.ToCSV()
+	if err != nil {
+		t.Fatalf("ToCSV() error = %v", err)
+	}
+	if want := "Name,Note\nA,\"x, \"\"y\"\"\"\n<b>,tab here\n"; csvOut != want {
+		t.Errorf("ToCSV() = %q, want %q", csvOut, want)
+	}
+	if want := "Name\tNote\nA\tx, \"y\"\n<b>\ttab here\n"; table.ToTSV() != want {
+		t.Errorf("ToTSV() = %q, want %q", table.ToTSV(), want)
+	}
+
+	htmlOut := table.ToHTML(nil)
+	for _, want := range []string{"<thead>\n<tr><th>Name</th><th>Note</th></tr>\n</thead>", "<td>&lt;b&gt;</td>"} {
+		if !strings.Contains(htmlOut, want) {
+			t.Errorf("ToHTML() missing %q:\n%s", want, htmlOut)
+		}
+	}
+	if strings.Contains(table.ToHTML(&kreuzberg.TableExportOptions{Header: kreuzberg.TableHeaderNone}), "<thead>") {
+		t.Error("TableHeaderNone should not emit <thead>")
+	}
+}
+
+func TestTableHeaderNames(t *testing.T) {
+	table := &kreuzberg.Table{Cells: [][]string{{"", "Total", "Total"}, {"a", "1", "2"}}}
+	names, rows := table.Header(&kreuzberg.TableExportOptions{Header: kreuzberg.TableHeaderFirstRow})
+	if want := []string{"column_1", "Total", "Total_2"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Header() names = %v, want %v", names, want)
+	}
+	if len(rows) != 1 {
+		t.Errorf("Header() rows = %v", rows)
+	}
+}
+
+func TestParseTableValues(t *testing.T) {
+	numbers := map[string]float64{"1,234.5": 1234.5, "1.234,5": 1234.5, "12,5": 12.5, "(42)": -42, "-7": -7, "1 000": 1000, ".5": 0.5,
+		"0.125": 0.125, "3.141": 3.141, "12.500": 12.5, "1.234.567": 1234567, "1.234.567,89": 1234567.89}
+	for in, want := range numbers {
+		if got, ok := kreuzberg.ParseTableNumber(in); !ok || got != want {
+			t.Errorf("ParseTableNumber(%q) = %v, %v; want %v", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1.2.3x", "12%"} {
+		if _, ok := kreuzberg.ParseTableNumber(in); ok {
+			t.Errorf("ParseTableNumber(%q) should fail", in)
+		}
+	}
+
+	amount, currency, ok := kreuzberg.ParseTableCurrency("1 200 USD")
+	if !ok || amount != 1200 || currency != "USD" {
+		t.Errorf("ParseTableCurrency() = %v, %q, %v", amount, currency, ok)
+	}
+	amount, currency, ok = kreuzberg.ParseTableCurrency("(£12.30)")
+	if !ok || amount != -12.3 || currency != "GBP" {
+		t.Errorf("ParseTableCurrency() = %v, %q, %v", amount, currency, ok)
+	}
+
+	for _, in := range []string{"2024-03-05", "03/05/2024", "25.12.2024", "March 5, 2024", "5 Mar 2024"} {
+		if _, ok := kreuzberg.ParseTableDate(in); !ok {
+			t.Errorf("ParseTableDate(%q) should succeed", in)
+		}
+	}
+	if _, ok := kreuzberg.ParseTableDate("Revenue"); ok {
+		t.Error("ParseTableDate(Revenue) should fail")
+	}
+}
@@ -0,0 +1,237 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// TableSourcePagesKey is the Metadata.Additional key under which the table stitching
+// post-processor records the source pages of each stitched table, as a JSON array of
+// page-number arrays parallel to ExtractionResult.Tables.
+const TableSourcePagesKey = "table_source_pages"
+
+// DefaultTableHeaderSimilarity is the fraction of matching header cells above which a
+// table's first row is treated as a repeat of the previous table's header.
+const DefaultTableHeaderSimilarity = 0.8
+
+// TableStitchOptions controls StitchTables.
+type TableStitchOptions struct {
+	// HeaderSimilarity is the minimum fraction of equal header cells (case- and
+	// whitespace-insensitive) for a repeated header to be recognized. Defaults to
+	// DefaultTableHeaderSimilarity.
+	HeaderSimilarity float64
+	// MaxPageGap is the largest page distance between two fragments of one table.
+	// Defaults to 1, i.e. fragments must be on consecutive pages.
+	MaxPageGap int
+}
+
+// StitchTables merges tables that continue across page boundaries into one logical
+// table and returns the result; tables is not modified.
+//
+// A table continues the previous one when it is the first table on its page, the
+// previous table is the last table on its page, the pages are at most MaxPageGap
+// apart, and both have the same number of columns. If its first row repeats the
+// previous header, that row is dropped; if it instead starts with a different header,
+// it is treated as a new table. Merged tables record their pages in SourcePages, take
+// the PageNumber of their first fragment, and get a regenerated Markdown rendering.
+func StitchTables(tables []Table, opts *TableStitchOptions) []Table {
+	similarity := DefaultTableHeaderSimilarity
+	maxGap := 1
+	if opts != nil {
+		if opts.HeaderSimilarity > 0 {
+			similarity = opts.HeaderSimilarity
+		}
+		if opts.MaxPageGap > 0 {
+			maxGap = opts.MaxPageGap
+		}
+	}
+
+	out := make([]Table, 0, len(tables))
+	merged := false
+	for i := range tables {
+		next := tables[i]
+		if len(out) > 0 && (i == 0 || tables[i-1].PageNumber != next.PageNumber) {
+			prev := &out[len(out)-1]
+			if rows, ok := tableContinuation(prev, &next, similarity, maxGap); ok {
+				if prev.SourcePages == nil {
+					prev.SourcePages = []int{prev.PageNumber}
+				}
+				prev.Cells = append(prev.Cells, rows...)
+				prev.SourcePages = append(prev.SourcePages, next.PageNumber)
+				merged = true
+				continue
+			}
+		}
+		next.Cells = append([][]string(nil), next.Cells...)
+		next.SourcePages = append([]int(nil), next.SourcePages...)
+		out = append(out, next)
+	}
+	if merged {
+		for i := range out {
+			if len(out[i].SourcePages) > 1 {
+				out[i].Markdown = markdownTable(&out[i])
+			}
+		}
+	}
+	return out
+}
+
+// StitchPageTables collects the tables of pages in page order and stitches them with
+// StitchTables.
+func StitchPageTables(pages []PageContent, opts *TableStitchOptions) []Table {
+	ordered := make([]PageContent, len(pages))
+	copy(ordered, pages)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].PageNumber < ordered[j].PageNumber })
+
+	var tables []Table
+	for _, page := range ordered {
+		for _, table := range page.Tables {
+			if table.PageNumber == 0 {
+				table.PageNumber = int(page.PageNumber)
+			}
+			tables = append(tables, table)
+		}
+	}
+	return StitchTables(tables, opts)
+}
+
+// StitchResultTables replaces result.Tables with StitchTables(result.Tables, opts).
+func StitchResultTables(result *ExtractionResult, opts *TableStitchOptions) {
+	if result == nil {
+		return
+	}
+	result.Tables = StitchTables(result.Tables, opts)
+}
+
+// TableSourcePages returns the pages the table at index was assembled from. It uses
+// Table.SourcePages when set, then the record left by the table stitching
+// post-processor, and finally the table's own PageNumber. It returns nil when index
+// is out of range.
+func (r *ExtractionResult) TableSourcePages(index int) []int {
+	if r == nil || index < 0 || index >= len(r.Tables) {
+		return nil
+	}
+	if pages := r.Tables[index].SourcePages; len(pages) > 0 {
+		return append([]int(nil), pages...)
+	}
+	if raw, ok := r.Metadata.Additional[TableSourcePagesKey]; ok {
+		var all [][]int
+		if err := json.Unmarshal(raw, &all); err == nil && index < len(all) && len(all[index]) > 0 {
+			return all[index]
+		}
+	}
+	return []int{r.Tables[index].PageNumber}
+}
+
+// tableContinuation reports whether next continues prev and returns the rows of next to
+// append.
+func tableContinuation(prev, next *Table, similarity float64, maxGap int) ([][]string, bool) {
+	lastPage := prev.PageNumber
+	if n := len(prev.SourcePages); n > 0 {
+		lastPage = prev.SourcePages[n-1]
+	}
+	gap := next.PageNumber - lastPage
+	if prev.PageNumber <= 0 || gap < 1 || gap > maxGap {
+		return nil, false
+	}
+
+	width := tableWidth(prev.Cells)
+	if width == 0 || width != tableWidth(next.Cells) || len(next.Cells) == 0 {
+		return nil, false
+	}
+
+	rows := next.Cells
+	header := prev.Cells[0]
+	first := next.Cells[0]
+	switch {
+	case headerSimilarity(header, first) >= similarity:
+		rows = rows[1:]
+	case detectTableHeader(next.Normalize()) && detectTableHeader((&Table{Cells: prev.Cells}).Normalize()):
+		// Both fragments start with their own, different header: separate tables.
+		return nil, false
+	}
+	return append([][]string(nil), rows...), true
+}
+
+func tableWidth(cells [][]string) int {
+	width := 0
+	for _, row := range cells {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
+
+func headerSimilarity(a, b []string) float64 {
+	width := len(a)
+	if len(b) > width {
+		width = len(b)
+	}
+	if width == 0 {
+		return 0
+	}
+	matches := 0
+	for i := 0; i < width; i++ {
+		var x, y string
+		if i < len(a) {
+			x = strings.ToLower(strings.Join(strings.Fields(a[i]), " "))
+		}
+		if i < len(b) {
+			y = strings.ToLower(strings.Join(strings.Fields(b[i]), " "))
+		}
+		if x == y {
+			matches++
+		}
+	}
+	return float64(matches) / float64(width)
+}
+
+// stitchResultJSON applies StitchTables to the tables of a JSON-encoded
+// ExtractionResult and records the source pages under TableSourcePagesKey, leaving
+// every other field untouched.
+func stitchResultJSON(data []byte, opts *TableStitchOptions) ([]byte, error) {
+	var result map[string]json.RawMessage
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, newSerializationErrorWithContext("failed to decode extraction result", err, ErrorCodeValidation, nil)
+	}
+	var tables []Table
+	if raw, ok := result["tables"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &tables); err != nil {
+			return nil, newSerializationErrorWithContext("failed to decode tables", err, ErrorCodeValidation, nil)
+		}
+	}
+	if len(tables) < 2 {
+		return data, nil
+	}
+
+	stitched := StitchTables(tables, opts)
+	sources := make([][]int, len(stitched))
+	for i := range stitched {
+		sources[i] = stitched[i].SourcePages
+		if len(sources[i]) == 0 {
+			sources[i] = []int{stitched[i].PageNumber}
+		}
+		stitched[i].SourcePages = nil
+	}
+
+	metadata := map[string]json.RawMessage{}
+	if raw, ok := result["metadata"]; ok && string(raw) != "null" {
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			return nil, newSerializationErrorWithContext("failed to decode metadata", err, ErrorCodeValidation, nil)
+		}
+	}
+
+	var err error
+	if metadata[TableSourcePagesKey], err = json.Marshal(sources); err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode table source pages", err, ErrorCodeValidation, nil)
+	}
+	if result["metadata"], err = json.Marshal(metadata); err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode metadata", err, ErrorCodeValidation, nil)
+	}
+	if result["tables"], err = json.Marshal(stitched); err != nil {
+		return nil, newSerializationErrorWithContext("failed to encode tables", err, ErrorCodeValidation, nil)
+	}
+	return json.Marshal(result)
+}
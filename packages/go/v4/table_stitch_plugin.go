@@ -0,0 +1,61 @@
+package kreuzberg
+
+/*
+#include "internal/ffi/kreuzberg.h"
+#include <stdlib.h>
+
+extern char* kreuzbergGoStitchTablesPostProcessor(char* result_json);
+*/
+import "C"
+
+import "sync"
+
+// TableStitchingPostProcessorName is the name under which
+// RegisterTableStitchingPostProcessor registers the built-in post-processor.
+const TableStitchingPostProcessorName = "go_table_stitching"
+
+var (
+	tableStitchingMu   sync.RWMutex
+	tableStitchingOpts *TableStitchOptions
+)
+
+// RegisterTableStitchingPostProcessor registers a post-processor in the native
+// pipeline that applies StitchTables to ExtractionResult.Tables of every extraction.
+//
+// The native result type has no field for source pages, so they are recorded in
+// Metadata.Additional under TableSourcePagesKey; read them with
+// ExtractionResult.TableSourcePages. Stitching is an enhancement, so a result the
+// processor cannot decode is passed on unchanged rather than failing the extraction.
+// Registering again replaces the options. Remove the
+// processor with UnregisterPostProcessor(TableStitchingPostProcessorName).
+func RegisterTableStitchingPostProcessor(priority int32, opts *TableStitchOptions) error {
+	var saved *TableStitchOptions
+	if opts != nil {
+		copied := *opts
+		saved = &copied
+	}
+	tableStitchingMu.Lock()
+	tableStitchingOpts = saved
+	tableStitchingMu.Unlock()
+
+	callback := (C.PostProcessorCallback)(C.kreuzbergGoStitchTablesPostProcessor)
+	return RegisterPostProcessor(TableStitchingPostProcessorName, priority, callback)
+}
+
+//export kreuzbergGoStitchTablesPostProcessor
+func kreuzbergGoStitchTablesPostProcessor(resultJSON *C.char) *C.char {
+	if resultJSON == nil {
+		return nil
+	}
+	tableStitchingMu.RLock()
+	opts := tableStitchingOpts
+	tableStitchingMu.RUnlock()
+
+	out, err := stitchResultJSON([]byte(C.GoString(resultJSON)), opts)
+	if err != nil {
+		// Unlike redaction, which must not let an unredacted result through, a result
+		// that cannot be stitched is still a good result.
+		return C.CString(C.GoString(resultJSON))
+	}
+	return C.CString(string(out))
+}
@@ -0,0 +1,101 @@
+package kreuzberg
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newStitchFragments() []Table {
+	return []Table{
+		{Cells: [][]string{{"Item", "Qty"}, {"Apples", "3"}}, PageNumber: 1},
+		{Cells: [][]string{{"item ", "QTY"}, {"Pears", "5"}}, PageNumber: 2},
+		{Cells: [][]string{{"Plums", "7"}}, PageNumber: 3},
+		{Cells: [][]string{{"Name", "Role", "Team"}, {"Ada", "Dev", "Core"}}, PageNumber: 4},
+	}
+}
+
+func TestStitchTables(t *testing.T) {
+	input := newStitchFragments()
+	got := StitchTables(input, nil)
+	if len(got) != 2 {
+		t.Fatalf("StitchTables() returned %d tables, want 2", len(got))
+	}
+
+	want := [][]string{{"Item", "Qty"}, {"Apples", "3"}, {"Pears", "5"}, {"Plums", "7"}}
+	if !reflect.DeepEqual(got[0].Cells, want) {
+		t.Errorf("stitched cells = %v, want %v", got[0].Cells, want)
+	}
+	if !reflect.DeepEqual(got[0].SourcePages, []int{1, 2, 3}) || got[0].PageNumber != 1 {
+		t.Errorf("source pages = %v, page = %d", got[0].SourcePages, got[0].PageNumber)
+	}
+	if !strings.Contains(got[0].Markdown, "| Plums | 7 |") {
+		t.Errorf("markdown should be regenerated, got %q", got[0].Markdown)
+	}
+	if got[1].SourcePages != nil {
+		t.Errorf("unmerged table should not get source pages, got %v", got[1].SourcePages)
+	}
+	if len(input[0].Cells) != 2 {
+		t.Error("StitchTables must not modify its input")
+	}
+}
+
+func TestStitchTablesKeepsSeparateTables(t *testing.T) {
+	tables := []Table{
+		{Cells: [][]string{{"Item", "Qty"}, {"Apples", "3"}}, PageNumber: 1},
+		{Cells: [][]string{{"Region", "Sales"}, {"North", "10"}}, PageNumber: 2},
+		{Cells: [][]string{{"South", "12"}}, PageNumber: 4},
+		{Cells: [][]string{{"East", "9"}}, PageNumber: 4},
+	}
+	if got := StitchTables(tables, nil); len(got) != 4 {
+		t.Errorf("different headers, page gaps and same-page tables must not merge, got %d tables", len(got))
+	}
+	if got := StitchTables(tables, &TableStitchOptions{MaxPageGap: 2}); len(got) != 3 {
+		t.Errorf("MaxPageGap 2 should merge the page 4 fragment, got %d tables", len(got))
+	}
+}
+
+func TestStitchPageTables(t *testing.T) {
+	pages := []PageContent{
+		{PageNumber: 2, Tables: []Table{{Cells: [][]string{{"b", "2"}}}}},
+		{PageNumber: 1, Tables: []Table{{Cells: [][]string{{"k", "v"}, {"a", "1"}}}}},
+	}
+	got := StitchPageTables(pages, nil)
+	if len(got) != 1 || len(got[0].Cells) != 3 || !reflect.DeepEqual(got[0].SourcePages, []int{1, 2}) {
+		t.Errorf("StitchPageTables() = %+v", got)
+	}
+}
+
+func TestStitchResultJSON(t *testing.T) {
+	tables, err := json.Marshal(newStitchFragments())
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := `{"content":"x","metadata":{"title":"T"},"tables":` + string(tables) + `}`
+
+	out, err := stitchResultJSON([]byte(input), nil)
+	if err != nil {
+		t.Fatalf("stitchResultJSON() error = %v", err)
+	}
+
+	var result ExtractionResult
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatalf("output is not a valid result: %v", err)
+	}
+	if len(result.Tables) != 2 || result.Content != "x" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if result.Tables[0].SourcePages != nil {
+		t.Error("source pages should be stored in metadata, not on the table")
+	}
+	if got := result.TableSourcePages(0); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("TableSourcePages(0) = %v", got)
+	}
+	if got := result.TableSourcePages(1); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("TableSourcePages(1) = %v", got)
+	}
+	if !strings.Contains(string(out), `"title":"T"`) {
+		t.Errorf("other metadata should be preserved: %s", out)
+	}
+}
@@ -14,13 +14,21 @@ type ExtractionResult struct {
 	Pages             []PageContent    `json:"pages,omitempty"`
 	Elements          []Element        `json:"elements,omitempty"`
 	DjotContent       *DjotContent     `json:"djot_content,omitempty"`
+	// Decryption is set by ExtractFileWithPasswords and ExtractBytesWithPasswords. It
+	// is not serialized.
+	Decryption *DecryptionInfo `json:"-"`
+	// PageSelection is set when PageConfig.Ranges selected the pages of the result. It
+	// is not serialized.
+	PageSelection *PageSelection `json:"-"`
 }
 
-// Table represents a detected table in the source document.
+// Table represents a detected table in the source document. SourcePages is only set
+// on tables merged by StitchTables.
 type Table struct {
-	Cells      [][]string `json:"cells"`
-	Markdown   string     `json:"markdown"`
-	PageNumber int        `json:"page_number"`
+	Cells       [][]string `json:"cells"`
+	Markdown    string     `json:"markdown"`
+	PageNumber  int        `json:"page_number"`
+	SourcePages []int      `json:"source_pages,omitempty"`
 }
 
 // Chunk contains chunked content plus optional embeddings and metadata.
@@ -73,6 +81,11 @@ type Metadata struct {
 	JSONSchema         json.RawMessage             `json:"json_schema,omitempty"`
 	Error              *ErrorMetadata              `json:"error,omitempty"`
 	Additional         map[string]json.RawMessage  `json:"-"`
+	// ParsedDates holds typed creation and modification dates once ResolveDates has
+	// been called. It is not serialized: the metadata JSON mirrors the native metadata
+	// and is passed back to the native library, and the dates are derived from
+	// CreatedAt and ModifiedAt, so call ResolveDates again after decoding.
+	ParsedDates *MetadataDates `json:"-"`
 }
 
 // FormatMetadata represents the discriminated union of metadata formats.
@@ -88,6 +101,23 @@ type FormatMetadata struct {
 	Text    *TextMetadata
 	HTML    *HtmlMetadata
 	OCR     *OcrMetadata
+
+	// Formats below are not tagged by the native library; they are recognized from the
+	// result's MIME type. See metadata_formats.go.
+	Docx        *DocxMetadata
+	Odt         *OdtMetadata
+	Epub        *EpubMetadata
+	FictionBook *FictionBookMetadata
+	Jupyter     *JupyterMetadata
+	Latex       *LatexMetadata
+	Rst         *RstMetadata
+	OrgMode     *OrgModeMetadata
+	Typst       *TypstMetadata
+	Bibtex      *BibtexMetadata
+	Opml        *OpmlMetadata
+	Jats        *JatsMetadata
+	DocBook     *DocBookMetadata
+	Rtf         *RtfMetadata
 }
 
 // FormatType enumerates supported metadata discriminators.
@@ -105,6 +135,21 @@ const (
 	FormatText    FormatType = "text"
 	FormatHTML    FormatType = "html"
 	FormatOCR     FormatType = "ocr"
+
+	FormatDOCX        FormatType = "docx"
+	FormatODT         FormatType = "odt"
+	FormatEPUB        FormatType = "epub"
+	FormatFictionBook FormatType = "fictionbook"
+	FormatJupyter     FormatType = "jupyter"
+	FormatLaTeX       FormatType = "latex"
+	FormatRST         FormatType = "rst"
+	FormatOrgMode     FormatType = "orgmode"
+	FormatTypst       FormatType = "typst"
+	FormatBibTeX      FormatType = "bibtex"
+	FormatOPML        FormatType = "opml"
+	FormatJATS        FormatType = "jats"
+	FormatDocBook     FormatType = "docbook"
+	FormatRTF         FormatType = "rtf"
 )
 
 // FormatType returns the discriminated format string.
@@ -528,6 +573,8 @@ type FormattedBlock struct {
 	BlockType BlockType `json:"block_type"`
 	// Level is the heading level (1-6) for headings, or nesting level for lists (optional).
 	Level *uint64 `json:"level,omitempty"`
+	// TableIndex is the index into DjotContent.Tables for table blocks (optional).
+	TableIndex *uint64 `json:"table_index,omitempty"`
 	// InlineContent contains inline content within the block.
 	InlineContent []InlineElement `json:"inline_content"`
 	// Attributes contains element attributes (classes, IDs, key-value pairs).
@@ -560,6 +607,9 @@ const (
 	BlockTypeThematicBreak  BlockType = "thematic_break"
 	BlockTypeRawBlock       BlockType = "raw_block"
 	BlockTypeMathDisplay    BlockType = "math_display"
+	// BlockTypeTable marks the position of a table. Its TableIndex is the index into
+	// DjotContent.Tables.
+	BlockTypeTable BlockType = "table"
 )
 
 // InlineElement represents an inline element within a block.
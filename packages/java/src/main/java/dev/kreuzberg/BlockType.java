@@ -58,7 +58,10 @@ public enum BlockType {
 	RAW_BLOCK("raw_block"),
 
 	/** Display math block */
-	MATH_DISPLAY("math_display");
+	MATH_DISPLAY("math_display"),
+
+	/** Table position; the table index points into the extracted tables */
+	TABLE("table");
 
 	private final String wireValue;
 
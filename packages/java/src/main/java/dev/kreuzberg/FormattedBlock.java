@@ -22,6 +22,7 @@ import java.util.Optional;
 public final class FormattedBlock {
 	private final BlockType blockType;
 	private final Optional<Integer> level;
+	private final Optional<Integer> tableIndex;
 	@JsonDeserialize(contentAs = InlineElement.class)
 	private final List<InlineElement> inlineContent;
 	private final Optional<Attributes> attributes;
@@ -30,15 +31,22 @@ public final class FormattedBlock {
 	@JsonDeserialize(contentAs = FormattedBlock.class)
 	private final List<FormattedBlock> children;
 
+	public FormattedBlock(BlockType blockType, Optional<Integer> level, List<InlineElement> inlineContent,
+			Optional<Attributes> attributes, Optional<String> language, Optional<String> code,
+			List<FormattedBlock> children) {
+		this(blockType, level, Optional.empty(), inlineContent, attributes, language, code, children);
+	}
+
 	@JsonCreator
 	public FormattedBlock(@JsonProperty("block_type") BlockType blockType,
-			@JsonProperty("level") Optional<Integer> level,
+			@JsonProperty("level") Optional<Integer> level, @JsonProperty("table_index") Optional<Integer> tableIndex,
 			@JsonProperty("inline_content") List<InlineElement> inlineContent,
 			@JsonProperty("attributes") Optional<Attributes> attributes,
 			@JsonProperty("language") Optional<String> language, @JsonProperty("code") Optional<String> code,
 			@JsonProperty("children") List<FormattedBlock> children) {
 		this.blockType = Objects.requireNonNull(blockType, "blockType must not be null");
 		this.level = level != null ? level : Optional.empty();
+		this.tableIndex = tableIndex != null ? tableIndex : Optional.empty();
 		this.inlineContent = Collections
 				.unmodifiableList(inlineContent != null ? new ArrayList<>(inlineContent) : new ArrayList<>());
 		this.attributes = attributes != null ? attributes : Optional.empty();
@@ -104,6 +112,15 @@ public final class FormattedBlock {
 		return level;
 	}
 
+	/**
+	 * Get the index into {@link DjotContent#getTables()} for table blocks.
+	 *
+	 * @return optional table index
+	 */
+	public Optional<Integer> getTableIndex() {
+		return tableIndex;
+	}
+
 	/**
 	 * Get the inline content within this block.
 	 *
@@ -193,6 +210,7 @@ public final class FormattedBlock {
 		}
 		FormattedBlock other = (FormattedBlock) obj;
 		return Objects.equals(blockType, other.blockType) && Objects.equals(level, other.level)
+				&& Objects.equals(tableIndex, other.tableIndex)
 				&& Objects.equals(inlineContent, other.inlineContent) && Objects.equals(attributes, other.attributes)
 				&& Objects.equals(language, other.language) && Objects.equals(code, other.code)
 				&& Objects.equals(children, other.children);
@@ -200,12 +218,13 @@ public final class FormattedBlock {
 
 	@Override
 	public int hashCode() {
-		return Objects.hash(blockType, level, inlineContent, attributes, language, code, children);
+		return Objects.hash(blockType, level, tableIndex, inlineContent, attributes, language, code, children);
 	}
 
 	@Override
 	public String toString() {
-		return "FormattedBlock{" + "blockType=" + blockType + ", level=" + level + ", inlineContent="
+		return "FormattedBlock{" + "blockType=" + blockType + ", level=" + level + ", tableIndex=" + tableIndex
+				+ ", inlineContent="
 				+ inlineContent.size() + ", attributes=" + attributes + ", language=" + language + ", code="
 				+ (code.isPresent() ? code.get().length() : "none") + ", children=" + children.size() + '}';
 	}
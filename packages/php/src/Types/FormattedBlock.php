@@ -11,6 +11,7 @@ namespace Kreuzberg\Types;
  *
  * @property-read string $blockType Type of block element
  * @property-read int|null $level Heading level (1-6) or nesting level for lists
+ * @property-read int|null $tableIndex Index into the Djot content's tables for table blocks
  * @property-read string|null $content Text content for inline elements
  * @property-read array<FormattedBlock> $children Child blocks for list items and containers
  * @property-read array<string, mixed>|null $attributes HTML/CSS attributes (id, class, etc.)
@@ -27,6 +28,7 @@ readonly class FormattedBlock
         public ?string $content = null,
         public array $children = [],
         public ?array $attributes = null,
+        public ?int $tableIndex = null,
     ) {
     }
 
@@ -60,12 +62,16 @@ readonly class FormattedBlock
         /** @var array<string, mixed>|null $attributes */
         $attributes = $data['attributes'] ?? null;
 
+        /** @var int|null $tableIndex */
+        $tableIndex = $data['table_index'] ?? null;
+
         return new self(
             blockType: $blockType,
             level: $level,
             content: $content,
             children: $children,
             attributes: $attributes,
+            tableIndex: $tableIndex,
         );
     }
 }
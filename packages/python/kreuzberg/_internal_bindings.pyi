@@ -729,6 +729,8 @@ class PageConfig:
         extract_pages (bool): Enable page tracking and per-page extraction. Default: False
         insert_page_markers (bool): Insert page markers into `content`. Default: False
         marker_format (str): Marker template containing `{page_num}`. Default: "\\n\\n<!-- PAGE {page_num} -->\\n\\n"
+        ranges (str | None): Pages to extract, e.g. "1-3,10,-2". Only PDF extraction honours
+            the selection. Default: None (every page)
 
     Example:
         >>> from kreuzberg import ExtractionConfig, PageConfig
@@ -738,6 +740,7 @@ class PageConfig:
     extract_pages: bool
     insert_page_markers: bool
     marker_format: str
+    ranges: str | None
 
     def __init__(
         self,
@@ -745,6 +748,7 @@ class PageConfig:
         extract_pages: bool | None = None,
         insert_page_markers: bool | None = None,
         marker_format: str | None = None,
+        ranges: str | None = None,
     ) -> None: ...
 
 class KeywordAlgorithm:
@@ -1453,6 +1457,7 @@ class Attributes(TypedDict, total=False):
 class FormattedBlock(TypedDict, total=False):
     block_type: str
     level: int | None
+    table_index: int | None
     inline_content: list[InlineElement]
     attributes: Attributes | None
     language: str | None
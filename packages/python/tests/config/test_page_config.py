@@ -11,6 +11,15 @@ def test_page_config_default_construction() -> None:
     assert config.extract_pages is False
     assert config.insert_page_markers is False
     assert config.marker_format == "\n\n<!-- PAGE {page_num} -->\n\n"
+    assert config.ranges is None
+
+
+def test_page_config_ranges() -> None:
+    """PageConfig should accept and update a page selection."""
+    config = PageConfig(ranges="1-3,-1")
+    assert config.ranges == "1-3,-1"
+    config.ranges = None
+    assert config.ranges is None
 
 
 def test_page_config_custom_values() -> None:
@@ -843,10 +843,17 @@ pub fn parse_page_config(ruby: &Ruby, hash: RHash) -> Result<PageConfig, Error>
         "\n\n<!-- PAGE {page_num} -->\n\n".to_string()
     };
 
+    let ranges = if let Some(val) = get_kw(ruby, hash, "ranges") {
+        Some(String::try_convert(val)?)
+    } else {
+        None
+    };
+
     let config = PageConfig {
         extract_pages,
         insert_page_markers,
         marker_format,
+        ranges,
     };
 
     Ok(config)
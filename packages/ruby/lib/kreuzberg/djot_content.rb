@@ -18,10 +18,11 @@ module Kreuzberg
 
       # Represents a formatted block in Djot content
       class FormattedBlock
-        attr_reader :block_type, :children, :attributes, :content, :level
+        attr_reader :block_type, :children, :attributes, :content, :level, :table_index
 
         # rubocop:disable Metrics/CyclomaticComplexity, Metrics/PerceivedComplexity
-        def initialize(hash_or_type = nil, children: nil, attributes: nil, content: nil, level: nil, block_type: nil)
+        def initialize(hash_or_type = nil, children: nil, attributes: nil, content: nil, level: nil, block_type: nil,
+                       table_index: nil)
           if hash_or_type.is_a?(Hash)
             # Initialize from hash
             @block_type = hash_or_type[:block_type] || hash_or_type['block_type'] || ''
@@ -29,6 +30,7 @@ module Kreuzberg
             @attributes = hash_or_type[:attributes] || hash_or_type['attributes'] || {}
             @content = hash_or_type[:content] || hash_or_type['content']
             @level = hash_or_type[:level] || hash_or_type['level']
+            @table_index = hash_or_type[:table_index] || hash_or_type['table_index']
           else
             # Initialize from keyword arguments (for backward compatibility)
             @block_type = block_type || hash_or_type || ''
@@ -36,6 +38,7 @@ module Kreuzberg
             @attributes = attributes || {}
             @content = content
             @level = level
+            @table_index = table_index
           end
         end
         # rubocop:enable Metrics/CyclomaticComplexity, Metrics/PerceivedComplexity
@@ -46,7 +49,8 @@ module Kreuzberg
             children: @children,
             attributes: @attributes,
             content: @content,
-            level: @level
+            level: @level,
+            table_index: @table_index
           }.compact
         end
       end
@@ -180,7 +184,8 @@ module Kreuzberg
           FormattedBlock.new(
             block_type: block['block_type'] || block[:block_type] || '',
             children: block['children'] || block[:children],
-            attributes: block['attributes'] || block[:attributes]
+            attributes: block['attributes'] || block[:attributes],
+            table_index: block['table_index'] || block[:table_index]
           )
         end
       end
@@ -159,6 +159,8 @@ export interface FormattedBlock {
 	blockType: BlockType;
 	/** Heading level (1-6) for headings, or nesting level for lists */
 	level?: number | null;
+	/** Index into the Djot content's tables for table blocks */
+	tableIndex?: number | null;
 	/** Text content for inline elements */
 	content?: string | null;
 	/** Child blocks for list items and containers */